@@ -0,0 +1,86 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	flag "github.com/spf13/pflag"
+)
+
+var safeShellArgRe = regexp.MustCompile(`^[\w@%+=:,./-]+$`)
+
+// quoteArgs joins the arguments together, quoting any that would otherwise be
+// split or interpreted by a shell
+func quoteArgs(args []string) string {
+	quoted := make([]string, 0, len(args))
+
+	for _, arg := range args {
+		if safeShellArgRe.MatchString(arg) {
+			quoted = append(quoted, arg)
+		} else {
+			quoted = append(quoted, "'"+strings.ReplaceAll(arg, "'", `'\''`)+"'")
+		}
+	}
+
+	return strings.Join(quoted, " ")
+}
+
+// forwardedFlags returns the flags that were explicitly set, in a form that
+// can be passed to another invocation of gh rr
+func forwardedFlags(cli *flag.FlagSet) []string {
+	var args []string
+
+	cli.Visit(func(f *flag.Flag) {
+		// tokens should not end up saved in plain text in the gh config
+		if f.Name == "token" {
+			return
+		}
+
+		if f.Value.Type() == "bool" {
+			if f.Value.String() == "true" {
+				args = append(args, "--"+f.Name)
+			} else {
+				args = append(args, "--"+f.Name+"=false")
+			}
+
+			return
+		}
+
+		// slices are formatted like [a,b] when stringified, so each value is
+		// given with its own flag instead
+		if slice, ok := f.Value.(flag.SliceValue); ok {
+			for _, value := range slice.GetSlice() {
+				args = append(args, "--"+f.Name, value)
+			}
+
+			return
+		}
+
+		args = append(args, "--"+f.Name, f.Value.String())
+	})
+
+	return args
+}
+
+// runAliasCommand creates a gh alias for invoking gh rr with the given flags
+func runAliasCommand(stdout, stderr io.Writer, ghExec ghExecutor, args []string, rrArgs []string) int {
+	if len(args) != 1 {
+		fmt.Fprintln(stderr, "please provide the name of the alias to create, along with the flags it should use")
+
+		return 1
+	}
+
+	expansion := strings.TrimSpace("rr " + quoteArgs(rrArgs))
+
+	if _, errMsg := ghExec("alias", "set", "--clobber", args[0], expansion); errMsg != "" {
+		fmt.Fprintf(stderr, "could not create alias: %s\n", strings.TrimSpace(errMsg))
+
+		return 1
+	}
+
+	fmt.Fprintf(stdout, "`gh %s` will now run `gh %s`\n", args[0], expansion)
+
+	return 0
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+func Test_run_Alias(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		args   []string
+		ghExec ghExecutor
+		exit   int
+	}{
+		{
+			name:   "when creating an alias for a group",
+			args:   []string{"alias", "rrs", "--from", "security"},
+			ghExec: expectCallToGh(t, "octocat/hello-world", "1"),
+			exit:   0,
+		},
+		{
+			name:   "when creating an alias with multiple flags",
+			args:   []string{"alias", "-gf", "security", "rrgs", "--profile", "my work", "--dry-run"},
+			ghExec: expectCallToGh(t, "octocat/hello-world", "1"),
+			exit:   0,
+		},
+		{
+			name:   "when creating an alias with a slice flag",
+			args:   []string{"alias", "rrx", "--except-team", "octo-org/release-freeze,octo-org/support"},
+			ghExec: expectCallToGh(t, "octocat/hello-world", "1"),
+			exit:   0,
+		},
+		{
+			name:   "when creating an alias without any flags",
+			args:   []string{"alias", "r"},
+			ghExec: expectCallToGh(t, "octocat/hello-world", "1"),
+			exit:   0,
+		},
+		{
+			name: "when gh fails",
+			args: []string{"alias", "rrs", "--from", "security"},
+			ghExec: func(_ ...string) (string, string) {
+				return "", "could not create alias: \"rrs\" is already a gh command"
+			},
+			exit: 1,
+		},
+		{
+			name:   "when no name is given",
+			args:   []string{"alias", "--from", "security"},
+			ghExec: expectNoCallToGh(t),
+			exit:   1,
+		},
+		{
+			name:   "when too many names are given",
+			args:   []string{"alias", "rrs", "rrt"},
+			ghExec: expectNoCallToGh(t),
+			exit:   1,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+
+			var ghExecArgs []string
+
+			got := run(tt.args, stdout, stderr, func(args ...string) (string, string) {
+				ghExecArgs = args
+
+				return tt.ghExec(args...)
+			})
+
+			if got != tt.exit {
+				t.Errorf("run() = %v, want %v", got, tt.exit)
+			}
+
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stdout))
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stderr))
+			snaps.MatchJSON(t, ghExecArgs)
+		})
+	}
+}
+
+func Test_run_Alias_SliceFlagsRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	var expansion string
+
+	got := run(
+		[]string{"alias", "rrx", "--except-team", "octo-org/release-freeze", "--except-team", "octo-org/support"},
+		&bytes.Buffer{},
+		&bytes.Buffer{},
+		func(args ...string) (string, string) {
+			expansion = args[len(args)-1]
+
+			return "", ""
+		},
+	)
+
+	if got != 0 {
+		t.Fatalf("run() = %v, want %v", got, 0)
+	}
+
+	// the alias expands to "rr <flags>", which should behave the same as the original flags
+	args := strings.Fields(strings.TrimPrefix(expansion, "rr "))
+
+	configDir := writeConfigFileInTempDir(t, dedent(t, `
+		repositories:
+			octocat/hello-world: [octocat, octodog, octopus]
+	`))
+
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+
+	var calls [][]string
+
+	got = run(
+		append([]string{"--config-dir", configDir, "--repo", "octocat/hello-world", "--dry-run"}, append(args, "123")...),
+		stdout,
+		stderr,
+		fakeTeamsGh(t, map[string]string{
+			"orgs/octo-org/teams/release-freeze/members?role=all": `[{"login":"octopus"}]`,
+			"orgs/octo-org/teams/support/members?role=all":        `[{"login":"octodog"}]`,
+		}, &calls),
+	)
+
+	if got != 0 {
+		t.Errorf("run() = %v, want %v", got, 0)
+	}
+
+	snaps.MatchSnapshot(t, expansion)
+	snaps.MatchSnapshot(t, normalizeStdStream(t, stdout))
+	snaps.MatchSnapshot(t, normalizeStdStream(t, stderr))
+	snaps.MatchJSON(t, calls)
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+)
+
+// decodePages decodes the output of `gh api --paginate` for an endpoint that
+// returns an array, which gh outputs as a separate array for each page
+func decodePages[T any](out string) ([]T, error) {
+	var all []T
+
+	decoder := json.NewDecoder(strings.NewReader(out))
+
+	for {
+		var page []T
+
+		if err := decoder.Decode(&page); err != nil {
+			if errors.Is(err, io.EOF) {
+				return all, nil
+			}
+
+			return nil, err
+		}
+
+		all = append(all, page...)
+	}
+}
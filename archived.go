@@ -0,0 +1,71 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// archivedBehavior controls what happens when requesting reviews on a pull
+// request in a repository that has been archived
+type archivedBehavior string
+
+const (
+	archivedWarn  archivedBehavior = "warn"
+	archivedError archivedBehavior = "error"
+)
+
+func (b *archivedBehavior) UnmarshalYAML(value *yaml.Node) error {
+	var behavior string
+
+	if err := value.Decode(&behavior); err != nil {
+		return err
+	}
+
+	switch archivedBehavior(behavior) {
+	case archivedWarn, archivedError:
+		*b = archivedBehavior(behavior)
+	default:
+		return fmt.Errorf("line %d: on_archived must be either warn or error, not `%s`", value.Line, behavior)
+	}
+
+	return nil
+}
+
+// isRepositoryArchived uses gh to check if the given repository, which can be
+// in the HOST/OWNER/REPO format, has been archived
+func isRepositoryArchived(ghExec ghExecutor, repo string) (bool, error) {
+	args := []string{"api"}
+	name := repo
+
+	if strings.Count(repo, "/") == 2 {
+		var host string
+
+		host, name, _ = strings.Cut(repo, "/")
+		args = append(args, "--hostname", host)
+	}
+
+	out, errMsg := ghExec(append(args, "repos/"+name, "--jq", ".archived")...)
+
+	if errMsg != "" {
+		return false, fmt.Errorf("could not check if %s is archived: %s", repo, strings.TrimSpace(errMsg))
+	}
+
+	return strings.TrimSpace(out) == "true", nil
+}
+
+// reportArchived outputs that the repository is archived, returning the exit
+// code that should be used based on the configured behavior
+func reportArchived(stderr io.Writer, behavior archivedBehavior, repo string) int {
+	if behavior == archivedWarn {
+		fmt.Fprintf(stderr, "warning: not requesting reviews as %s is archived\n", repo)
+
+		return 0
+	}
+
+	fmt.Fprintf(stderr, "%s is archived, so reviews cannot be requested on its pull requests\n", repo)
+
+	return 1
+}
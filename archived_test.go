@@ -0,0 +1,137 @@
+package main
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+// fakeArchivedGh acts as gh with the given repositories being archived, with
+// any repositories named "missing" not existing
+func fakeArchivedGh(t *testing.T, archived []string, calls *[][]string) ghExecutor {
+	t.Helper()
+
+	return func(args ...string) (string, string) {
+		t.Helper()
+
+		*calls = append(*calls, args)
+
+		i := slices.IndexFunc(args, func(arg string) bool { return strings.HasPrefix(arg, "repos/") })
+
+		switch {
+		case len(args) > 0 && args[0] == "api" && i != -1:
+			repo := strings.TrimPrefix(args[i], "repos/")
+
+			if strings.HasSuffix(repo, "/missing") {
+				return "", "gh: Not Found (HTTP 404)"
+			}
+
+			if slices.Contains(archived, repo) {
+				return "true\n", ""
+			}
+
+			return "false\n", ""
+		case len(args) > 2 && args[0] == "pr" && args[1] == "edit":
+			return "https://github.com/octocat/hello-world/pull/" + args[2], ""
+		}
+
+		t.Errorf("unexpected call to gh: %v", args)
+
+		return "", ""
+	}
+}
+
+func Test_run_WithArchivedRepositories(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		args     []string
+		settings string
+		archived []string
+		exit     int
+	}{
+		{
+			name:     "when the repository is not archived",
+			args:     []string{"--repo", "octocat/hello-world", "123"},
+			settings: "on_archived: error",
+			exit:     0,
+		},
+		{
+			name:     "when the repository is archived",
+			args:     []string{"--repo", "octocat/hello-world", "123"},
+			settings: "on_archived: error",
+			archived: []string{"octocat/hello-world"},
+			exit:     1,
+		},
+		{
+			name:     "when the repository is archived and only warning",
+			args:     []string{"--repo", "octocat/hello-world", "123"},
+			settings: "on_archived: warn",
+			archived: []string{"octocat/hello-world"},
+			exit:     0,
+		},
+		{
+			name:     "when the repository is archived and not checking",
+			args:     []string{"--repo", "octocat/hello-world", "123"},
+			archived: []string{"octocat/hello-world"},
+			exit:     0,
+		},
+		{
+			name:     "when the repository is on another host",
+			args:     []string{"--repo", "github.example.com/octocat/hello-world", "123"},
+			settings: "on_archived: error",
+			archived: []string{"octocat/hello-world"},
+			exit:     1,
+		},
+		{
+			name:     "when the repository cannot be checked",
+			args:     []string{"--repo", "octocat/missing", "123"},
+			settings: "on_archived: error",
+			exit:     1,
+		},
+		{
+			name:     "when the behavior is not known",
+			args:     []string{"--repo", "octocat/hello-world", "123"},
+			settings: "on_archived: ignore",
+			exit:     1,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			configDir := writeConfigFileInTempDir(t, dedent(t, `
+				settings:
+					`+tt.settings+`
+				repositories:
+					'*':
+						default: [octocat]
+			`))
+
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+
+			var calls [][]string
+
+			got := run(
+				append([]string{"--config-dir", configDir, "-g"}, tt.args...),
+				stdout,
+				stderr,
+				fakeArchivedGh(t, tt.archived, &calls),
+			)
+
+			if got != tt.exit {
+				t.Errorf("run() = %v, want %v", got, tt.exit)
+			}
+
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stdout))
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stderr))
+			snaps.MatchJSON(t, calls)
+		})
+	}
+}
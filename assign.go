@@ -0,0 +1,143 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+type assignIssuesOptions struct {
+	repo       string
+	groupRepo  string
+	group      string
+	reviewers  []reviewer
+	issues     []string
+	sweepLabel string
+	statePath  string
+	stateGist  string
+	isDryRun   bool
+}
+
+// roundRobinKey returns the key used to track whose turn it is within a group
+func roundRobinKey(repo, group string) string {
+	return strings.ToLower(repo) + "#" + group
+}
+
+// findIssuesToSweep returns the numbers of the open issues in the repository
+// that have the given label and are not assigned to anyone
+func findIssuesToSweep(ghExec ghExecutor, repo, label string) ([]string, error) {
+	out, errMsg := ghExec(
+		"issue", "list",
+		"--repo", repo,
+		"--label", label,
+		"--search", "no:assignee",
+		"--state", "open",
+		"--json", "number",
+	)
+
+	if errMsg != "" {
+		return nil, fmt.Errorf("could not list issues: %s", strings.TrimSpace(errMsg))
+	}
+
+	var issues []struct {
+		Number int `json:"number"`
+	}
+
+	if err := json.Unmarshal([]byte(out), &issues); err != nil {
+		return nil, fmt.Errorf("could not parse issues: %w", err)
+	}
+
+	numbers := make([]string, 0, len(issues))
+
+	for _, issue := range issues {
+		numbers = append(numbers, strconv.Itoa(issue.Number))
+	}
+
+	return numbers, nil
+}
+
+// assignIssues assigns each issue to the next member of the group, taking
+// turns across runs by tracking who was assigned last in the state file, or in
+// a gist if one has been configured for syncing state between machines
+func assignIssues(stdout, stderr io.Writer, ghExec ghExecutor, opts assignIssuesOptions) int {
+	if len(opts.reviewers) == 0 {
+		fmt.Fprintf(stderr, "the %s group for %s does not have anyone in it\n", opts.group, opts.repo)
+
+		return 1
+	}
+
+	issues := opts.issues
+
+	if opts.sweepLabel != "" {
+		swept, err := findIssuesToSweep(ghExec, opts.repo, opts.sweepLabel)
+
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+
+			return 1
+		}
+
+		if len(swept) == 0 {
+			fmt.Fprintf(stdout, "there are no unassigned issues labelled %s\n", opts.sweepLabel)
+
+			return 0
+		}
+
+		issues = append(issues, swept...)
+	}
+
+	if len(issues) == 0 {
+		fmt.Fprintln(stderr, "please provide the issues to assign, or use --sweep to assign all issues with a label")
+
+		return 1
+	}
+
+	st, err := readState(ghExec, opts.statePath, opts.stateGist)
+
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+
+		return 1
+	}
+
+	key := roundRobinKey(opts.groupRepo, opts.group)
+	next := st.RoundRobin[key]
+	exit := 0
+
+	for _, issue := range issues {
+		assignee := opts.reviewers[next%len(opts.reviewers)]
+
+		if opts.isDryRun {
+			fmt.Fprintf(stdout, "would have assigned issue %s to %s\n", issue, assignee)
+		} else {
+			url, errMsg := ghExec("issue", "edit", issue, "--repo", opts.repo, "--add-assignee", assignee.Handle)
+
+			if errMsg != "" {
+				fmt.Fprintf(stderr, "could not assign issue %s: %s\n", issue, strings.TrimSpace(errMsg))
+				exit = 1
+
+				break
+			}
+
+			fmt.Fprintf(stdout, "assigned %s to %s\n", url, assignee)
+		}
+
+		next++
+	}
+
+	if opts.isDryRun {
+		return exit
+	}
+
+	st.RoundRobin[key] = next % len(opts.reviewers)
+
+	if err := writeState(ghExec, opts.statePath, opts.stateGist, st); err != nil {
+		fmt.Fprintf(stderr, "could not save state: %v\n", err)
+
+		return 1
+	}
+
+	return exit
+}
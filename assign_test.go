@@ -0,0 +1,279 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+// fakeIssuesGh acts as gh for the issue commands used when assigning issues
+func fakeIssuesGh(t *testing.T, issues string, calls *[][]string) ghExecutor {
+	t.Helper()
+
+	return func(args ...string) (string, string) {
+		t.Helper()
+
+		*calls = append(*calls, args)
+
+		switch {
+		case len(args) > 1 && args[0] == "issue" && args[1] == "list":
+			return issues, ""
+		case len(args) > 2 && args[0] == "issue" && args[1] == "edit":
+			if args[2] == "13" {
+				return "", "GraphQL: Could not resolve to an issue or pull request with the number of 13."
+			}
+
+			return fmt.Sprintf("https://github.com/octocat/hello-world/issues/%s", args[2]), ""
+		}
+
+		t.Errorf("unexpected call to gh: %v", args)
+
+		return "", ""
+	}
+}
+
+func Test_run_AssignIssues(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		args   []string
+		state  string
+		issues string
+		exit   int
+	}{
+		{
+			name:  "when assigning a single issue",
+			args:  []string{"assign-issues", "--from", "triage", "1"},
+			state: "",
+			exit:  0,
+		},
+		{
+			name:  "when assigning multiple issues",
+			args:  []string{"assign-issues", "--from", "triage", "1", "2", "3", "4"},
+			state: "",
+			exit:  0,
+		},
+		{
+			name:  "when it is someone else's turn",
+			args:  []string{"assign-issues", "--from", "triage", "1", "2"},
+			state: `{"roundRobin": {"octocat/hello-world#triage": 2, "octocat/hello-world#default": 1}}`,
+			exit:  0,
+		},
+		{
+			name:  "when using a global group",
+			args:  []string{"assign-issues", "-gf", "triage", "1"},
+			state: `{"roundRobin": {"octocat/hello-world#triage": 2, "*#triage": 1}}`,
+			exit:  0,
+		},
+		{
+			name:   "when sweeping issues with a label",
+			args:   []string{"assign-issues", "--from", "triage", "--sweep", "bug"},
+			state:  `{"roundRobin": {"octocat/hello-world#triage": 1}}`,
+			issues: `[{"number":7},{"number":9}]`,
+			exit:   0,
+		},
+		{
+			name:   "when sweeping and there are no issues",
+			args:   []string{"assign-issues", "--from", "triage", "--sweep", "bug"},
+			state:  "",
+			issues: `[]`,
+			exit:   0,
+		},
+		{
+			name:  "when doing a dry-run",
+			args:  []string{"assign-issues", "--from", "triage", "--dry-run", "1", "2"},
+			state: `{"roundRobin": {"octocat/hello-world#triage": 1}}`,
+			exit:  0,
+		},
+		{
+			name:  "when an issue cannot be assigned",
+			args:  []string{"assign-issues", "--from", "triage", "12", "13", "14"},
+			state: "",
+			exit:  1,
+		},
+		{
+			name:  "when no issues are given",
+			args:  []string{"assign-issues", "--from", "triage"},
+			state: "",
+			exit:  1,
+		},
+		{
+			name:  "when the group is empty",
+			args:  []string{"assign-issues", "--from", "empty", "1"},
+			state: "",
+			exit:  1,
+		},
+		{
+			name:  "when the group does not exist",
+			args:  []string{"assign-issues", "--from", "nope", "1"},
+			state: "",
+			exit:  1,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			configDir := writeConfigFileInTempDir(t, dedent(t, `
+				repositories:
+					'*':
+						triage: [octocat, octopus]
+					octocat/hello-world:
+						triage: [octodog, octopus, octocat]
+						empty: []
+			`))
+
+			statePath := stateFilePath(configDir)
+
+			if tt.state != "" {
+				if err := os.WriteFile(statePath, []byte(tt.state), 0600); err != nil {
+					t.Fatalf("could not write state: %v", err)
+				}
+			}
+
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+
+			var calls [][]string
+
+			got := run(
+				append([]string{"--config-dir", configDir, "--repo", "octocat/hello-world"}, tt.args...),
+				stdout,
+				stderr,
+				fakeIssuesGh(t, tt.issues, &calls),
+			)
+
+			if got != tt.exit {
+				t.Errorf("run() = %v, want %v", got, tt.exit)
+			}
+
+			st, _ := os.ReadFile(filepath.Clean(statePath))
+
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stdout))
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stderr))
+			snaps.MatchJSON(t, calls)
+			snaps.MatchSnapshot(t, strings.TrimSpace(string(st)))
+		})
+	}
+}
+
+// fakeGistStateGh acts as gh for the issue commands used when assigning issues,
+// along with the gist api that state is synced through
+func fakeGistStateGh(t *testing.T, content string, calls *[][]string) ghExecutor {
+	t.Helper()
+
+	issuesGh := fakeIssuesGh(t, "", calls)
+
+	return func(args ...string) (string, string) {
+		t.Helper()
+
+		if len(args) > 1 && args[0] == "api" {
+			*calls = append(*calls, args)
+
+			if strings.Contains(strings.Join(args, " "), "gists/missing") {
+				return "", "gh: Not Found (HTTP 404)"
+			}
+
+			if args[1] == "-X" {
+				return "{}", ""
+			}
+
+			return content, ""
+		}
+
+		return issuesGh(args...)
+	}
+}
+
+func Test_run_AssignIssuesWithStateGist(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		args    []string
+		gist    string
+		content string
+		exit    int
+	}{
+		{
+			name:    "when the gist does not have any state yet",
+			args:    []string{"assign-issues", "--from", "triage", "1", "2"},
+			gist:    "abc123",
+			content: "",
+			exit:    0,
+		},
+		{
+			name:    "when the gist has state",
+			args:    []string{"assign-issues", "--from", "triage", "1"},
+			gist:    "abc123",
+			content: `{"roundRobin": {"octocat/hello-world#triage": 2}}`,
+			exit:    0,
+		},
+		{
+			name:    "when doing a dry-run",
+			args:    []string{"assign-issues", "--from", "triage", "--dry-run", "1"},
+			gist:    "abc123",
+			content: `{"roundRobin": {"octocat/hello-world#triage": 1}}`,
+			exit:    0,
+		},
+		{
+			name:    "when the gist state is invalid",
+			args:    []string{"assign-issues", "--from", "triage", "1"},
+			gist:    "abc123",
+			content: `{"roundRobin": [}`,
+			exit:    1,
+		},
+		{
+			name:    "when the gist does not exist",
+			args:    []string{"assign-issues", "--from", "triage", "1"},
+			gist:    "missing",
+			content: "",
+			exit:    1,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			configDir := writeConfigFileInTempDir(t, dedent(t, fmt.Sprintf(`
+				settings:
+					state_gist: %s
+				repositories:
+					octocat/hello-world:
+						triage: [octodog, octopus, octocat]
+			`, tt.gist)))
+
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+
+			var calls [][]string
+
+			got := run(
+				append([]string{"--config-dir", configDir, "--repo", "octocat/hello-world"}, tt.args...),
+				stdout,
+				stderr,
+				fakeGistStateGh(t, tt.content, &calls),
+			)
+
+			if got != tt.exit {
+				t.Errorf("run() = %v, want %v", got, tt.exit)
+			}
+
+			if _, err := os.Stat(stateFilePath(configDir)); err == nil {
+				t.Errorf("expected state to not be saved locally when using a gist")
+			}
+
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stdout))
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stderr))
+			snaps.MatchJSON(t, calls)
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"path"
+	"strings"
+)
+
+// authorRule routes pull requests made by matching authors (typically bots
+// like dependabot) to a specific group, or skips requesting reviews entirely
+type authorRule struct {
+	Match []string `yaml:"match"`
+	Group string   `yaml:"group"`
+	Skip  bool     `yaml:"skip"`
+}
+
+// normalizeLogin converts the "app/<name>" format that gh uses for the logins
+// of apps into the "<name>[bot]" format that is used by GitHub elsewhere
+func normalizeLogin(login string) string {
+	if name, ok := strings.CutPrefix(login, "app/"); ok {
+		return name + "[bot]"
+	}
+
+	return login
+}
+
+// matchesLogin checks if the login matches any of the patterns, ignoring case;
+// square brackets are treated literally since they're used in bot logins
+func matchesLogin(patterns []string, login string) bool {
+	replacer := strings.NewReplacer("[", `\[`, "]", `\]`)
+
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(replacer.Replace(strings.ToLower(pattern)), strings.ToLower(login)); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// findAuthorRule returns the first rule that matches the given author
+func findAuthorRule(rules []authorRule, author string) (authorRule, bool) {
+	for _, rule := range rules {
+		if matchesLogin(rule.Match, author) {
+			return rule, true
+		}
+	}
+
+	return authorRule{}, false
+}
+
+// fetchPullRequestAuthor uses gh to determine who authored the target pull request
+func fetchPullRequestAuthor(ghExec ghExecutor, repo, target string) (string, error) {
+	pr, err := fetchPullRequest(ghExec, repo, target, "author")
+
+	if err != nil {
+		return "", err
+	}
+
+	return normalizeLogin(pr.Author.Login), nil
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+// fakeAuthoredPrGh acts as gh for a pull request that was authored by the given login
+func fakeAuthoredPrGh(t *testing.T, author string, calls *[][]string) ghExecutor {
+	t.Helper()
+
+	return func(args ...string) (string, string) {
+		t.Helper()
+
+		*calls = append(*calls, args)
+
+		switch {
+		case len(args) > 1 && args[0] == "pr" && args[1] == "view":
+			if author == "" {
+				return "", "no pull requests found for branch \"main\""
+			}
+
+			return fmt.Sprintf(`{"author":{"login":%q}}`, author), ""
+		case len(args) > 1 && args[0] == "pr" && args[1] == "edit":
+			return "https://github.com/octocat/hello-world/pull/123", ""
+		}
+
+		t.Errorf("unexpected call to gh: %v", args)
+
+		return "", ""
+	}
+}
+
+func Test_run_AuthorRules(t *testing.T) {
+	t.Parallel()
+
+	config := `
+		authors:
+			- match: ['dependabot[bot]', 'renovate[bot]']
+				group: deps
+			- match: ['*[bot]']
+				skip: true
+			- match: [octopus]
+		repositories:
+			octocat/hello-world:
+				default: [octocat, octopus]
+				deps: [octodog]
+				infra: [octopus]
+	`
+
+	tests := []struct {
+		name   string
+		args   []string
+		author string
+		exit   int
+	}{
+		{
+			name:   "when the author matches a rule with a group",
+			args:   []string{"123"},
+			author: "app/dependabot",
+			exit:   0,
+		},
+		{
+			name:   "when the author matches a rule with a group using the rest api format",
+			args:   []string{"123"},
+			author: "Renovate[bot]",
+			exit:   0,
+		},
+		{
+			name:   "when the author matches a rule that skips",
+			args:   []string{"123"},
+			author: "app/github-actions",
+			exit:   0,
+		},
+		{
+			name:   "when the author matches a rule without a group",
+			args:   []string{"123"},
+			author: "octopus",
+			exit:   0,
+		},
+		{
+			name:   "when the author does not match any rules",
+			args:   []string{"123"},
+			author: "octocat",
+			exit:   0,
+		},
+		{
+			name:   "when a group is explicitly requested",
+			args:   []string{"--from", "infra", "123"},
+			author: "app/dependabot",
+			exit:   0,
+		},
+		{
+			name:   "when the author cannot be determined",
+			args:   []string{},
+			author: "",
+			exit:   1,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			configDir := writeConfigFileInTempDir(t, dedent(t, config))
+
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+
+			var calls [][]string
+
+			got := run(
+				append([]string{"--config-dir", configDir, "--repo", "octocat/hello-world"}, tt.args...),
+				stdout,
+				stderr,
+				fakeAuthoredPrGh(t, tt.author, &calls),
+			)
+
+			if got != tt.exit {
+				t.Errorf("run() = %v, want %v", got, tt.exit)
+			}
+
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stdout))
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stderr))
+			snaps.MatchJSON(t, calls)
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// prCheck is a check on a pull request as returned by `gh pr checks --json`
+type prCheck struct {
+	Name   string `json:"name"`
+	Bucket string `json:"bucket"`
+}
+
+// fetchChecks returns the checks of the target pull request
+func fetchChecks(ghExec ghExecutor, repo, target string) ([]prCheck, error) {
+	args := []string{"pr", "checks"}
+
+	if target != "" {
+		args = append(args, target)
+	}
+
+	out, errMsg := ghExec(append(args, "--repo", repo, "--json", "name,bucket")...)
+
+	if errMsg != "" {
+		// pull requests without any checks have nothing that can fail
+		if strings.Contains(errMsg, "no checks reported") {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("could not get the checks of the pull request: %s", strings.TrimSpace(errMsg))
+	}
+
+	var checks []prCheck
+
+	if err := json.Unmarshal([]byte(out), &checks); err != nil {
+		return nil, fmt.Errorf("could not parse the checks of the pull request: %w", err)
+	}
+
+	return checks, nil
+}
+
+// summarizeChecks returns the names of the checks which are still running and
+// which have failed
+func summarizeChecks(checks []prCheck) (pending []string, failed []string) {
+	for _, check := range checks {
+		switch check.Bucket {
+		case "pending":
+			pending = append(pending, check.Name)
+		case "fail", "cancel":
+			failed = append(failed, check.Name)
+		}
+	}
+
+	return pending, failed
+}
+
+type checksOptions struct {
+	repo     string
+	target   string
+	wait     bool
+	timeout  time.Duration
+	interval time.Duration
+}
+
+// gateOnChecks returns an error unless every check on the pull request has
+// passed, optionally waiting for any that are still running to finish
+func gateOnChecks(stderr io.Writer, ghExec ghExecutor, opts checksOptions) error {
+	deadline := time.Now().Add(opts.timeout)
+	waiting := false
+
+	for {
+		checks, err := fetchChecks(ghExec, opts.repo, opts.target)
+
+		if err != nil {
+			return err
+		}
+
+		pending, failed := summarizeChecks(checks)
+
+		if len(failed) > 0 {
+			return fmt.Errorf("not requesting reviews as some checks have failed: %s", strings.Join(failed, ", "))
+		}
+
+		if len(pending) == 0 {
+			return nil
+		}
+
+		if !opts.wait {
+			return fmt.Errorf("not requesting reviews as some checks are still running: %s", strings.Join(pending, ", "))
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("timed out waiting for checks to finish: %s", strings.Join(pending, ", "))
+		}
+
+		if !waiting {
+			fmt.Fprintf(stderr, "waiting for checks to finish: %s\n", strings.Join(pending, ", "))
+			waiting = true
+		}
+
+		time.Sleep(opts.interval)
+	}
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+// fakeChecksGh acts as gh for a pull request whose checks are reported as each
+// of the given responses in turn, repeating the last one once exhausted
+func fakeChecksGh(t *testing.T, responses []string, calls *[][]string) ghExecutor {
+	t.Helper()
+
+	checked := 0
+
+	return func(args ...string) (string, string) {
+		t.Helper()
+
+		*calls = append(*calls, args)
+
+		switch {
+		case len(args) > 1 && args[0] == "pr" && args[1] == "checks":
+			response := responses[min(checked, len(responses)-1)]
+			checked++
+
+			if response == "" {
+				return "", "no checks reported on the 'main' branch"
+			}
+
+			return response, ""
+		case len(args) > 1 && args[0] == "pr" && args[1] == "edit":
+			return "https://github.com/octocat/hello-world/pull/123", ""
+		}
+
+		t.Errorf("unexpected call to gh: %v", args)
+
+		return "", ""
+	}
+}
+
+func Test_run_Checks(t *testing.T) {
+	t.Parallel()
+
+	passed := `[{"name":"build","bucket":"pass"},{"name":"lint","bucket":"skipping"}]`
+	pending := `[{"name":"build","bucket":"pending"},{"name":"lint","bucket":"pass"}]`
+	failed := `[{"name":"build","bucket":"fail"},{"name":"lint","bucket":"cancel"}]`
+
+	tests := []struct {
+		name      string
+		args      []string
+		responses []string
+		exit      int
+	}{
+		{
+			name:      "when requiring checks that have passed",
+			args:      []string{"--require-checks", "123"},
+			responses: []string{passed},
+			exit:      0,
+		},
+		{
+			name:      "when requiring checks that are still running",
+			args:      []string{"--require-checks", "123"},
+			responses: []string{pending},
+			exit:      1,
+		},
+		{
+			name:      "when requiring checks that have failed",
+			args:      []string{"--require-checks", "123"},
+			responses: []string{failed},
+			exit:      1,
+		},
+		{
+			name:      "when requiring checks on a pull request without any",
+			args:      []string{"--require-checks"},
+			responses: []string{""},
+			exit:      0,
+		},
+		{
+			name:      "when waiting for checks that pass",
+			args:      []string{"--wait-checks", "--checks-interval", "1ms", "123"},
+			responses: []string{pending, pending, passed},
+			exit:      0,
+		},
+		{
+			name:      "when waiting for checks that fail",
+			args:      []string{"--wait-checks", "--checks-interval", "1ms", "123"},
+			responses: []string{pending, failed},
+			exit:      1,
+		},
+		{
+			name:      "when waiting for checks times out",
+			args:      []string{"--wait-checks", "--checks-interval", "1ms", "--checks-timeout", "0s", "123"},
+			responses: []string{pending},
+			exit:      1,
+		},
+		{
+			name:      "when doing a dry-run",
+			args:      []string{"--dry-run", "--require-checks", "123"},
+			responses: []string{failed},
+			exit:      0,
+		},
+		{
+			name:      "when both waiting for and requiring checks",
+			args:      []string{"--wait-checks", "--require-checks", "123"},
+			responses: []string{passed},
+			exit:      1,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			configDir := writeConfigFileInTempDir(t, dedent(t, `
+				repositories:
+					octocat/hello-world:
+						- octocat
+						- octopus
+			`))
+
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+
+			var calls [][]string
+
+			got := run(
+				append([]string{"--config-dir", configDir, "--repo", "octocat/hello-world"}, tt.args...),
+				stdout,
+				stderr,
+				fakeChecksGh(t, tt.responses, &calls),
+			)
+
+			if got != tt.exit {
+				t.Errorf("run() = %v, want %v", got, tt.exit)
+			}
+
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stdout))
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stderr))
+			snaps.MatchJSON(t, calls)
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package main
+
+// maxReviewersPerEdit is the most reviewers that are added to a pull request
+// with a single call to gh, as GitHub limits how many can be requested at once
+const maxReviewersPerEdit = 15
+
+// maxHandlesLengthPerEdit is the most characters of handles that are passed to
+// a single call to gh, to stay well within the limits that operating systems
+// place on the length of command lines
+const maxHandlesLengthPerEdit = 4096
+
+// chunkReviewers splits the reviewers into chunks that are small enough to be
+// added to a pull request with a single call to gh; there is always at least
+// one chunk, even if there are no reviewers
+func chunkReviewers(reviewers []reviewer) [][]reviewer {
+	var chunks [][]reviewer
+	var chunk []reviewer
+
+	length := 0
+
+	for _, r := range reviewers {
+		if len(chunk) > 0 && (len(chunk) == maxReviewersPerEdit || length+len(r.Handle) > maxHandlesLengthPerEdit) {
+			chunks = append(chunks, chunk)
+			chunk = nil
+			length = 0
+		}
+
+		chunk = append(chunk, r)
+		length += len(r.Handle)
+	}
+
+	return append(chunks, chunk)
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+// fakeChunkedGh acts as gh for editing pull requests, failing if any of the
+// reviewers being added are "octo-broken"
+func fakeChunkedGh(t *testing.T, calls *[][]string) ghExecutor {
+	t.Helper()
+
+	return func(args ...string) (string, string) {
+		t.Helper()
+
+		*calls = append(*calls, args)
+
+		if len(args) > 2 && args[0] == "pr" && args[1] == "edit" {
+			if strings.Contains(strings.Join(args, " "), "octo-broken") {
+				return "", "GraphQL: Could not resolve to a User with the login of 'octo-broken'."
+			}
+
+			return fmt.Sprintf("https://github.com/octocat/hello-world/pull/%s", args[2]), ""
+		}
+
+		t.Errorf("unexpected call to gh: %v", args)
+
+		return "", ""
+	}
+}
+
+// manyReviewers returns a yaml list of the given number of reviewers
+func manyReviewers(count int, extra ...string) string {
+	handles := make([]string, 0, count+len(extra))
+
+	for i := 1; i <= count; i++ {
+		handles = append(handles, fmt.Sprintf("octocat-%d", i))
+	}
+
+	return "[" + strings.Join(append(handles, extra...), ", ") + "]"
+}
+
+func Test_run_WithManyReviewers(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		args  []string
+		group string
+		exit  int
+	}{
+		{
+			name:  "when the group fits in a single call",
+			args:  []string{"123"},
+			group: manyReviewers(15),
+			exit:  0,
+		},
+		{
+			name:  "when the group needs multiple calls",
+			args:  []string{"123"},
+			group: manyReviewers(32),
+			exit:  0,
+		},
+		{
+			name:  "when the request is urgent",
+			args:  []string{"--urgent", "123"},
+			group: manyReviewers(20),
+			exit:  0,
+		},
+		{
+			name:  "when the first call fails",
+			args:  []string{"123"},
+			group: manyReviewers(3, "octo-broken"),
+			exit:  1,
+		},
+		{
+			name:  "when a later call fails",
+			args:  []string{"123"},
+			group: manyReviewers(16, "octo-broken"),
+			exit:  1,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			configDir := writeConfigFileInTempDir(t, dedent(t, `
+				repositories:
+					octocat/hello-world:
+						default: `+tt.group+`
+			`))
+
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+
+			var calls [][]string
+
+			got := run(
+				append([]string{"--config-dir", configDir, "--repo", "octocat/hello-world"}, tt.args...),
+				stdout,
+				stderr,
+				fakeChunkedGh(t, &calls),
+			)
+
+			if got != tt.exit {
+				t.Errorf("run() = %v, want %v", got, tt.exit)
+			}
+
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stdout))
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stderr))
+			snaps.MatchJSON(t, calls)
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	flag "github.com/spf13/pflag"
+)
+
+// completionCommand is the hidden command used by shells to complete arguments,
+// following the same protocol as cobra (which is what gh uses for completions)
+const completionCommand = "__complete"
+
+// directives telling the shell what to do after the candidates are given,
+// using the same values as cobra
+const (
+	completeDefault = 0
+	completeNoFiles = 4
+)
+
+// lookupFlag returns the flag that the given argument is, if any, using the
+// last shorthand when multiple are combined like -gf
+func lookupFlag(cli *flag.FlagSet, arg string) *flag.Flag {
+	if strings.HasPrefix(arg, "--") {
+		if strings.Contains(arg, "=") {
+			return nil
+		}
+
+		return cli.Lookup(arg[2:])
+	}
+
+	if len(arg) > 1 && arg[0] == '-' {
+		return cli.ShorthandLookup(arg[len(arg)-1:])
+	}
+
+	return nil
+}
+
+// completionGroupNames returns the name of every group in the config that
+// would be used, so that they can be completed for --from
+func completionGroupNames(cli *flag.FlagSet) []string {
+	configDir := cli.Lookup("config-dir").Value.String()
+	configFile := cli.Lookup("config").Value.String()
+
+	personalPaths, defaultPath := personalConfigPaths(configDir, cli.Changed("config-dir"))
+	personalPath := findPersonalConfig(personalPaths, defaultPath)
+
+	conf, _, err := parseLayeredConfig(personalPath, findLocalSharedConfig(), configFile)
+
+	if err != nil {
+		return nil
+	}
+
+	names := map[string]bool{}
+
+	for _, repos := range []repositories{conf.Repositories, conf.Owners} {
+		for _, groups := range repos {
+			for name := range groups {
+				names[name] = true
+			}
+		}
+	}
+
+	return sortedNames(names)
+}
+
+// completeFlagValue returns the candidates for the value of the given flag
+func completeFlagValue(cli *flag.FlagSet, f *flag.Flag) ([]string, int) {
+	switch f.Name {
+	case "from":
+		return completionGroupNames(cli), completeNoFiles
+	case "format":
+		return []string{"text", "csv", "json"}, completeNoFiles
+	case "order":
+		return []string{"config", "alphabetical", "shuffle"}, completeNoFiles
+	case "config", "config-dir", "record", "replay", "gh-path":
+		return nil, completeDefault
+	}
+
+	return nil, completeNoFiles
+}
+
+// completionCandidates returns what the argument being completed could be,
+// based on the arguments that come before it
+func completionCandidates(cli *flag.FlagSet, args []string, toComplete string) ([]string, int) {
+	if len(args) > 0 {
+		if f := lookupFlag(cli, args[len(args)-1]); f != nil && f.NoOptDefVal == "" {
+			return completeFlagValue(cli, f)
+		}
+	}
+
+	if strings.HasPrefix(toComplete, "-") {
+		var candidates []string
+
+		cli.VisitAll(func(f *flag.Flag) {
+			if !f.Hidden {
+				candidates = append(candidates, "--"+f.Name+"\t"+f.Usage)
+			}
+		})
+
+		return candidates, completeNoFiles
+	}
+
+	if cli.NArg() == 0 {
+		return commands, completeNoFiles
+	}
+
+	return nil, completeNoFiles
+}
+
+// runCompletion outputs the candidates for the last of the given arguments,
+// followed by the directive for the shell
+func runCompletion(stdout io.Writer, cli *flag.FlagSet, args []string) int {
+	toComplete := ""
+
+	if len(args) > 0 {
+		toComplete = args[len(args)-1]
+		args = args[:len(args)-1]
+	}
+
+	// the arguments are incomplete, so the flags are parsed as best as possible
+	cli.SetOutput(io.Discard)
+	_ = cli.Parse(args)
+
+	candidates, directive := completionCandidates(cli, args, toComplete)
+
+	for _, candidate := range candidates {
+		if strings.HasPrefix(candidate, toComplete) {
+			fmt.Fprintln(stdout, candidate)
+		}
+	}
+
+	fmt.Fprintf(stdout, ":%d\n", directive)
+
+	return 0
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+func Test_run_Completion(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{
+			name: "when completing a command",
+			args: []string{"__complete", "o"},
+		},
+		{
+			name: "when completing after a command",
+			args: []string{"__complete", "groups", ""},
+		},
+		{
+			name: "when completing a flag",
+			args: []string{"__complete", "--dry"},
+		},
+		{
+			name: "when completing a group",
+			args: []string{"__complete", "--from", "s"},
+		},
+		{
+			name: "when completing a group with a shorthand",
+			args: []string{"__complete", "-gf", ""},
+		},
+		{
+			name: "when completing a flag with fixed values",
+			args: []string{"__complete", "--order", ""},
+		},
+		{
+			name: "when completing a flag that takes a file",
+			args: []string{"__complete", "--config", ""},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			configDir := writeConfigFileInTempDir(t, dedent(t, `
+				owners:
+					octocat:
+						support: [octopus]
+				repositories:
+					'*':
+						security: [octokitten]
+					octocat/hello-world:
+						default: [octocat]
+						sre: [octodog]
+			`))
+
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+
+			got := run(
+				append(tt.args[:1:1], append([]string{"--config-dir", configDir}, tt.args[1:]...)...),
+				stdout,
+				stderr,
+				expectNoCallToGh(t),
+			)
+
+			if got != 0 {
+				t.Errorf("run() = %v, want %v", got, 0)
+			}
+
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stdout))
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stderr))
+		})
+	}
+}
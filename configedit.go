@@ -0,0 +1,261 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+var errConfigEncrypted = errors.New("config is encrypted")
+var errConfigIsJSON = errors.New("config is json")
+
+// groupLocation identifies a group within the config file
+type groupLocation struct {
+	profile string
+	host    string
+	repo    string
+	group   string
+
+	// isOwner is whether the group is one of the owner defaults, in which case
+	// repo is the name of the owner
+	isOwner bool
+}
+
+func (l groupLocation) String() string {
+	repo := l.repo
+
+	if repo == "*" {
+		repo = "global"
+	}
+
+	details := []string{l.group}
+
+	if l.isOwner {
+		details = append(details, "owner")
+	}
+
+	if l.host != "" {
+		details = append(details, l.host)
+	}
+
+	if l.profile != "" {
+		details = append(details, l.profile+" profile")
+	}
+
+	return fmt.Sprintf("%s (%s)", repo, strings.Join(details, ", "))
+}
+
+// loadConfigDocument reads the config file as a yaml document, so that it can
+// be edited without losing any comments or formatting
+func loadConfigDocument(file string) (*yaml.Node, []byte, error) {
+	out, err := os.ReadFile(file)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if isSopsEncrypted(out) {
+		return nil, nil, errConfigEncrypted
+	}
+
+	// json configs are typically generated, and would be written back as yaml
+	if isJSONConfig(file) {
+		return nil, nil, errConfigIsJSON
+	}
+
+	var doc yaml.Node
+
+	if err := yaml.Unmarshal(out, &doc); err != nil {
+		return nil, nil, describeYAMLError(file, out, err)
+	}
+
+	return &doc, out, nil
+}
+
+// mappingValue returns the value of the given key in a mapping node, if present
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+
+	return nil
+}
+
+// groupWalker is called with the location of a group, the mapping node of the
+// group (which is nil if the group is using the shorthand form), and the
+// sequence node of its reviewers
+type groupWalker = func(loc groupLocation, g *yaml.Node, reviewers *yaml.Node)
+
+// walkRepositoryGroups calls fn for each group within the given "repositories"
+// (or "owners") mapping, with the location of each being based on the given one
+func walkRepositoryGroups(repos *yaml.Node, base groupLocation, fn groupWalker) {
+	if repos == nil || repos.Kind != yaml.MappingNode {
+		return
+	}
+
+	for i := 0; i+1 < len(repos.Content); i += 2 {
+		loc := base
+		loc.repo = strings.ToLower(repos.Content[i].Value)
+		groups := repos.Content[i+1]
+
+		// an array is shorthand for the default group
+		if groups.Kind == yaml.SequenceNode {
+			loc.group = "default"
+			fn(loc, nil, groups)
+
+			continue
+		}
+
+		if groups.Kind != yaml.MappingNode {
+			continue
+		}
+
+		for j := 0; j+1 < len(groups.Content); j += 2 {
+			loc.group = groups.Content[j].Value
+			g := groups.Content[j+1]
+
+			// these are settings of the repository rather than groups
+			if isRepositorySettingKey(loc.group) {
+				continue
+			}
+
+			switch g.Kind {
+			case yaml.SequenceNode:
+				fn(loc, nil, g)
+			case yaml.MappingNode:
+				if reviewers := mappingValue(g, "reviewers"); reviewers != nil && reviewers.Kind == yaml.SequenceNode {
+					fn(loc, g, reviewers)
+				}
+			}
+		}
+	}
+}
+
+// walkConfigGroups calls fn for each group of the repositories and owners of
+// the config, including those of its hosts
+func walkConfigGroups(conf *yaml.Node, base groupLocation, fn groupWalker) {
+	owners := base
+	owners.isOwner = true
+
+	walkRepositoryGroups(mappingValue(conf, "repositories"), base, fn)
+	walkRepositoryGroups(mappingValue(conf, "owners"), owners, fn)
+
+	hosts := mappingValue(conf, "hosts")
+
+	if hosts == nil || hosts.Kind != yaml.MappingNode {
+		return
+	}
+
+	for i := 0; i+1 < len(hosts.Content); i += 2 {
+		host := base
+		host.host = strings.ToLower(hosts.Content[i].Value)
+
+		walkConfigGroups(hosts.Content[i+1], host, fn)
+	}
+}
+
+// walkGroups calls fn for every group in the config document, including those
+// of owners, hosts, and profiles
+func walkGroups(doc *yaml.Node, fn groupWalker) {
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 {
+		return
+	}
+
+	root := doc.Content[0]
+
+	walkConfigGroups(root, groupLocation{}, fn)
+
+	profiles := mappingValue(root, "profiles")
+
+	if profiles == nil || profiles.Kind != yaml.MappingNode {
+		return
+	}
+
+	for i := 0; i+1 < len(profiles.Content); i += 2 {
+		walkConfigGroups(profiles.Content[i+1], groupLocation{profile: profiles.Content[i].Value}, fn)
+	}
+}
+
+// reviewerNodeHandle returns the handle of the reviewer represented by the node
+func reviewerNodeHandle(node *yaml.Node) string {
+	if node.Kind == yaml.ScalarNode {
+		return node.Value
+	}
+
+	if handle := mappingValue(node, "handle"); handle != nil {
+		return handle.Value
+	}
+
+	return ""
+}
+
+// encodeConfigDocument converts the edited config document back into yaml
+func encodeConfigDocument(doc *yaml.Node) ([]byte, error) {
+	var buf bytes.Buffer
+
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+
+	if err := encoder.Encode(doc); err != nil {
+		return nil, fmt.Errorf("could not encode config: %w", err)
+	}
+
+	if err := encoder.Close(); err != nil {
+		return nil, fmt.Errorf("could not encode config: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// saveConfigDocument writes the edited config document back to the file, or
+// outputs a diff of the changes that would be made if doing a dry-run
+func saveConfigDocument(stdout io.Writer, file string, doc *yaml.Node, original []byte, isDryRun bool) error {
+	out, err := encodeConfigDocument(doc)
+
+	if err != nil {
+		return err
+	}
+
+	if isDryRun {
+		fmt.Fprintln(stdout, unifiedDiff(file, string(original), string(out)))
+
+		return nil
+	}
+
+	info, err := os.Stat(file)
+
+	if err != nil {
+		return fmt.Errorf("could not save config: %w", err)
+	}
+
+	if err := os.WriteFile(file, out, info.Mode().Perm()); err != nil {
+		return fmt.Errorf("could not save config: %w", err)
+	}
+
+	return nil
+}
+
+// printConfigEditError outputs a friendly message for errors from editing the config
+func printConfigEditError(stderr io.Writer, file string, err error) {
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		fmt.Fprintf(stderr, "please create %s to configure your repositories\n", file)
+	case errors.Is(err, errConfigEncrypted):
+		fmt.Fprintf(stderr, "%s is encrypted with sops, so it cannot be edited by gh-rr\n", file)
+	case errors.Is(err, errConfigIsJSON):
+		fmt.Fprintf(stderr, "%s is json, so it cannot be edited by gh-rr\n", file)
+	default:
+		fmt.Fprintln(stderr, err)
+	}
+}
@@ -0,0 +1,315 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// configGroupCommands are the subcommands of config that edit the groups of a
+// repository, rather than the config as a whole
+var configGroupCommands = []string{"add-reviewer", "remove-reviewer", "add-group", "remove-group"}
+
+type configGroupOptions struct {
+	file     string
+	command  string
+	args     []string
+	group    string
+	isDryRun bool
+}
+
+// findMappingKey returns the index of the given key within the mapping node,
+// or -1 if it is not present
+func findMappingKey(node *yaml.Node, key string, ignoreCase bool) int {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key || (ignoreCase && strings.EqualFold(node.Content[i].Value, key)) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// hasConfigGroup checks if the groups of a repository include the named group
+func hasConfigGroup(groups *yaml.Node, name string) bool {
+	if groups.Kind == yaml.SequenceNode {
+		return name == "default"
+	}
+
+	return !isRepositorySettingKey(name) && findMappingKey(groups, name, false) != -1
+}
+
+// handlesNode creates a sequence node for the given handles
+func handlesNode(handles []string) *yaml.Node {
+	node := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+
+	for _, handle := range handles {
+		node.Content = append(node.Content, scalarNode(handle))
+	}
+
+	return node
+}
+
+// repositoryGroupsNode returns the node holding the groups of the repository in
+// the document, optionally creating it if it does not exist yet; repositories
+// using the shorthand for the default group are expanded into a mapping, so
+// that other groups can be added alongside it
+func repositoryGroupsNode(doc *yaml.Node, repo string, create bool) *yaml.Node {
+	if len(doc.Content) == 0 {
+		if !create {
+			return nil
+		}
+
+		doc.Kind = yaml.DocumentNode
+		doc.Content = []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}
+	}
+
+	root := doc.Content[0]
+	repos := mappingValue(root, "repositories")
+
+	if repos == nil || repos.Kind != yaml.MappingNode {
+		if !create {
+			return nil
+		}
+
+		repos = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		root.Content = append(root.Content, scalarNode("repositories"), repos)
+	}
+
+	i := findMappingKey(repos, repo, true)
+
+	if i == -1 {
+		if !create {
+			return nil
+		}
+
+		repos.Content = append(repos.Content, scalarNode(strings.ToLower(repo)), &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"})
+		i = len(repos.Content) - 2
+	}
+
+	groups := repos.Content[i+1]
+
+	if groups.Kind == yaml.SequenceNode && create {
+		groups = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map", Content: []*yaml.Node{scalarNode("default"), groups}}
+		repos.Content[i+1] = groups
+	}
+
+	return groups
+}
+
+// groupReviewersNode returns the sequence node of the reviewers of the group,
+// adding one to the group if it does not have any reviewers yet
+func groupReviewersNode(groups *yaml.Node, name string) *yaml.Node {
+	// a sequence is shorthand for the default group
+	if groups.Kind == yaml.SequenceNode {
+		if name == "default" {
+			return groups
+		}
+
+		return nil
+	}
+
+	if !hasConfigGroup(groups, name) {
+		return nil
+	}
+
+	g := groups.Content[findMappingKey(groups, name, false)+1]
+
+	switch g.Kind {
+	case yaml.SequenceNode:
+		return g
+	case yaml.MappingNode:
+		reviewers := mappingValue(g, "reviewers")
+
+		if reviewers == nil {
+			reviewers = &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+			g.Content = append(g.Content, scalarNode("reviewers"), reviewers)
+		}
+
+		return reviewers
+	}
+
+	return nil
+}
+
+// editConfigGroups adds or removes reviewers and groups of a repository within
+// the config file, preserving any comments and the order of everything else
+func editConfigGroups(stdout, stderr io.Writer, opts configGroupOptions) int {
+	if len(opts.args) == 0 {
+		fmt.Fprintln(stderr, "please provide the repository")
+
+		return 1
+	}
+
+	repo, args := strings.ToLower(opts.args[0]), opts.args[1:]
+
+	if _, _, found := strings.Cut(repo, "/"); !found {
+		fmt.Fprintln(stderr, "repository should be in the format of <owner>/<repository>")
+
+		return 1
+	}
+
+	name := opts.group
+
+	// the group is given as an argument when it is what's being changed
+	if opts.command == "add-group" || opts.command == "remove-group" {
+		if len(args) == 0 {
+			fmt.Fprintln(stderr, "please provide the name of the group")
+
+			return 1
+		}
+
+		name, args = args[0], args[1:]
+	}
+
+	handles := make([]string, 0, len(args))
+
+	for _, arg := range args {
+		handles = append(handles, strings.TrimPrefix(arg, "@"))
+	}
+
+	if len(handles) == 0 && (opts.command == "add-reviewer" || opts.command == "remove-reviewer") {
+		fmt.Fprintln(stderr, "please provide the handles of the reviewers")
+
+		return 1
+	}
+
+	if len(handles) > 0 && opts.command == "remove-group" {
+		fmt.Fprintln(stderr, "reviewers cannot be given when removing a group")
+
+		return 1
+	}
+
+	doc, original, err := loadConfigDocument(opts.file)
+
+	if err != nil {
+		printConfigEditError(stderr, opts.file, err)
+
+		return 1
+	}
+
+	loc := groupLocation{repo: repo, group: name}
+	groups := repositoryGroupsNode(doc, repo, opts.command == "add-group")
+
+	if groups == nil {
+		fmt.Fprintf(stderr, "%s is not configured\n", repo)
+
+		return 1
+	}
+
+	var message string
+
+	switch opts.command {
+	case "add-group":
+		if hasConfigGroup(groups, name) {
+			fmt.Fprintf(stderr, "%s already has a group named %s\n", repo, name)
+
+			return 1
+		}
+
+		groups.Content = append(groups.Content, scalarNode(name), handlesNode(handles))
+		message = fmt.Sprintf("added %s", loc)
+	case "remove-group":
+		if !hasConfigGroup(groups, name) {
+			fmt.Fprintf(stderr, "%s does not have a group named %s\n", repo, name)
+
+			return 1
+		}
+
+		removeConfigGroup(doc, repo, name)
+		message = fmt.Sprintf("removed %s", loc)
+	case "add-reviewer":
+		reviewers := groupReviewersNode(groups, name)
+
+		if reviewers == nil {
+			fmt.Fprintf(stderr, "%s does not have a group named %s\n", repo, name)
+
+			return 1
+		}
+
+		var added []string
+
+		for _, handle := range handles {
+			if !slices.ContainsFunc(reviewers.Content, func(node *yaml.Node) bool {
+				return strings.EqualFold(reviewerNodeHandle(node), handle)
+			}) {
+				reviewers.Content = append(reviewers.Content, scalarNode(handle))
+				added = append(added, handle)
+			}
+		}
+
+		if len(added) == 0 {
+			fmt.Fprintf(stdout, "everyone is already in %s\n", loc)
+
+			return 0
+		}
+
+		message = fmt.Sprintf("added %s to %s", strings.Join(added, ", "), loc)
+	case "remove-reviewer":
+		reviewers := groupReviewersNode(groups, name)
+
+		if reviewers == nil {
+			fmt.Fprintf(stderr, "%s does not have a group named %s\n", repo, name)
+
+			return 1
+		}
+
+		var removed []string
+
+		reviewers.Content = slices.DeleteFunc(reviewers.Content, func(node *yaml.Node) bool {
+			i := slices.IndexFunc(handles, func(handle string) bool {
+				return strings.EqualFold(reviewerNodeHandle(node), handle)
+			})
+
+			if i != -1 {
+				removed = append(removed, handles[i])
+			}
+
+			return i != -1
+		})
+
+		if len(removed) == 0 {
+			fmt.Fprintf(stdout, "no one given is in %s\n", loc)
+
+			return 0
+		}
+
+		message = fmt.Sprintf("removed %s from %s", strings.Join(removed, ", "), loc)
+	}
+
+	if err := saveConfigDocument(stdout, opts.file, doc, original, opts.isDryRun); err != nil {
+		fmt.Fprintln(stderr, err)
+
+		return 1
+	}
+
+	if opts.isDryRun {
+		message = "would have " + message
+	}
+
+	fmt.Fprintln(stdout, message)
+
+	return 0
+}
+
+// removeConfigGroup removes the group from the repository, along with the
+// repository itself if it would no longer have any groups
+func removeConfigGroup(doc *yaml.Node, repo, name string) {
+	repos := mappingValue(doc.Content[0], "repositories")
+	i := findMappingKey(repos, repo, true)
+	groups := repos.Content[i+1]
+
+	if groups.Kind == yaml.MappingNode {
+		j := findMappingKey(groups, name, false)
+		groups.Content = slices.Delete(groups.Content, j, j+2)
+
+		if len(groups.Content) > 0 {
+			return
+		}
+	}
+
+	repos.Content = slices.Delete(repos.Content, i, i+2)
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+func Test_run_ConfigGroups(t *testing.T) {
+	t.Parallel()
+
+	config := `
+		# the groups of each repository
+		repositories:
+			# this is the implicit default group
+			octocat/hello-world: [octocat, octodog]
+			octocat/spoon-knife:
+				default:
+					- octocat
+				infra:
+					description: Infrastructure
+					reviewers:
+						- handle: octopus
+							name: Octo Pus
+				security:
+					team: octocat/security
+	`
+
+	tests := []struct {
+		name string
+		args []string
+		exit int
+	}{
+		{
+			name: "when adding a reviewer to the default group",
+			args: []string{"config", "add-reviewer", "octocat/hello-world", "@octopus"},
+			exit: 0,
+		},
+		{
+			name: "when adding reviewers to a specific group",
+			args: []string{"config", "add-reviewer", "Octocat/Spoon-Knife", "--from", "infra", "octokitten", "octopus", "octobear"},
+			exit: 0,
+		},
+		{
+			name: "when adding a reviewer to a group without reviewers",
+			args: []string{"config", "add-reviewer", "octocat/spoon-knife", "--from", "security", "octokitten"},
+			exit: 0,
+		},
+		{
+			name: "when adding a reviewer that is already in the group",
+			args: []string{"config", "add-reviewer", "octocat/hello-world", "OctoCat"},
+			exit: 0,
+		},
+		{
+			name: "when adding a reviewer to a group that does not exist",
+			args: []string{"config", "add-reviewer", "octocat/hello-world", "--from", "infra", "octopus"},
+			exit: 1,
+		},
+		{
+			name: "when adding a reviewer to a repository that is not configured",
+			args: []string{"config", "add-reviewer", "octocat/linguist", "octopus"},
+			exit: 1,
+		},
+		{
+			name: "when adding a reviewer without any handles",
+			args: []string{"config", "add-reviewer", "octocat/hello-world"},
+			exit: 1,
+		},
+		{
+			name: "when adding a reviewer without a repository",
+			args: []string{"config", "add-reviewer"},
+			exit: 1,
+		},
+		{
+			name: "when adding a reviewer to an invalid repository",
+			args: []string{"config", "add-reviewer", "hello-world", "octopus"},
+			exit: 1,
+		},
+		{
+			name: "when removing reviewers",
+			args: []string{"config", "remove-reviewer", "octocat/hello-world", "octodog", "octopus"},
+			exit: 0,
+		},
+		{
+			name: "when removing a reviewer with details",
+			args: []string{"config", "remove-reviewer", "octocat/spoon-knife", "--from", "infra", "octopus"},
+			exit: 0,
+		},
+		{
+			name: "when removing a reviewer that is not in the group",
+			args: []string{"config", "remove-reviewer", "octocat/hello-world", "octopus"},
+			exit: 0,
+		},
+		{
+			name: "when adding a group",
+			args: []string{"config", "add-group", "octocat/spoon-knife", "docs", "octokitten", "octobear"},
+			exit: 0,
+		},
+		{
+			name: "when adding a group to a repository using the shorthand",
+			args: []string{"config", "add-group", "octocat/hello-world", "infra", "octopus"},
+			exit: 0,
+		},
+		{
+			name: "when adding a group to a repository that is not configured",
+			args: []string{"config", "add-group", "octocat/linguist", "default", "octopus"},
+			exit: 0,
+		},
+		{
+			name: "when adding a group that already exists",
+			args: []string{"config", "add-group", "octocat/spoon-knife", "infra"},
+			exit: 1,
+		},
+		{
+			name: "when adding a group without a name",
+			args: []string{"config", "add-group", "octocat/spoon-knife"},
+			exit: 1,
+		},
+		{
+			name: "when removing a group",
+			args: []string{"config", "remove-group", "octocat/spoon-knife", "infra"},
+			exit: 0,
+		},
+		{
+			name: "when removing the only group of a repository",
+			args: []string{"config", "remove-group", "octocat/hello-world", "default"},
+			exit: 0,
+		},
+		{
+			name: "when removing a group that does not exist",
+			args: []string{"config", "remove-group", "octocat/spoon-knife", "docs"},
+			exit: 1,
+		},
+		{
+			name: "when removing a group with reviewers",
+			args: []string{"config", "remove-group", "octocat/spoon-knife", "infra", "octopus"},
+			exit: 1,
+		},
+		{
+			name: "when doing a dry-run",
+			args: []string{"config", "add-reviewer", "octocat/hello-world", "octopus", "--dry-run"},
+			exit: 0,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			configDir := writeConfigFileInTempDir(t, dedent(t, config))
+
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+
+			got := run(
+				append([]string{"--config-dir", configDir}, tt.args...),
+				stdout,
+				stderr,
+				expectNoCallToGh(t),
+			)
+
+			if got != tt.exit {
+				t.Errorf("run() = %v, want %v", got, tt.exit)
+			}
+
+			edited, _ := os.ReadFile(filepath.Join(configDir, "gh-rr.yml"))
+
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stdout))
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stderr))
+			snaps.MatchSnapshot(t, string(edited))
+		})
+	}
+}
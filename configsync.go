@@ -0,0 +1,189 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// configGistFilename is the name of the file within a gist that the config is
+// synced through
+const configGistFilename = "gh-rr.yml"
+
+type configSyncOptions struct {
+	file     string
+	args     []string
+	isDryRun bool
+}
+
+// configuredConfigGist returns the gist that the config says it is synced
+// through, if it says so
+func configuredConfigGist(file string) string {
+	conf, err := parseConfig(file)
+
+	if err != nil {
+		return ""
+	}
+
+	return conf.Settings.ConfigGist
+}
+
+// pushConfig stores the config in the given gist, or in a new secret gist if
+// one is not given, returning the id of the gist
+func pushConfig(ghExec ghExecutor, gist string, content []byte) (string, error) {
+	field := fmt.Sprintf("files[%s][content]=%s", configGistFilename, content)
+
+	if gist == "" {
+		out, errMsg := ghExec(
+			"api", "gists",
+			"-f", "description=gh-rr config",
+			"-F", "public=false",
+			"-f", field,
+			"--jq", ".id",
+		)
+
+		if errMsg != "" {
+			return "", fmt.Errorf("could not create gist: %s", strings.TrimSpace(errMsg))
+		}
+
+		return strings.TrimSpace(out), nil
+	}
+
+	if _, errMsg := ghExec("api", "-X", "PATCH", "gists/"+gist, "-f", field); errMsg != "" {
+		return "", fmt.Errorf("could not update gist %s: %s", gist, strings.TrimSpace(errMsg))
+	}
+
+	return gist, nil
+}
+
+// pullConfig returns the config that is stored in the given gist
+func pullConfig(ghExec ghExecutor, gist string) (string, error) {
+	out, errMsg := ghExec(
+		"api", "gists/"+gist,
+		"--jq", fmt.Sprintf(`.files[%q].content // ""`, configGistFilename),
+	)
+
+	if errMsg != "" {
+		return "", fmt.Errorf("could not get gist %s: %s", gist, strings.TrimSpace(errMsg))
+	}
+
+	if strings.TrimSpace(out) == "" {
+		return "", fmt.Errorf("gist %s does not have a %s", gist, configGistFilename)
+	}
+
+	if err := yaml.Unmarshal([]byte(out), &config{}); err != nil {
+		return "", fmt.Errorf("could not parse the %s in gist %s: %w", configGistFilename, gist, err)
+	}
+
+	return strings.TrimSuffix(out, "\n") + "\n", nil
+}
+
+// syncConfig pulls the config from, or pushes it to, a gist so that it can be
+// shared between machines
+func syncConfig(stdout, stderr io.Writer, ghExec ghExecutor, opts configSyncOptions) int {
+	if len(opts.args) == 0 || (opts.args[0] != "pull" && opts.args[0] != "push") {
+		fmt.Fprintln(stderr, "please specify if the config should be pulled, pushed, validated, or edited")
+
+		return 1
+	}
+
+	gist := ""
+
+	if len(opts.args) > 1 {
+		gist = opts.args[1]
+	} else {
+		gist = configuredConfigGist(opts.file)
+	}
+
+	if opts.args[0] == "push" {
+		content, err := os.ReadFile(opts.file)
+
+		if err != nil {
+			printConfigEditError(stderr, opts.file, err)
+
+			return 1
+		}
+
+		if opts.isDryRun {
+			if gist == "" {
+				fmt.Fprintf(stdout, "would have pushed %s to a new secret gist\n", opts.file)
+			} else {
+				fmt.Fprintf(stdout, "would have pushed %s to gist %s\n", opts.file, gist)
+			}
+
+			return 0
+		}
+
+		isNew := gist == ""
+		gist, err = pushConfig(ghExec, gist, content)
+
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+
+			return 1
+		}
+
+		fmt.Fprintf(stdout, "pushed %s to gist %s\n", opts.file, gist)
+
+		if isNew {
+			fmt.Fprintf(stdout, "use `gh rr config pull %s` to pull it on your other machines\n", gist)
+		}
+
+		return 0
+	}
+
+	if gist == "" {
+		fmt.Fprintln(stderr, "please provide the id of the gist to pull the config from")
+
+		return 1
+	}
+
+	content, err := pullConfig(ghExec, gist)
+
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+
+		return 1
+	}
+
+	original, err := os.ReadFile(opts.file)
+
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		fmt.Fprintln(stderr, err)
+
+		return 1
+	}
+
+	if strings.TrimSpace(string(original)) == strings.TrimSpace(content) {
+		fmt.Fprintf(stdout, "%s is already up to date\n", opts.file)
+
+		return 0
+	}
+
+	if opts.isDryRun {
+		fmt.Fprintln(stdout, unifiedDiff(opts.file, string(original), content))
+
+		return 0
+	}
+
+	if err := os.MkdirAll(filepath.Dir(opts.file), 0700); err != nil {
+		fmt.Fprintf(stderr, "could not save config: %v\n", err)
+
+		return 1
+	}
+
+	if err := os.WriteFile(opts.file, []byte(content), 0600); err != nil {
+		fmt.Fprintf(stderr, "could not save config: %v\n", err)
+
+		return 1
+	}
+
+	fmt.Fprintf(stdout, "pulled %s from gist %s\n", opts.file, gist)
+
+	return 0
+}
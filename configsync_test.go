@@ -0,0 +1,204 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+// fakeGistConfigGh acts as gh for the gist api that configs are synced through,
+// with the given gist having the given config
+func fakeGistConfigGh(t *testing.T, content string, calls *[][]string) ghExecutor {
+	t.Helper()
+
+	return func(args ...string) (string, string) {
+		t.Helper()
+
+		*calls = append(*calls, args)
+
+		switch {
+		case len(args) > 1 && args[0] == "api" && args[1] == "gists":
+			return "f00dcafe", ""
+		case len(args) > 1 && args[0] == "api" && strings.Contains(strings.Join(args, " "), "gists/missing"):
+			return "", "gh: Not Found (HTTP 404)"
+		case len(args) > 2 && args[0] == "api" && args[1] == "-X":
+			return "{}", ""
+		case len(args) > 1 && args[0] == "api":
+			return content, ""
+		}
+
+		t.Errorf("unexpected call to gh: %v", args)
+
+		return "", ""
+	}
+}
+
+func Test_run_ConfigSync(t *testing.T) {
+	t.Parallel()
+
+	local := `
+		settings:
+			config_gist: abc123
+		repositories:
+			octocat/hello-world:
+				- octocat
+	`
+
+	remote := `
+		settings:
+			config_gist: abc123
+		repositories:
+			octocat/hello-world:
+				- octocat
+				- octopus
+	`
+
+	tests := []struct {
+		name   string
+		args   []string
+		config string
+		gist   string
+		exit   int
+	}{
+		{
+			name:   "when pushing to the configured gist",
+			args:   []string{"config", "push"},
+			config: local,
+			gist:   remote,
+			exit:   0,
+		},
+		{
+			name:   "when pushing to a specific gist",
+			args:   []string{"config", "push", "def456"},
+			config: local,
+			gist:   remote,
+			exit:   0,
+		},
+		{
+			name:   "when pushing to a new gist",
+			args:   []string{"config", "push"},
+			config: "repositories: {octocat/hello-world: [octocat]}",
+			gist:   "",
+			exit:   0,
+		},
+		{
+			name:   "when pushing to a gist that does not exist",
+			args:   []string{"config", "push", "missing"},
+			config: local,
+			gist:   "",
+			exit:   1,
+		},
+		{
+			name:   "when pushing without a config",
+			args:   []string{"config", "push"},
+			config: "",
+			gist:   "",
+			exit:   1,
+		},
+		{
+			name:   "when doing a dry-run push",
+			args:   []string{"config", "push", "--dry-run"},
+			config: local,
+			gist:   remote,
+			exit:   0,
+		},
+		{
+			name:   "when pulling from the configured gist",
+			args:   []string{"config", "pull"},
+			config: local,
+			gist:   remote,
+			exit:   0,
+		},
+		{
+			name:   "when pulling without a config",
+			args:   []string{"config", "pull", "abc123"},
+			config: "",
+			gist:   remote,
+			exit:   0,
+		},
+		{
+			name:   "when pulling without a gist",
+			args:   []string{"config", "pull"},
+			config: "",
+			gist:   remote,
+			exit:   1,
+		},
+		{
+			name:   "when the config is already up to date",
+			args:   []string{"config", "pull"},
+			config: local,
+			gist:   local,
+			exit:   0,
+		},
+		{
+			name:   "when the gist does not have a config",
+			args:   []string{"config", "pull", "abc123"},
+			config: "",
+			gist:   "",
+			exit:   1,
+		},
+		{
+			name:   "when the gist has an invalid config",
+			args:   []string{"config", "pull", "abc123"},
+			config: "",
+			gist:   "repositories: [",
+			exit:   1,
+		},
+		{
+			name:   "when the gist does not exist",
+			args:   []string{"config", "pull", "missing"},
+			config: local,
+			gist:   "",
+			exit:   1,
+		},
+		{
+			name:   "when doing a dry-run pull",
+			args:   []string{"config", "pull", "--dry-run"},
+			config: local,
+			gist:   remote,
+			exit:   0,
+		},
+		{
+			name:   "when not saying whether to pull or push",
+			args:   []string{"config"},
+			config: local,
+			gist:   remote,
+			exit:   1,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			configDir := writeConfigFileInTempDir(t, dedent(t, tt.config))
+
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+
+			var calls [][]string
+
+			got := run(
+				append([]string{"--config-dir", configDir}, tt.args...),
+				stdout,
+				stderr,
+				fakeGistConfigGh(t, dedent(t, tt.gist), &calls),
+			)
+
+			if got != tt.exit {
+				t.Errorf("run() = %v, want %v", got, tt.exit)
+			}
+
+			config, _ := os.ReadFile(filepath.Join(configDir, "gh-rr.yml"))
+
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stdout))
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stderr))
+			snaps.MatchJSON(t, calls)
+			snaps.MatchSnapshot(t, string(config))
+		})
+	}
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"net/url"
+	"path"
+	"slices"
+	"strings"
+)
+
+// contributorsPrefix marks a reviewer as being the people who have recently
+// committed to the files changed by the pull request that match a glob
+const contributorsPrefix = "contributors:"
+
+// the number of recent contributors that are requested for each glob
+const contributorsLimit = 3
+
+// the number of recent commits to each file that are considered
+const contributorsCommits = 30
+
+// hasContributors checks if any of the reviewers are for recent contributors
+func hasContributors(reviewers []reviewer) bool {
+	return slices.ContainsFunc(reviewers, func(r reviewer) bool {
+		return strings.HasPrefix(r.Handle, contributorsPrefix)
+	})
+}
+
+// withoutContributors returns the reviewers without any that are for recent
+// contributors, for when there is not a single pull request to check
+func withoutContributors(reviewers []reviewer) []reviewer {
+	return slices.DeleteFunc(slices.Clone(reviewers), func(r reviewer) bool {
+		return strings.HasPrefix(r.Handle, contributorsPrefix)
+	})
+}
+
+// matchesPathGlob checks if the path matches the glob, with a trailing "**"
+// matching everything within a directory
+func matchesPathGlob(glob, p string) bool {
+	if dir, ok := strings.CutSuffix(glob, "**"); ok {
+		return strings.HasPrefix(p, dir)
+	}
+
+	ok, _ := path.Match(glob, p)
+
+	return ok
+}
+
+// fetchFileContributors returns the logins of the authors of the recent commits
+// to the given file, with an entry for each commit
+func fetchFileContributors(ghExec ghExecutor, repo, file string) ([]string, error) {
+	out, errMsg := ghExec("api", fmt.Sprintf("repos/%s/commits?path=%s&per_page=%d", repo, url.QueryEscape(file), contributorsCommits))
+
+	if errMsg != "" {
+		return nil, fmt.Errorf("could not get the commits to %s: %s", file, strings.TrimSpace(errMsg))
+	}
+
+	commits, err := decodePages[struct {
+		Author *struct {
+			Login string `json:"login"`
+		} `json:"author"`
+	}](out)
+
+	if err != nil {
+		return nil, fmt.Errorf("could not parse the commits to %s: %w", file, err)
+	}
+
+	logins := make([]string, 0, len(commits))
+
+	for _, commit := range commits {
+		// commits can be made by people without a GitHub account
+		if commit.Author != nil && commit.Author.Login != "" {
+			logins = append(logins, commit.Author.Login)
+		}
+	}
+
+	return logins, nil
+}
+
+// topContributors returns the people who have made the most commits, ignoring
+// bots and the given author as they cannot review the pull request
+func topContributors(logins []string, author string) []string {
+	counts := make(map[string]int)
+	names := make(map[string]string)
+
+	for _, login := range logins {
+		key := strings.ToLower(login)
+
+		if strings.HasSuffix(key, "[bot]") || strings.EqualFold(login, author) {
+			continue
+		}
+
+		counts[key]++
+		names[key] = login
+	}
+
+	keys := make([]string, 0, len(counts))
+
+	for key := range counts {
+		keys = append(keys, key)
+	}
+
+	slices.SortFunc(keys, func(a, b string) int {
+		if counts[a] != counts[b] {
+			return cmp.Compare(counts[b], counts[a])
+		}
+
+		return strings.Compare(a, b)
+	})
+
+	top := make([]string, 0, contributorsLimit)
+
+	for _, key := range keys[:min(len(keys), contributorsLimit)] {
+		top = append(top, names[key])
+	}
+
+	return top
+}
+
+// expandContributors replaces any recent contributor reviewers with the people
+// who have most recently committed to the files changed by the pull request
+// that match their glob, skipping anyone who is already a reviewer
+func expandContributors(ghExec ghExecutor, repo, target string, reviewers []reviewer) ([]reviewer, error) {
+	if !hasContributors(reviewers) {
+		return reviewers, nil
+	}
+
+	pr, err := fetchPullRequest(ghExec, repo, target, "author", "files")
+
+	if err != nil {
+		return nil, err
+	}
+
+	expanded := make([]reviewer, 0, len(reviewers))
+	seen := make(map[string]bool)
+	commits := make(map[string][]string)
+
+	for _, r := range reviewers {
+		if !strings.HasPrefix(r.Handle, contributorsPrefix) {
+			seen[strings.ToLower(r.Handle)] = true
+		}
+	}
+
+	for _, r := range reviewers {
+		glob, ok := strings.CutPrefix(r.Handle, contributorsPrefix)
+
+		if !ok {
+			expanded = append(expanded, r)
+
+			continue
+		}
+
+		var logins []string
+
+		for _, file := range pr.Files {
+			if !matchesPathGlob(glob, file.Path) {
+				continue
+			}
+
+			if _, ok := commits[file.Path]; !ok {
+				commits[file.Path], err = fetchFileContributors(ghExec, repo, file.Path)
+
+				if err != nil {
+					return nil, err
+				}
+			}
+
+			for _, login := range commits[file.Path] {
+				if !seen[strings.ToLower(login)] {
+					logins = append(logins, login)
+				}
+			}
+		}
+
+		for _, login := range topContributors(logins, pr.Author.Login) {
+			seen[strings.ToLower(login)] = true
+			expanded = append(expanded, reviewer{Handle: login, method: r.method})
+		}
+	}
+
+	return expanded, nil
+}
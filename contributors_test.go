@@ -0,0 +1,155 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+// fakeContributorsGh acts as gh for a pull request authored by octocat that
+// changes the given files, each of which has the given commit authors
+func fakeContributorsGh(t *testing.T, files map[string][]string, calls *[][]string) ghExecutor {
+	t.Helper()
+
+	return func(args ...string) (string, string) {
+		t.Helper()
+
+		*calls = append(*calls, args)
+
+		switch {
+		case len(args) > 1 && args[0] == "pr" && args[1] == "view":
+			paths := make([]string, 0, len(files))
+
+			for _, p := range []string{"src/api/server.go", "src/api/routes.go", "docs/README.md", "broken.go"} {
+				if _, ok := files[p]; ok {
+					paths = append(paths, `{"path":"`+p+`"}`)
+				}
+			}
+
+			return `{"author":{"login":"octocat"},"files":[` + strings.Join(paths, ",") + `]}`, ""
+		case len(args) > 1 && args[0] == "api":
+			for p, authors := range files {
+				if !strings.Contains(args[1], "path="+strings.ReplaceAll(p, "/", "%2F")+"&") {
+					continue
+				}
+
+				if p == "broken.go" {
+					return "", "gh: Server Error (HTTP 500)"
+				}
+
+				commits := make([]string, 0, len(authors))
+
+				for _, author := range authors {
+					if author == "" {
+						commits = append(commits, `{"author":null}`)
+					} else {
+						commits = append(commits, `{"author":{"login":"`+author+`"}}`)
+					}
+				}
+
+				return "[" + strings.Join(commits, ",") + "]", ""
+			}
+		case len(args) > 1 && args[0] == "pr" && args[1] == "edit":
+			return "https://github.com/octocat/hello-world/pull/123", ""
+		}
+
+		t.Errorf("unexpected call to gh: %v", args)
+
+		return "", ""
+	}
+}
+
+func Test_run_Contributors(t *testing.T) {
+	t.Parallel()
+
+	files := map[string][]string{
+		"src/api/server.go": {"octopus", "octodog", "octocat", "octopus", "dependabot[bot]", ""},
+		"src/api/routes.go": {"OctoDog", "octokitten", "octopus", "octobear"},
+		"docs/README.md":    {"octobear"},
+	}
+
+	tests := []struct {
+		name   string
+		args   []string
+		config string
+		files  map[string][]string
+		exit   int
+	}{
+		{
+			name: "when a group includes recent contributors",
+			args: []string{"123"},
+			config: `
+				repositories:
+					octocat/hello-world:
+						- octokitten
+						- contributors:src/api/**
+			`,
+			files: files,
+			exit:  0,
+		},
+		{
+			name: "when no changed files match the glob",
+			args: []string{"123"},
+			config: `
+				repositories:
+					octocat/hello-world:
+						- octokitten
+						- contributors:lib/*.go
+			`,
+			files: files,
+			exit:  0,
+		},
+		{
+			name: "when the group does not include recent contributors",
+			args: []string{"123"},
+			config: `
+				repositories:
+					octocat/hello-world:
+						- octokitten
+			`,
+			files: files,
+			exit:  0,
+		},
+		{
+			name: "when the commits cannot be fetched",
+			args: []string{"123"},
+			config: `
+				repositories:
+					octocat/hello-world:
+						- contributors:*.go
+			`,
+			files: map[string][]string{"broken.go": nil},
+			exit:  1,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			configDir := writeConfigFileInTempDir(t, dedent(t, tt.config))
+
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+
+			var calls [][]string
+
+			got := run(
+				append([]string{"--config-dir", configDir, "--repo", "octocat/hello-world"}, tt.args...),
+				stdout,
+				stderr,
+				fakeContributorsGh(t, tt.files, &calls),
+			)
+
+			if got != tt.exit {
+				t.Errorf("run() = %v, want %v", got, tt.exit)
+			}
+
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stdout))
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stderr))
+			snaps.MatchJSON(t, calls)
+		})
+	}
+}
@@ -0,0 +1,316 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// codeownersLocations are where GitHub looks for a CODEOWNERS file, in order
+var codeownersLocations = []string{".github/CODEOWNERS", "CODEOWNERS", "docs/CODEOWNERS"}
+
+// codeownersRule is a line of a CODEOWNERS file
+type codeownersRule struct {
+	pattern string
+	re      *regexp.Regexp
+	owners  []string
+}
+
+// codeownersPatternToRegexp converts a CODEOWNERS pattern, which follows the
+// same rules as gitignore, into a regular expression for matching paths
+func codeownersPatternToRegexp(pattern string) (*regexp.Regexp, error) {
+	isDir := strings.HasSuffix(pattern, "/")
+	trimmed := strings.Trim(pattern, "/")
+
+	// patterns are relative to the root if they have a slash anywhere but the end
+	isAnchored := strings.HasPrefix(pattern, "/") || strings.Contains(trimmed, "/")
+
+	var sb strings.Builder
+
+	if isAnchored {
+		sb.WriteString("^")
+	} else {
+		sb.WriteString("^(?:.*/)?")
+	}
+
+	for i := 0; i < len(trimmed); i++ {
+		switch {
+		case strings.HasPrefix(trimmed[i:], "**/"):
+			sb.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(trimmed[i:], "**"):
+			sb.WriteString(".*")
+			i++
+		case trimmed[i] == '*':
+			sb.WriteString("[^/]*")
+		case trimmed[i] == '?':
+			sb.WriteString("[^/]")
+		default:
+			sb.WriteString(regexp.QuoteMeta(trimmed[i : i+1]))
+		}
+	}
+
+	switch {
+	case isDir:
+		sb.WriteString("/.*$")
+	// "docs/*" only matches the files directly within the directory
+	case strings.HasSuffix(trimmed, "/*"):
+		sb.WriteString("$")
+	default:
+		sb.WriteString("(?:/.*)?$")
+	}
+
+	return regexp.Compile(sb.String())
+}
+
+// parseCodeowners returns the rules in the given CODEOWNERS file
+func parseCodeowners(content string) ([]codeownersRule, error) {
+	var rules []codeownersRule
+
+	scanner := bufio.NewScanner(strings.NewReader(content))
+
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		fields := strings.Fields(line)
+
+		if len(fields) == 0 {
+			continue
+		}
+
+		re, err := codeownersPatternToRegexp(fields[0])
+
+		if err != nil {
+			return nil, fmt.Errorf("could not parse CODEOWNERS pattern %s: %w", fields[0], err)
+		}
+
+		rules = append(rules, codeownersRule{pattern: fields[0], re: re, owners: fields[1:]})
+	}
+
+	return rules, nil
+}
+
+// fetchCodeowners returns the rules of the CODEOWNERS file of the repository,
+// if it has one
+func fetchCodeowners(ghExec ghExecutor, repo string) ([]codeownersRule, error) {
+	for _, location := range codeownersLocations {
+		out, errMsg := ghExec("api", fmt.Sprintf("repos/%s/contents/%s", repo, location), "-H", "Accept: application/vnd.github.raw")
+
+		if errMsg != "" {
+			if strings.Contains(errMsg, "HTTP 404") {
+				continue
+			}
+
+			return nil, fmt.Errorf("could not get the CODEOWNERS of %s: %s", repo, strings.TrimSpace(errMsg))
+		}
+
+		return parseCodeowners(out)
+	}
+
+	return nil, nil
+}
+
+// requiredParty is someone whose approval is required on a pull request,
+// which is given if any one of the reviewers approves
+type requiredParty struct {
+	name      string
+	reviewers []string
+}
+
+// codeownerParties returns the owners of each of the given files, based on the
+// last rule in the CODEOWNERS file that matches each file
+func codeownerParties(rules []codeownersRule, files []string) []requiredParty {
+	var parties []requiredParty
+
+	for _, file := range files {
+		var owners []string
+		var pattern string
+
+		for _, rule := range rules {
+			if rule.re.MatchString(file) {
+				owners = rule.owners
+				pattern = rule.pattern
+			}
+		}
+
+		if len(owners) == 0 {
+			continue
+		}
+
+		name := fmt.Sprintf("%s (%s)", strings.Join(owners, " "), pattern)
+
+		if slices.ContainsFunc(parties, func(p requiredParty) bool { return p.name == name }) {
+			continue
+		}
+
+		reviewers := make([]string, 0, len(owners))
+
+		for _, owner := range owners {
+			reviewers = append(reviewers, strings.TrimPrefix(owner, "@"))
+		}
+
+		parties = append(parties, requiredParty{name: name, reviewers: reviewers})
+	}
+
+	return parties
+}
+
+// hasRequestedReviewFromOwner checks if a review has been requested from the
+// given user or team, which can include the organization of the team
+func (pr pullRequest) hasRequestedReviewFromOwner(owner string) bool {
+	if pr.hasRequestedReviewFrom(owner) {
+		return true
+	}
+
+	_, slug, isTeam := strings.Cut(owner, "/")
+
+	return isTeam && pr.hasRequestedReviewFrom(slug)
+}
+
+// coverageOf returns who has approved on behalf of the party, and whether any
+// of the reviewers for the party have had a review requested from them
+func coverageOf(cache *teamMemberCache, pr pullRequest, approved map[string]bool, party requiredParty) ([]string, bool, error) {
+	var approvers []string
+
+	isRequested := false
+
+	for _, handle := range party.reviewers {
+		isRequested = isRequested || pr.hasRequestedReviewFromOwner(handle)
+
+		members, isTeam, err := cache.membersOf(handle)
+
+		if err != nil {
+			return nil, false, err
+		}
+
+		if !isTeam {
+			members = []string{handle}
+		}
+
+		for _, member := range members {
+			isRequested = isRequested || pr.hasRequestedReviewFrom(member)
+
+			if approved[strings.ToLower(member)] && !slices.Contains(approvers, member) {
+				approvers = append(approvers, member)
+			}
+		}
+	}
+
+	return approvers, isRequested, nil
+}
+
+// reportCoverage outputs which of the parties whose approval is required on
+// the pull request have approved it, which are pending, and which were never
+// requested, failing unless they have all approved
+func reportCoverage(stdout, stderr io.Writer, ghExec ghExecutor, conf config, repo, target string) int {
+	pr, err := fetchPullRequest(ghExec, repo, target, "url", "files", "reviews", "reviewRequests")
+
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+
+		return 1
+	}
+
+	rules, err := fetchCodeowners(ghExec, repo)
+
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+
+		return 1
+	}
+
+	var parties []requiredParty
+
+	for _, name := range findForRepository(conf.Required, repo) {
+		reviewers, err := determineReviewers(conf, strings.ToLower(repo), name)
+
+		if err != nil {
+			printReviewersError(stderr, err, repo, name)
+
+			return 1
+		}
+
+		reviewers, err = expandDynamicReviewers(ghExec, reviewers)
+
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+
+			return 1
+		}
+
+		handles := make([]string, 0, len(reviewers))
+
+		for _, reviewer := range reviewers {
+			handles = append(handles, reviewer.Handle)
+		}
+
+		parties = append(parties, requiredParty{name: fmt.Sprintf("the %s group", name), reviewers: handles})
+	}
+
+	files := make([]string, 0, len(pr.Files))
+
+	for _, file := range pr.Files {
+		files = append(files, file.Path)
+	}
+
+	parties = append(parties, codeownerParties(rules, files)...)
+
+	if len(parties) == 0 {
+		fmt.Fprintf(stdout, "no approvals are required on %s\n", pr.URL)
+
+		return 0
+	}
+
+	cache := &teamMemberCache{ghExec: ghExec, members: make(map[string][]string)}
+	approved := pr.approvers()
+
+	var done, pending, missing []string
+
+	for _, party := range parties {
+		approvers, isRequested, err := coverageOf(cache, pr, approved, party)
+
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+
+			return 1
+		}
+
+		switch {
+		case len(approvers) > 0:
+			done = append(done, fmt.Sprintf("%s, by %s", party.name, strings.Join(approvers, ", ")))
+		case isRequested:
+			pending = append(pending, party.name)
+		default:
+			missing = append(missing, party.name)
+		}
+	}
+
+	fmt.Fprintf(stdout, "required approvals on %s:\n", pr.URL)
+
+	for _, section := range []struct {
+		title   string
+		parties []string
+	}{
+		{"approved", done},
+		{"pending", pending},
+		{"never requested", missing},
+	} {
+		if len(section.parties) == 0 {
+			continue
+		}
+
+		fmt.Fprintf(stdout, "  %s:\n", section.title)
+
+		for _, party := range section.parties {
+			fmt.Fprintf(stdout, "    - %s\n", party)
+		}
+	}
+
+	if len(pending) > 0 || len(missing) > 0 {
+		return 1
+	}
+
+	return 0
+}
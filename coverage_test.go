@@ -0,0 +1,154 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+// fakeCoverageGh acts as gh for a pull request with the given details, in a
+// repository where the api responds with the given responses by path
+func fakeCoverageGh(t *testing.T, details string, responses map[string]string, calls *[][]string) ghExecutor {
+	t.Helper()
+
+	return func(args ...string) (string, string) {
+		t.Helper()
+
+		*calls = append(*calls, args)
+
+		switch {
+		case len(args) > 1 && args[0] == "pr" && args[1] == "view":
+			return details, ""
+		case len(args) > 1 && args[0] == "api":
+			if out, ok := responses[args[1]]; ok {
+				return out, ""
+			}
+
+			return "", "gh: Not Found (HTTP 404)"
+		}
+
+		t.Errorf("unexpected call to gh: %v", args)
+
+		return "", ""
+	}
+}
+
+func Test_run_Coverage(t *testing.T) {
+	t.Parallel()
+
+	codeowners := dedent(t, `
+		# everything else
+		*           @octocat
+		*.md        @octo-org/docs
+		/src/api/   @octodog @octopus
+		docs/*      @octobear
+	`)
+
+	responses := map[string]string{
+		"repos/octocat/hello-world/contents/CODEOWNERS": codeowners,
+		"orgs/octo-org/teams/docs/members?role=all":     `[{"login":"octokitten"},{"login":"octobear"}]`,
+		"orgs/octo-org/teams/security/members?role=all": `[{"login":"octodog"}]`,
+	}
+
+	files := `"files":[{"path":"src/api/server.go"},{"path":"docs/guides/setup.md"},{"path":"main.go"}]`
+
+	config := `
+		required:
+			octocat/hello-world: [security]
+		repositories:
+			octocat/hello-world:
+				security: [octo-org/security]
+	`
+
+	tests := []struct {
+		name      string
+		args      []string
+		config    string
+		details   string
+		responses map[string]string
+		exit      int
+	}{
+		{
+			name:      "when some required approvals are missing",
+			args:      []string{"coverage", "123"},
+			config:    config,
+			details:   `{"url":"https://github.com/octocat/hello-world/pull/123",` + files + `,"reviews":[{"author":{"login":"octopus"},"state":"APPROVED"}],"reviewRequests":[{"slug":"docs"},{"login":"octodog"}]}`,
+			responses: responses,
+			exit:      1,
+		},
+		{
+			name:   "when every required approval has been given",
+			args:   []string{"coverage", "123"},
+			config: config,
+			details: `{"url":"https://github.com/octocat/hello-world/pull/123",` + files + `,"reviews":[` +
+				`{"author":{"login":"octodog"},"state":"APPROVED"},` +
+				`{"author":{"login":"octokitten"},"state":"APPROVED"},` +
+				`{"author":{"login":"octocat"},"state":"APPROVED"}` +
+				`],"reviewRequests":[]}`,
+			responses: responses,
+			exit:      0,
+		},
+		{
+			name: "when nothing is required",
+			args: []string{"coverage", "123"},
+			config: `
+				repositories:
+					octocat/hello-world:
+						- octocat
+			`,
+			details:   `{"url":"https://github.com/octocat/hello-world/pull/123",` + files + `,"reviews":[],"reviewRequests":[]}`,
+			responses: map[string]string{},
+			exit:      0,
+		},
+		{
+			name: "when a required group does not exist",
+			args: []string{"coverage", "123"},
+			config: `
+				required:
+					'*': [security]
+				repositories:
+					octocat/hello-world:
+						- octocat
+			`,
+			details:   `{"url":"https://github.com/octocat/hello-world/pull/123",` + files + `,"reviews":[],"reviewRequests":[]}`,
+			responses: map[string]string{},
+			exit:      1,
+		},
+		{
+			name:      "when given multiple pull requests",
+			args:      []string{"coverage", "1", "2"},
+			config:    config,
+			responses: responses,
+			exit:      1,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			configDir := writeConfigFileInTempDir(t, dedent(t, tt.config))
+
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+
+			var calls [][]string
+
+			got := run(
+				append([]string{"--config-dir", configDir, "--repo", "octocat/hello-world"}, tt.args...),
+				stdout,
+				stderr,
+				fakeCoverageGh(t, tt.details, tt.responses, &calls),
+			)
+
+			if got != tt.exit {
+				t.Errorf("run() = %v, want %v", got, tt.exit)
+			}
+
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stdout))
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stderr))
+			snaps.MatchJSON(t, calls)
+		})
+	}
+}
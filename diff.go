@@ -0,0 +1,143 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// diffContext is the number of unchanged lines shown around each change
+const diffContext = 2
+
+type diffOp struct {
+	kind byte // ' ', '-', or '+'
+	line string
+}
+
+// diffLines returns the operations needed to turn a into b, using the longest
+// common subsequence of their lines; configs are small, so this is fine
+func diffLines(a, b []string) []diffOp {
+	lcs := make([][]int, len(a)+1)
+
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	ops := make([]diffOp, 0, len(a)+len(b))
+
+	i, j := 0, 0
+
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			ops = append(ops, diffOp{' ', a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			ops = append(ops, diffOp{'-', a[i]})
+			i++
+		default:
+			ops = append(ops, diffOp{'+', b[j]})
+			j++
+		}
+	}
+
+	for ; i < len(a); i++ {
+		ops = append(ops, diffOp{'-', a[i]})
+	}
+
+	for ; j < len(b); j++ {
+		ops = append(ops, diffOp{'+', b[j]})
+	}
+
+	return ops
+}
+
+// unifiedDiff returns a unified diff of the changes between the two versions
+// of the given file, or an empty string if there are no changes
+func unifiedDiff(file, before, after string) string {
+	ops := diffLines(
+		strings.Split(strings.TrimSuffix(before, "\n"), "\n"),
+		strings.Split(strings.TrimSuffix(after, "\n"), "\n"),
+	)
+
+	var sb strings.Builder
+
+	// the start and end of the current hunk within ops
+	start, end := -1, -1
+
+	flush := func() {
+		if start == -1 {
+			return
+		}
+
+		aStart, bStart := 1, 1
+
+		for _, op := range ops[:start] {
+			if op.kind != '+' {
+				aStart++
+			}
+
+			if op.kind != '-' {
+				bStart++
+			}
+		}
+
+		aLen, bLen := 0, 0
+
+		for _, op := range ops[start:end] {
+			if op.kind != '+' {
+				aLen++
+			}
+
+			if op.kind != '-' {
+				bLen++
+			}
+		}
+
+		fmt.Fprintf(&sb, "@@ -%d,%d +%d,%d @@\n", aStart, aLen, bStart, bLen)
+
+		for _, op := range ops[start:end] {
+			fmt.Fprintf(&sb, "%c%s\n", op.kind, op.line)
+		}
+
+		start, end = -1, -1
+	}
+
+	for i, op := range ops {
+		if op.kind == ' ' {
+			continue
+		}
+
+		hunkStart := max(i-diffContext, 0)
+		hunkEnd := min(i+diffContext+1, len(ops))
+
+		// start a new hunk if this change is too far from the current one
+		if start != -1 && hunkStart > end {
+			flush()
+		}
+
+		if start == -1 {
+			start = hunkStart
+		}
+
+		end = max(end, hunkEnd)
+	}
+
+	flush()
+
+	if sb.Len() == 0 {
+		return ""
+	}
+
+	return fmt.Sprintf("--- %s\n+++ %s\n%s", file, file, sb.String())
+}
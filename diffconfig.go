@@ -0,0 +1,252 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// parseConfigSource parses the given source as a repository config, being in
+// the format of OWNER/REPO[:PATH][@REF] with the path defaulting to that of the
+// shared config, returning false if it is not in that format
+func parseConfigSource(source string) (repo, file, ref string, ok bool) {
+	repo, ref, _ = strings.Cut(source, "@")
+	repo, file, _ = strings.Cut(repo, ":")
+
+	if strings.Count(repo, "/") != 1 || strings.HasPrefix(repo, "/") || strings.HasSuffix(repo, "/") {
+		return "", "", "", false
+	}
+
+	if file == "" {
+		file = sharedConfigPath
+	}
+
+	return repo, file, ref, true
+}
+
+// loadConfigSource loads the config from the given source, which is either a
+// local file or a file within a repository
+func loadConfigSource(ghExec ghExecutor, source string) (config, error) {
+	if _, err := os.Stat(source); err == nil {
+		return parseConfig(source)
+	}
+
+	repo, file, ref, ok := parseConfigSource(source)
+
+	if !ok {
+		return config{}, fmt.Errorf("%s is not a file or a repository in the format of OWNER/REPO[:PATH][@REF]", source)
+	}
+
+	out, _, err := fetchConfigFile(ghExec, repo, file, ref)
+
+	if err != nil {
+		return config{}, err
+	}
+
+	conf := config{Repositories: repositories{}}
+
+	if err := yaml.Unmarshal(out, &conf); err != nil {
+		return config{}, describeYAMLError(source, out, err)
+	}
+
+	return conf, nil
+}
+
+// configChange is a change made between two configs, along with the
+// repositories that it was made in
+type configChange struct {
+	description string
+	repos       []string
+}
+
+// describeRepos returns a human-friendly description of the repositories
+func describeRepos(repos []string) string {
+	if len(repos) == 1 {
+		return repos[0]
+	}
+
+	return fmt.Sprintf("%d repositories (%s)", len(repos), strings.Join(repos, ", "))
+}
+
+// handlesOf returns the handles of the reviewers, keyed by their lowercase form
+func handlesOf(reviewers []reviewer) map[string]string {
+	handles := make(map[string]string, len(reviewers))
+
+	for _, r := range reviewers {
+		handles[strings.ToLower(r.Handle)] = r.Handle
+	}
+
+	return handles
+}
+
+// diffConfigs returns the changes to groups and their membership between the
+// two configs, with the same change made in multiple repositories combined
+func diffConfigs(before, after config) []configChange {
+	var order []string
+
+	changes := make(map[string]*configChange)
+
+	record := func(description, repo string) {
+		if _, ok := changes[description]; !ok {
+			order = append(order, description)
+			changes[description] = &configChange{description: description}
+		}
+
+		changes[description].repos = append(changes[description].repos, repo)
+	}
+
+	var repos []string
+
+	for repo := range before.Repositories {
+		repos = append(repos, repo)
+	}
+
+	for repo := range after.Repositories {
+		if _, ok := before.Repositories[repo]; !ok {
+			repos = append(repos, repo)
+		}
+	}
+
+	slices.Sort(repos)
+
+	for _, repo := range repos {
+		var names []string
+
+		for name := range before.Repositories[repo] {
+			names = append(names, name)
+		}
+
+		for name := range after.Repositories[repo] {
+			if _, ok := before.Repositories[repo][name]; !ok {
+				names = append(names, name)
+			}
+		}
+
+		slices.Sort(names)
+
+		for _, name := range names {
+			was, hadGroup := before.Repositories[repo][name]
+			is, hasGroup := after.Repositories[repo][name]
+
+			if !hadGroup {
+				record(fmt.Sprintf("the %s group was added to", name), repo)
+
+				continue
+			}
+
+			if !hasGroup {
+				record(fmt.Sprintf("the %s group was removed from", name), repo)
+
+				continue
+			}
+
+			wasHandles := handlesOf(was.Reviewers)
+			isHandles := handlesOf(is.Reviewers)
+
+			for _, r := range was.Reviewers {
+				if _, ok := isHandles[strings.ToLower(r.Handle)]; !ok {
+					record(fmt.Sprintf("%s was removed from %s in", r.Handle, name), repo)
+				}
+			}
+
+			for _, r := range is.Reviewers {
+				if _, ok := wasHandles[strings.ToLower(r.Handle)]; !ok {
+					record(fmt.Sprintf("%s was added to %s in", r.Handle, name), repo)
+				}
+			}
+		}
+	}
+
+	result := make([]configChange, 0, len(order))
+
+	for _, description := range order {
+		result = append(result, *changes[description])
+	}
+
+	return result
+}
+
+// diffConfigSections returns the names of the other top-level sections of the
+// configs which have changed, as those are not described in detail
+func diffConfigSections(before, after config) []string {
+	var sections []string
+
+	b := reflect.ValueOf(before)
+	a := reflect.ValueOf(after)
+
+	for i := 0; i < b.NumField(); i++ {
+		field := b.Type().Field(i)
+
+		if field.Name == "Repositories" {
+			continue
+		}
+
+		if !reflect.DeepEqual(b.Field(i).Interface(), a.Field(i).Interface()) {
+			name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+			sections = append(sections, name)
+		}
+	}
+
+	return sections
+}
+
+type diffConfigOptions struct {
+	sources []string
+	current string
+}
+
+// printConfigDiff outputs how the groups changed between two configs, with the
+// current config being compared against if only one source is given
+func printConfigDiff(stdout, stderr io.Writer, ghExec ghExecutor, opts diffConfigOptions) int {
+	if len(opts.sources) == 0 || len(opts.sources) > 2 {
+		fmt.Fprintln(stderr, "please provide one or two configs to compare")
+
+		return 1
+	}
+
+	sources := opts.sources
+
+	if len(sources) == 1 {
+		sources = []string{sources[0], opts.current}
+	}
+
+	configs := make([]config, 0, len(sources))
+
+	for _, source := range sources {
+		conf, err := loadConfigSource(ghExec, source)
+
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+
+			return 1
+		}
+
+		configs = append(configs, conf)
+	}
+
+	changes := diffConfigs(configs[0], configs[1])
+	sections := diffConfigSections(configs[0], configs[1])
+
+	if len(changes) == 0 && len(sections) == 0 {
+		fmt.Fprintf(stdout, "there are no differences between %s and %s\n", sources[0], sources[1])
+
+		return 0
+	}
+
+	fmt.Fprintf(stdout, "changes from %s to %s:\n", sources[0], sources[1])
+
+	for _, change := range changes {
+		fmt.Fprintf(stdout, "  - %s %s\n", change.description, describeRepos(change.repos))
+	}
+
+	for _, section := range sections {
+		fmt.Fprintf(stdout, "  - the %s section has changed\n", section)
+	}
+
+	return 0
+}
@@ -0,0 +1,189 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+// fakeDiffConfigGh acts as gh for a repository with the given files, keyed by
+// their contents api endpoint
+func fakeDiffConfigGh(t *testing.T, files map[string]string, calls *[][]string) ghExecutor {
+	t.Helper()
+
+	return func(args ...string) (string, string) {
+		t.Helper()
+
+		*calls = append(*calls, args)
+
+		if len(args) > 1 && args[0] == "api" {
+			content, ok := files[args[1]]
+
+			if !ok {
+				return "", "gh: Not Found (HTTP 404)"
+			}
+
+			return fmt.Sprintf(`{"content":"%s\n","sha":"abc123"}`, base64.StdEncoding.EncodeToString([]byte(content))), ""
+		}
+
+		t.Errorf("unexpected call to gh: %v", args)
+
+		return "", ""
+	}
+}
+
+func Test_run_DiffConfig(t *testing.T) {
+	t.Parallel()
+
+	before := dedent(t, `
+		repositories:
+			'*':
+				security: [octodog, octokitten]
+			octocat/hello-world:
+				default: [octocat]
+				security: [octodog, octopus]
+			octocat/spoon-knife:
+				default: [octocat]
+				security: [octodog, octopus]
+				infra: [octopus]
+	`)
+
+	after := dedent(t, `
+		repositories:
+			'*':
+				security: [octokitten]
+			octocat/hello-world:
+				default: [OctoCat, octobear]
+				security: [octopus]
+				docs: [octokitten]
+			octocat/spoon-knife:
+				default: [octocat]
+				security: [octopus]
+	`)
+
+	files := map[string]string{
+		"repos/octo-org/configs/contents/.github/gh-rr.yml":                   before,
+		"repos/octo-org/configs/contents/.github/gh-rr.yml?ref=new-reviewers": after,
+		"repos/octo-org/configs/contents/teams/gh-rr.yml":                     after,
+		"repos/octo-org/configs/contents/invalid.yml":                         "repositories: [",
+	}
+
+	tests := []struct {
+		name  string
+		args  []string
+		other string
+		exit  int
+	}{
+		{
+			name:  "when comparing two files",
+			args:  []string{"diff-config", "{{config}}", "{{other}}"},
+			other: after,
+			exit:  0,
+		},
+		{
+			name:  "when comparing a file against the current config",
+			args:  []string{"diff-config", "{{other}}"},
+			other: after,
+			exit:  0,
+		},
+		{
+			name: "when comparing two refs of a repository",
+			args: []string{"diff-config", "octo-org/configs", "octo-org/configs@new-reviewers"},
+			exit: 0,
+		},
+		{
+			name: "when comparing a file within a repository against the current config",
+			args: []string{"diff-config", "octo-org/configs:teams/gh-rr.yml"},
+			exit: 0,
+		},
+		{
+			name:  "when there are no differences",
+			args:  []string{"diff-config", "{{config}}", "{{other}}"},
+			other: before,
+			exit:  0,
+		},
+		{
+			name:  "when only other sections have changed",
+			args:  []string{"diff-config", "{{config}}", "{{other}}"},
+			other: before + "\nsettings:\n  order: shuffle\n",
+			exit:  0,
+		},
+		{
+			name: "when the repository does not have the config",
+			args: []string{"diff-config", "octo-org/configs:missing.yml"},
+			exit: 1,
+		},
+		{
+			name: "when the config in the repository is invalid",
+			args: []string{"diff-config", "octo-org/configs:invalid.yml"},
+			exit: 1,
+		},
+		{
+			name: "when the source is not a file or a repository",
+			args: []string{"diff-config", "nope.yml"},
+			exit: 1,
+		},
+		{
+			name: "when no sources are given",
+			args: []string{"diff-config"},
+			exit: 1,
+		},
+		{
+			name: "when too many sources are given",
+			args: []string{"diff-config", "octo-org/configs", "octo-org/configs", "octo-org/configs"},
+			exit: 1,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			configDir := writeConfigFileInTempDir(t, before)
+			other := filepath.Join(writeConfigFileInTempDir(t, ""), "other.yml")
+
+			if tt.other != "" {
+				if err := os.WriteFile(other, []byte(tt.other), 0600); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			args := slices.Clone(tt.args)
+
+			for i, arg := range args {
+				switch arg {
+				case "{{config}}":
+					args[i] = filepath.Join(configDir, "gh-rr.yml")
+				case "{{other}}":
+					args[i] = other
+				}
+			}
+
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+
+			var calls [][]string
+
+			got := run(
+				append([]string{"--config-dir", configDir}, args...),
+				stdout,
+				stderr,
+				fakeDiffConfigGh(t, files, &calls),
+			)
+
+			if got != tt.exit {
+				t.Errorf("run() = %v, want %v", got, tt.exit)
+			}
+
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stdout))
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stderr))
+			snaps.MatchJSON(t, calls)
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// configTemplate is what a config is created with when it is being edited for
+// the first time, to give a starting point
+const configTemplate = `# see https://github.com/G-Rath/gh-rr#usage for everything that can be configured
+repositories:
+  # octocat/hello-world:
+  #   default:
+  #     - octocat
+  #   infra:
+  #     - octodog
+  #     - octopus
+`
+
+type editConfigOptions struct {
+	file   string
+	editor string
+}
+
+// findEditor returns the editor that should be used, preferring $VISUAL over
+// $EDITOR like most tools do, and falling back to one that is likely installed
+func findEditor() string {
+	for _, name := range []string{"VISUAL", "EDITOR"} {
+		if editor := os.Getenv(name); editor != "" {
+			return editor
+		}
+	}
+
+	if runtime.GOOS == "windows" {
+		return "notepad"
+	}
+
+	return "nano"
+}
+
+// editConfig opens the config in an editor, creating it first if it does not
+// exist, and then checks that the edited config can still be parsed
+func editConfig(stdout, stderr io.Writer, opts editConfigOptions) int {
+	if _, err := os.Stat(opts.file); errors.Is(err, os.ErrNotExist) {
+		if err := os.MkdirAll(filepath.Dir(opts.file), 0700); err != nil {
+			fmt.Fprintf(stderr, "could not create config: %v\n", err)
+
+			return 1
+		}
+
+		if err := os.WriteFile(opts.file, []byte(configTemplate), 0600); err != nil {
+			fmt.Fprintf(stderr, "could not create config: %v\n", err)
+
+			return 1
+		}
+
+		fmt.Fprintf(stdout, "created %s\n", opts.file)
+	}
+
+	// editors can be given with arguments, like "code --wait"
+	args := strings.Fields(opts.editor)
+
+	if len(args) == 0 {
+		fmt.Fprintln(stderr, "please set $EDITOR to the editor you want to use")
+
+		return 1
+	}
+
+	cmd := exec.Command(args[0], append(args[1:], opts.file)...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+
+	if err := cmd.Run(); err != nil {
+		fmt.Fprintf(stderr, "could not edit %s with %s: %v\n", opts.file, args[0], err)
+
+		return 1
+	}
+
+	if _, err := parseConfig(opts.file); err != nil {
+		printParseConfigError(stderr, opts.file, err)
+
+		return 1
+	}
+
+	return 0
+}
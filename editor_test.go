@@ -0,0 +1,125 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+// writeFakeEditor creates a fake editor executable which appends the given
+// content to the file it is opening, and then exits with the given code
+func writeFakeEditor(t *testing.T, content string, code string) string {
+	t.Helper()
+
+	binDir := writeConfigFileInTempDir(t, "")
+
+	script := "#!/bin/sh\nfor file; do :; done\ncat \"$(dirname \"$0\")/append\" >> \"$file\"\nexit " + code + "\n"
+
+	err := os.WriteFile(filepath.Join(binDir, "editor"), []byte(script), 0700) //nolint:gosec // it needs to be executable
+	if err != nil {
+		t.Fatalf("could not create fake editor: %v", err)
+	}
+
+	err = os.WriteFile(filepath.Join(binDir, "append"), []byte(content), 0600)
+	if err != nil {
+		t.Fatalf("could not create fake editor: %v", err)
+	}
+
+	return filepath.Join(binDir, "editor")
+}
+
+func Test_editConfig(t *testing.T) {
+	t.Parallel()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake editor is a shell script")
+	}
+
+	tests := []struct {
+		name   string
+		config string
+		append string
+		code   string
+		args   string
+		noEdit bool
+		exit   int
+	}{
+		{
+			name:   "when the config exists",
+			config: "repositories:\n  octocat/hello-world: [octocat]\n",
+			append: "  octocat/spoon-knife: [octopus]\n",
+			code:   "0",
+			exit:   0,
+		},
+		{
+			name:   "when the config does not exist",
+			config: "",
+			append: "",
+			code:   "0",
+			exit:   0,
+		},
+		{
+			name:   "when the editor is given with arguments",
+			config: "repositories:\n  octocat/hello-world: [octocat]\n",
+			append: "",
+			args:   " --wait",
+			code:   "0",
+			exit:   0,
+		},
+		{
+			name:   "when the edited config is invalid",
+			config: "repositories:\n  octocat/hello-world: [octocat]\n",
+			append: "  octocat/spoon-knife: [\n",
+			code:   "0",
+			exit:   1,
+		},
+		{
+			name:   "when the editor fails",
+			config: "repositories:\n  octocat/hello-world: [octocat]\n",
+			append: "",
+			code:   "3",
+			exit:   1,
+		},
+		{
+			name:   "when there is no editor",
+			config: "repositories:\n  octocat/hello-world: [octocat]\n",
+			noEdit: true,
+			exit:   1,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			configDir := writeConfigFileInTempDir(t, tt.config)
+			editor := ""
+
+			if !tt.noEdit {
+				editor = writeFakeEditor(t, tt.append, tt.code) + tt.args
+			}
+
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+
+			got := editConfig(stdout, stderr, editConfigOptions{
+				file:   filepath.Join(configDir, "gh-rr.yml"),
+				editor: editor,
+			})
+
+			if got != tt.exit {
+				t.Errorf("editConfig() = %v, want %v", got, tt.exit)
+			}
+
+			config, _ := os.ReadFile(filepath.Join(configDir, "gh-rr.yml"))
+
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stdout))
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stderr))
+			snaps.MatchSnapshot(t, string(config))
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// resolveExceptTeams returns the logins of the members of the given teams,
+// which must be in the ORG/SLUG format, mapped to the team they are a member of
+func resolveExceptTeams(cache *teamMemberCache, teams []string) (map[string]string, error) {
+	excepted := make(map[string]string)
+
+	for _, team := range teams {
+		team = strings.TrimPrefix(team, "@")
+
+		if org, slug, ok := strings.Cut(team, "/"); !ok || org == "" || slug == "" || strings.Contains(slug, "/") {
+			return nil, fmt.Errorf("teams to exclude must be in the format of <org>/<team>, not `%s`", team)
+		}
+
+		members, _, err := cache.membersOf(team)
+
+		if err != nil {
+			return nil, err
+		}
+
+		for _, member := range members {
+			if _, ok := excepted[strings.ToLower(member)]; !ok {
+				excepted[strings.ToLower(member)] = team
+			}
+		}
+	}
+
+	return excepted, nil
+}
+
+// excludeTeamMembers returns the reviewers without anyone who is a member of
+// the excepted teams, explaining who was skipped and why if requested
+func excludeTeamMembers(stderr io.Writer, reviewers []reviewer, excepted map[string]string, explain bool) []reviewer {
+	kept := make([]reviewer, 0, len(reviewers))
+
+	for _, r := range reviewers {
+		team, ok := excepted[strings.ToLower(r.Handle)]
+
+		if !ok {
+			kept = append(kept, r)
+
+			continue
+		}
+
+		if explain {
+			fmt.Fprintf(stderr, "skipping %s as they are a member of %s\n", r.Handle, team)
+		}
+	}
+
+	return kept
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+func Test_run_ExceptTeams(t *testing.T) {
+	t.Parallel()
+
+	responses := map[string]string{
+		"orgs/octo-org/teams/release-freeze/members?role=all": `[{"login":"OctoPus"}]`,
+		"orgs/octo-org/teams/support/members?role=all":        `[{"login":"octodog"},{"login":"octopus"}]`,
+	}
+
+	tests := []struct {
+		name   string
+		args   []string
+		config string
+		exit   int
+	}{
+		{
+			name: "when excepting a team",
+			args: []string{"--except-team", "octo-org/release-freeze", "123"},
+			exit: 0,
+		},
+		{
+			name: "when excepting multiple teams and explaining",
+			args: []string{"--except-team", "octo-org/release-freeze", "--except-team", "@octo-org/support", "--explain", "123"},
+			exit: 0,
+		},
+		{
+			name: "when excepting teams in the config",
+			args: []string{"--explain", "123"},
+			config: `
+				settings:
+					except_teams: [octo-org/support]
+			`,
+			exit: 0,
+		},
+		{
+			name: "when the flag overrides the config",
+			args: []string{"--except-team", "octo-org/release-freeze", "123"},
+			config: `
+				settings:
+					except_teams: [octo-org/support]
+			`,
+			exit: 0,
+		},
+		{
+			name: "when everyone is excepted and there is a fallback",
+			args: []string{"--except-team", "octo-org/support", "--from", "small", "123"},
+			exit: 0,
+		},
+		{
+			name: "when the team is not in the right format",
+			args: []string{"--except-team", "octodog", "123"},
+			exit: 1,
+		},
+		{
+			name: "when the team cannot be found",
+			args: []string{"--except-team", "octo-org/missing", "123"},
+			exit: 1,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			configDir := writeConfigFileInTempDir(t, dedent(t, tt.config+`
+				repositories:
+					octocat/hello-world:
+						default: [octocat, octodog, octopus]
+						small:
+							reviewers: [octodog]
+							fallback: [fallback]
+						fallback: [octodog, octokitten]
+			`))
+
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+
+			var calls [][]string
+
+			got := run(
+				append([]string{"--config-dir", configDir, "--repo", "octocat/hello-world"}, tt.args...),
+				stdout,
+				stderr,
+				fakeTeamsGh(t, responses, &calls),
+			)
+
+			if got != tt.exit {
+				t.Errorf("run() = %v, want %v", got, tt.exit)
+			}
+
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stdout))
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stderr))
+			snaps.MatchJSON(t, calls)
+		})
+	}
+}
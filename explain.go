@@ -0,0 +1,276 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+)
+
+// configContribution is what a config contributes to the groups of a
+// repository, both those of the repository itself and the global groups
+type configContribution struct {
+	source configSource
+	label  string
+	conf   config
+	groups map[string]group
+	owner  map[string]group
+	global map[string]group
+}
+
+// describe returns a human-friendly description of where the config came from
+func (c configContribution) describe() string {
+	if c.source.includedBy != "" {
+		return fmt.Sprintf("%s (included by %s)", c.source.file, c.source.includedBy)
+	}
+
+	return fmt.Sprintf("%s (%s)", c.source.file, c.label)
+}
+
+// sortedNames returns the keys of the given map in alphabetical order
+func sortedNames[T any](m map[string]T) []string {
+	names := make([]string, 0, len(m))
+
+	for name := range m {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+
+	return names
+}
+
+// collectContributions returns what each of the layered configs contribute to
+// the groups of the given repository, in order of precedence from lowest to
+// highest, along with the key the groups of the repository are resolved from;
+// each config is resolved the same way as when requesting reviews, including
+// the config being extended, the selected profile, and the section for the host
+func collectContributions(opts explainOptions) ([]configContribution, string, error) {
+	var contributions []configContribution
+
+	// the shared config is restricted unless the personal config trusts it
+	trusted := false
+
+	for _, layer := range []struct{ file, label string }{
+		{opts.personal, "personal"},
+		{opts.shared, "shared"},
+		{opts.extra, "given with --config"},
+	} {
+		if layer.file == "" {
+			continue
+		}
+
+		sources, err := collectConfigSources(layer.file, nil)
+
+		if errors.Is(err, os.ErrNotExist) && layer.file != opts.extra {
+			continue
+		}
+
+		if err != nil {
+			return nil, "", err
+		}
+
+		for _, source := range sources {
+			conf := source.contribution()
+
+			if layer.file == opts.shared && !trusted {
+				conf = restrictSharedConfig(conf)
+			}
+
+			contributions = append(contributions, configContribution{
+				source: source,
+				label:  layer.label,
+				conf:   conf,
+			})
+		}
+
+		if layer.file == opts.personal {
+			trusted = sources[len(sources)-1].conf.Settings.TrustSharedConfig
+		}
+	}
+
+	// the config being extended has the lowest precedence of them all
+	if opts.extends != "" {
+		contributions = append([]configContribution{{
+			source: configSource{file: opts.extends},
+			label:  "extended",
+			conf:   opts.extended,
+		}}, contributions...)
+	}
+
+	if opts.profile != "" {
+		var selected []configContribution
+
+		for _, contribution := range contributions {
+			conf, err := selectProfile(contribution.conf, opts.profile)
+
+			if err != nil {
+				continue
+			}
+
+			// profiles replace each other entirely rather than being merged
+			contribution.conf = conf
+			selected = []configContribution{contribution}
+		}
+
+		contributions = selected
+	}
+
+	merged := repositories{}
+
+	for i := range contributions {
+		contributions[i].conf = resolveHostConfig(contributions[i].conf, opts.host, opts.repo)
+		merged = mergeRepositories(merged, contributions[i].conf.Repositories)
+	}
+
+	key := strings.ToLower(opts.repo)
+
+	if _, ok := merged[key]; !ok {
+		if pattern, ok := matchRepositoryPattern(merged, key); ok {
+			key = pattern
+		}
+	}
+
+	for i := range contributions {
+		contributions[i].groups = contributions[i].conf.Repositories[key]
+		contributions[i].owner = contributions[i].conf.Owners[ownerOf(opts.repo)]
+		contributions[i].global = contributions[i].conf.Repositories["*"]
+	}
+
+	return contributions, key, nil
+}
+
+// groupDefinitions are the groups defined by a config for a repository
+type groupDefinitions struct {
+	file   string
+	groups map[string]group
+}
+
+// printGroupProvenance prints the config that each of the groups come from,
+// along with any configs whose group of the same name it overrides and the
+// description of the group, with the definitions being in order of precedence
+// from lowest to highest
+func printGroupProvenance(w io.Writer, definitions []groupDefinitions) {
+	definedBy := make(map[string][]string)
+	resolved := make(map[string]group)
+
+	for _, definition := range definitions {
+		for name, g := range definition.groups {
+			definedBy[name] = append(definedBy[name], definition.file)
+			resolved[name] = g
+		}
+	}
+
+	for _, name := range sortedNames(definedBy) {
+		files := definedBy[name]
+
+		fmt.Fprintf(w, "  %s from %s", name, files[len(files)-1])
+
+		if len(files) > 1 {
+			fmt.Fprintf(w, ", overriding %s", strings.Join(files[:len(files)-1], ", "))
+		}
+
+		fmt.Fprintln(w)
+
+		if description := resolved[name].Description; description != "" {
+			fmt.Fprintf(w, "    %s\n", description)
+		}
+	}
+}
+
+type explainOptions struct {
+	host     string
+	repo     string
+	personal string
+	shared   string
+	extra    string
+	extends  string
+	extended config
+	profile  string
+}
+
+// explainConfig outputs which configs contribute to the groups of the given
+// repository and in what order, along with the config that each group is
+// ultimately resolved from
+func explainConfig(stdout, stderr io.Writer, opts explainOptions) int {
+	contributions, key, err := collectContributions(opts)
+
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+
+		return 1
+	}
+
+	if opts.profile != "" {
+		fmt.Fprintf(stdout, "using the %s profile, which is only taken from the last config that defines it\n\n", opts.profile)
+	}
+
+	fmt.Fprintf(stdout, "configs for %s, from lowest to highest precedence:\n", opts.repo)
+
+	owner := ownerOf(opts.repo)
+	hasRepoGroups := false
+	hasGlobalGroups := false
+
+	var repoDefinitions, ownerDefinitions, globalDefinitions []groupDefinitions
+
+	for _, contribution := range contributions {
+		fmt.Fprintf(stdout, "  %s\n", contribution.describe())
+
+		if contribution.groups == nil && contribution.owner == nil && contribution.global == nil {
+			fmt.Fprintf(stdout, "    nothing for %s\n", opts.repo)
+		}
+
+		if contribution.owner != nil {
+			hasRepoGroups = true
+			ownerDefinitions = append(ownerDefinitions, groupDefinitions{
+				file:   fmt.Sprintf("the %s owner in %s", owner, contribution.source.file),
+				groups: contribution.owner,
+			})
+			fmt.Fprintf(stdout, "    %s (owner): %s\n", owner, strings.Join(sortedNames(contribution.owner), ", "))
+		}
+
+		if contribution.groups != nil {
+			hasRepoGroups = true
+			repoDefinitions = append(repoDefinitions, groupDefinitions{
+				file:   contribution.source.file,
+				groups: contribution.groups,
+			})
+			fmt.Fprintf(stdout, "    %s: %s\n", key, strings.Join(sortedNames(contribution.groups), ", "))
+		}
+
+		if contribution.global != nil {
+			hasGlobalGroups = true
+			globalDefinitions = append(globalDefinitions, groupDefinitions{
+				file:   contribution.source.file,
+				groups: contribution.global,
+			})
+			fmt.Fprintf(stdout, "    *: %s\n", strings.Join(sortedNames(contribution.global), ", "))
+		}
+	}
+
+	if !hasRepoGroups && !hasGlobalGroups {
+		fmt.Fprintf(stderr, "no reviewers are configured for %s\n", opts.repo)
+
+		return 1
+	}
+
+	if hasRepoGroups {
+		if isRepositoryPattern(key) {
+			fmt.Fprintf(stdout, "\ngroups for %s, from the %s pattern:\n", opts.repo, key)
+		} else {
+			fmt.Fprintf(stdout, "\ngroups for %s:\n", opts.repo)
+		}
+
+		// the groups of the repository always take precedence over those of its owner
+		printGroupProvenance(stdout, append(ownerDefinitions, repoDefinitions...))
+	}
+
+	if hasGlobalGroups {
+		fmt.Fprintln(stdout, "\nglobal groups:")
+		printGroupProvenance(stdout, globalDefinitions)
+	}
+
+	return 0
+}
@@ -0,0 +1,219 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+func Test_run_ExplainConfig(t *testing.T) {
+	t.Parallel()
+
+	files := map[string]string{
+		"teams/backend.yml": `
+			repositories:
+				octocat/hello-world:
+					default: [octodog]
+					infra: [octodog, octopus]
+		`,
+		"teams/security.yml": `
+			include: [backend.yml]
+			repositories:
+				'*':
+					security: [octokitten]
+		`,
+		"other.yml": `
+			repositories:
+				octocat/hello-world:
+					docs: [octobear]
+		`,
+		"teams/enterprise.yml": `
+			repositories:
+				octocat/hello-world:
+					infra: [octocorn]
+			hosts:
+				github.example.com:
+					repositories:
+						octocat/hello-world:
+							infra: [octobear]
+		`,
+	}
+
+	tests := []struct {
+		name   string
+		args   []string
+		config string
+		exit   int
+	}{
+		{
+			name: "when the config includes other configs",
+			args: []string{"--repo", "octocat/hello-world", "explain-config"},
+			config: `
+				include: [teams/security.yml]
+				repositories:
+					octocat/hello-world:
+						default: [octocat]
+					octocat/spoon-knife:
+						default: [octocat]
+			`,
+			exit: 0,
+		},
+		{
+			name: "when the repository is given as an argument",
+			args: []string{"explain-config", "OctoCat/Hello-World"},
+			config: `
+				include: [teams/backend.yml]
+				repositories:
+					octocat/hello-world:
+						default: [octocat]
+			`,
+			exit: 0,
+		},
+		{
+			name: "when groups have descriptions",
+			args: []string{"explain-config", "octocat/hello-world"},
+			config: `
+				include: [teams/security.yml]
+				repositories:
+					octocat/hello-world:
+						infra:
+							description: people who look after our servers
+							reviewers: [octocat]
+						docs: [octocat]
+			`,
+			exit: 0,
+		},
+		{
+			name: "when a config does not have anything for the repository",
+			args: []string{"explain-config", "octocat/spoon-knife"},
+			config: `
+				include: [teams/security.yml]
+				repositories:
+					octocat/spoon-knife:
+						default: [octocat]
+			`,
+			exit: 0,
+		},
+		{
+			name: "when a config is given with --config",
+			args: []string{"--config", "{{other}}", "explain-config", "octocat/hello-world"},
+			config: `
+				repositories:
+					octocat/hello-world:
+						default: [octocat]
+			`,
+			exit: 0,
+		},
+		{
+			name: "when using a profile",
+			args: []string{"--profile", "work", "explain-config", "octocat/hello-world"},
+			config: `
+				include: [teams/backend.yml]
+				profiles:
+					work:
+						repositories:
+							octocat/hello-world:
+								default: [octopus]
+			`,
+			exit: 0,
+		},
+		{
+			name: "when the repository is on a configured host",
+			args: []string{"--repo", "github.example.com/octocat/hello-world", "explain-config"},
+			config: `
+				include: [teams/backend.yml]
+				repositories:
+					octocat/hello-world:
+						default: [octocat]
+				hosts:
+					github.example.com:
+						repositories:
+							octocat/hello-world:
+								default: [octopus]
+			`,
+			exit: 0,
+		},
+		{
+			name: "when an included config has a section for the host",
+			args: []string{"--repo", "github.example.com/octocat/hello-world", "explain-config"},
+			config: `
+				include: [teams/enterprise.yml]
+				hosts:
+					github.example.com:
+						repositories:
+							octocat/hello-world:
+								default: [octocat]
+			`,
+			exit: 0,
+		},
+		{
+			name: "when there are no reviewers for the repository",
+			args: []string{"explain-config", "octocat/spoon-knife"},
+			config: `
+				include: [teams/backend.yml]
+			`,
+			exit: 1,
+		},
+		{
+			name: "when an included config is invalid",
+			args: []string{"explain-config", "octocat/hello-world"},
+			config: `
+				include: [teams/missing.yml]
+			`,
+			exit: 1,
+		},
+		{
+			name: "when explaining multiple repositories",
+			args: []string{"explain-config", "octocat/hello-world", "octocat/spoon-knife"},
+			config: `
+				include: [teams/backend.yml]
+			`,
+			exit: 1,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			configDir := writeConfigFileInTempDir(t, dedent(t, tt.config))
+
+			for name, content := range files {
+				p := filepath.Join(configDir, filepath.FromSlash(name))
+
+				if err := os.MkdirAll(filepath.Dir(p), 0750); err != nil {
+					t.Fatal(err)
+				}
+
+				if err := os.WriteFile(p, []byte(dedent(t, content)), 0600); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			args := []string{"--config-dir", configDir}
+
+			for _, arg := range tt.args {
+				if arg == "{{other}}" {
+					arg = filepath.Join(configDir, "other.yml")
+				}
+
+				args = append(args, arg)
+			}
+
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+
+			got := run(args, stdout, stderr, expectNoCallToGh(t))
+
+			if got != tt.exit {
+				t.Errorf("run() = %v, want %v", got, tt.exit)
+			}
+
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stdout))
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stderr))
+		})
+	}
+}
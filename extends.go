@@ -0,0 +1,130 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// groupReferencePrefix is used within the reviewers of a group to include the
+// reviewers of another group of the same repository
+const groupReferencePrefix = "@group:"
+
+// referencesGroups checks if the group includes the reviewers of other groups,
+// either by extending them or by referencing them in its reviewers
+func referencesGroups(g group) bool {
+	return len(g.Extends) > 0 || slices.ContainsFunc(g.Reviewers, func(r reviewer) bool {
+		return strings.HasPrefix(r.Handle, groupReferencePrefix)
+	})
+}
+
+// resolveGroupReferences returns the repositories with each group that extends
+// or references other groups having the reviewers of those groups in place;
+// groups that cannot be resolved are marked with why, so that they only get in
+// the way when they are actually used
+func resolveGroupReferences(repos repositories) repositories {
+	resolved := make(repositories, len(repos))
+
+	for _, repo := range sortedNames(repos) {
+		groups := make(map[string]group, len(repos[repo]))
+
+		for _, name := range sortedNames(repos[repo]) {
+			g := repos[repo][name]
+
+			if referencesGroups(g) {
+				reviewers, err := expandGroupReferences(repo, repos[repo], name, nil)
+
+				g.Reviewers = reviewers
+				g.referenceErr = err
+			}
+
+			groups[name] = g
+		}
+
+		resolved[repo] = groups
+	}
+
+	return resolved
+}
+
+// findGroupReferenceError returns the first problem with resolving the groups
+// of any repository, for when every repository is being used
+func findGroupReferenceError(repos repositories) error {
+	for _, repo := range sortedNames(repos) {
+		for _, name := range sortedNames(repos[repo]) {
+			if err := repos[repo][name].referenceErr; err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// expandGroupReferences returns the reviewers of the named group, with those
+// of the groups that it extends coming first and those of the groups that it
+// references being in place of the reference, without any duplicates
+func expandGroupReferences(repo string, groups map[string]group, name string, including []string) ([]reviewer, error) {
+	if slices.Contains(including, name) {
+		return nil, fmt.Errorf(
+			"the %s group of %s cannot include itself (%s)",
+			including[0],
+			repo,
+			strings.Join(append(including, name), " -> "),
+		)
+	}
+
+	including = append(slices.Clone(including), name)
+
+	var reviewers []reviewer
+
+	expand := func(other, relation string) error {
+		if _, ok := groups[other]; !ok {
+			return fmt.Errorf("the %s group of %s %s the %s group, which does not exist", name, repo, relation, other)
+		}
+
+		included, err := expandGroupReferences(repo, groups, other, including)
+
+		if err != nil {
+			return err
+		}
+
+		reviewers = append(reviewers, included...)
+
+		return nil
+	}
+
+	for _, parent := range groups[name].Extends {
+		if err := expand(parent, "extends"); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, r := range groups[name].Reviewers {
+		other, ok := strings.CutPrefix(r.Handle, groupReferencePrefix)
+
+		if !ok {
+			reviewers = append(reviewers, r)
+
+			continue
+		}
+
+		if err := expand(other, "references"); err != nil {
+			return nil, err
+		}
+	}
+
+	seen := make(map[string]bool, len(reviewers))
+	unique := make([]reviewer, 0, len(reviewers))
+
+	for _, r := range reviewers {
+		if seen[strings.ToLower(r.Handle)] {
+			continue
+		}
+
+		seen[strings.ToLower(r.Handle)] = true
+		unique = append(unique, r)
+	}
+
+	return unique, nil
+}
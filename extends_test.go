@@ -0,0 +1,267 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+func Test_run_WithExtends(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		args   []string
+		config string
+		exit   int
+	}{
+		{
+			name: "when a group extends another group",
+			args: []string{"--from", "security", "123"},
+			config: `
+				repositories:
+					octocat/hello-world:
+						default: [octocat, octodog]
+						security:
+							extends: [default]
+							reviewers: [octokitten, OctoCat]
+			`,
+			exit: 0,
+		},
+		{
+			name: "when a group extends multiple groups",
+			args: []string{"--from", "everyone", "123"},
+			config: `
+				repositories:
+					octocat/hello-world:
+						default: [octocat]
+						infra: [octodog, octocat]
+						everyone:
+							extends: [default, infra]
+			`,
+			exit: 0,
+		},
+		{
+			name: "when a group extends a group that extends another group",
+			args: []string{"--from", "security", "123"},
+			config: `
+				repositories:
+					octocat/hello-world:
+						default: [octocat]
+						infra:
+							extends: [default]
+							reviewers: [octodog]
+						security:
+							extends: [infra]
+							reviewers: [octokitten]
+			`,
+			exit: 0,
+		},
+		{
+			name: "when a group extends a group of the owner",
+			args: []string{"--from", "security", "123"},
+			config: `
+				owners:
+					octocat:
+						default: [octocat]
+				repositories:
+					octocat/hello-world:
+						security:
+							extends: [default]
+							reviewers: [octokitten]
+			`,
+			exit: 0,
+		},
+		{
+			name: "when a global group extends another global group",
+			args: []string{"-g", "--from", "security", "123"},
+			config: `
+				repositories:
+					'*':
+						default: [octocat]
+						security:
+							extends: [default]
+							reviewers: [octokitten]
+			`,
+			exit: 0,
+		},
+		{
+			name: "when a group references other groups",
+			args: []string{"--from", "everyone", "123"},
+			config: `
+				repositories:
+					octocat/hello-world:
+						frontend: [octocat, octodog]
+						backend: [octopus, octocat]
+						everyone: [octobear, '@group:frontend', '@group:backend']
+			`,
+			exit: 0,
+		},
+		{
+			name: "when a group references a group that references another group",
+			args: []string{"--from", "everyone", "123"},
+			config: `
+				repositories:
+					octocat/hello-world:
+						frontend: [octocat]
+						web: ['@group:frontend', octodog]
+						everyone:
+							extends: [default]
+							reviewers: ['@group:web']
+						default: [octokitten]
+			`,
+			exit: 0,
+		},
+		{
+			name: "when a group references a group that does not exist",
+			args: []string{"123"},
+			config: `
+				repositories:
+					octocat/hello-world:
+						default: [octocat, '@group:frontend']
+			`,
+			exit: 1,
+		},
+		{
+			name: "when groups reference each other",
+			args: []string{"--from", "frontend", "123"},
+			config: `
+				repositories:
+					octocat/hello-world:
+						frontend: [octocat, '@group:web']
+						web: [octodog, '@group:frontend']
+			`,
+			exit: 1,
+		},
+		{
+			name: "when a group references itself",
+			args: []string{"123"},
+			config: `
+				repositories:
+					octocat/hello-world:
+						default: [octocat, '@group:default']
+			`,
+			exit: 1,
+		},
+		{
+			name: "when groups of another repository reference each other",
+			args: []string{"123"},
+			config: `
+				repositories:
+					octocat/hello-world:
+						default: [octocat]
+					octocat/spoon-knife:
+						x: ['@group:y']
+						y: ['@group:x']
+			`,
+			exit: 0,
+		},
+		{
+			name: "when another group of the repository references a group that does not exist",
+			args: []string{"123"},
+			config: `
+				repositories:
+					octocat/hello-world:
+						default: [octocat]
+						everyone: ['@group:default', '@group:infra']
+			`,
+			exit: 0,
+		},
+		{
+			name: "when listing groups",
+			args: []string{"groups"},
+			config: `
+				repositories:
+					octocat/hello-world:
+						default: [octocat]
+						security:
+							extends: [default]
+							reviewers: [octokitten]
+			`,
+			exit: 0,
+		},
+		{
+			name: "when a group extends a group that does not exist",
+			args: []string{"123"},
+			config: `
+				repositories:
+					octocat/hello-world:
+						default:
+							extends: [infra]
+							reviewers: [octocat]
+			`,
+			exit: 1,
+		},
+		{
+			name: "when groups of another repository extend each other",
+			args: []string{"123"},
+			config: `
+				repositories:
+					octocat/hello-world:
+						default: [octocat]
+					octocat/spoon-knife:
+						default:
+							extends: [security]
+							reviewers: [octocat]
+						security:
+							extends: [default]
+							reviewers: [octokitten]
+			`,
+			exit: 0,
+		},
+		{
+			name: "when another group of the repository extends a group that does not exist",
+			args: []string{"123"},
+			config: `
+				repositories:
+					octocat/hello-world:
+						default: [octocat]
+						security:
+							extends: [infra]
+							reviewers: [octokitten]
+			`,
+			exit: 0,
+		},
+		{
+			name: "when groups extend each other",
+			args: []string{"123"},
+			config: `
+				repositories:
+					octocat/hello-world:
+						default:
+							extends: [security]
+							reviewers: [octocat]
+						security:
+							extends: [default]
+							reviewers: [octokitten]
+			`,
+			exit: 1,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			configDir := writeConfigFileInTempDir(t, dedent(t, tt.config))
+
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+
+			got := run(
+				append([]string{"--config-dir", configDir, "--repo", "octocat/hello-world"}, tt.args...),
+				stdout,
+				stderr,
+				expectCallToGh(t, "octocat/hello-world", "123"),
+			)
+
+			if got != tt.exit {
+				t.Errorf("run() = %v, want %v", got, tt.exit)
+			}
+
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stdout))
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stderr))
+		})
+	}
+}
@@ -0,0 +1,202 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+type orgTeam struct {
+	Slug        string `json:"slug"`
+	Description string `json:"description"`
+}
+
+type teamRepository struct {
+	FullName    string `json:"full_name"`
+	Permissions struct {
+		Push bool `json:"push"`
+	} `json:"permissions"`
+}
+
+// fetchOrgTeams returns every team in the given organization
+func fetchOrgTeams(ghExec ghExecutor, org string) ([]orgTeam, error) {
+	out, errMsg := ghExec("api", fmt.Sprintf("orgs/%s/teams", org), "--paginate")
+
+	if errMsg != "" {
+		return nil, fmt.Errorf("could not get the teams of %s: %s", org, strings.TrimSpace(errMsg))
+	}
+
+	teams, err := decodePages[orgTeam](out)
+
+	if err != nil {
+		return nil, fmt.Errorf("could not parse the teams of %s: %w", org, err)
+	}
+
+	return teams, nil
+}
+
+// fetchTeamRepositories returns the repositories that the given team can push to
+func fetchTeamRepositories(ghExec ghExecutor, org, slug string) ([]string, error) {
+	out, errMsg := ghExec("api", fmt.Sprintf("orgs/%s/teams/%s/repos", org, slug), "--paginate")
+
+	if errMsg != "" {
+		return nil, fmt.Errorf("could not get the repositories of %s/%s: %s", org, slug, strings.TrimSpace(errMsg))
+	}
+
+	repos, err := decodePages[teamRepository](out)
+
+	if err != nil {
+		return nil, fmt.Errorf("could not parse the repositories of %s/%s: %w", org, slug, err)
+	}
+
+	names := make([]string, 0, len(repos))
+
+	for _, repo := range repos {
+		// teams that can only read a repository are not going to be reviewing it
+		if repo.Permissions.Push {
+			names = append(names, strings.ToLower(repo.FullName))
+		}
+	}
+
+	return names, nil
+}
+
+func scalarNode(value string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+}
+
+// buildGeneratedConfig creates a config document that has a group for each
+// team that can push to each repository, made up of the members of that team
+func buildGeneratedConfig(org string, teams []orgTeam, teamRepos, teamMembers map[string][]string) *yaml.Node {
+	groupsByRepo := make(map[string][]orgTeam)
+
+	for _, team := range teams {
+		for _, repo := range teamRepos[team.Slug] {
+			groupsByRepo[repo] = append(groupsByRepo[repo], team)
+		}
+	}
+
+	repoNames := make([]string, 0, len(groupsByRepo))
+
+	for repo := range groupsByRepo {
+		repoNames = append(repoNames, repo)
+	}
+
+	slices.Sort(repoNames)
+
+	repos := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+
+	for _, repo := range repoNames {
+		groups := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+
+		for _, team := range groupsByRepo[repo] {
+			reviewers := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+
+			for _, member := range teamMembers[team.Slug] {
+				reviewers.Content = append(reviewers.Content, scalarNode(member))
+			}
+
+			g := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+
+			if team.Description != "" {
+				g.Content = append(g.Content, scalarNode("description"), scalarNode(team.Description))
+			}
+
+			g.Content = append(
+				g.Content,
+				scalarNode("team"), scalarNode(org+"/"+team.Slug),
+				scalarNode("reviewers"), reviewers,
+			)
+			groups.Content = append(groups.Content, scalarNode(team.Slug), g)
+		}
+
+		repos.Content = append(repos.Content, scalarNode(repo), groups)
+	}
+
+	root := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	root.Content = append(root.Content, scalarNode("repositories"), repos)
+	root.HeadComment = fmt.Sprintf(
+		"generated from the teams of %s using `gh rr generate`\n"+
+			"each group is named after a team, so you will probably want to rename\n"+
+			"one of them in each repository to \"default\"",
+		org,
+	)
+
+	return &yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{root}}
+}
+
+// generateConfig outputs a config made up of groups derived from the teams of
+// the given organization, as a starting point for configuring large orgs
+func generateConfig(stdout, stderr io.Writer, ghExec ghExecutor, org string) int {
+	if org == "" {
+		fmt.Fprintln(stderr, "please provide the organization to generate a config for with --org")
+
+		return 1
+	}
+
+	teams, err := fetchOrgTeams(ghExec, org)
+
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+
+		return 1
+	}
+
+	slices.SortFunc(teams, func(a, b orgTeam) int {
+		return strings.Compare(a.Slug, b.Slug)
+	})
+
+	teamRepos := make(map[string][]string, len(teams))
+	teamMembers := make(map[string][]string, len(teams))
+
+	for _, team := range teams {
+		repos, err := fetchTeamRepositories(ghExec, org, team.Slug)
+
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+
+			return 1
+		}
+
+		// there is no point finding the members of teams without any repositories
+		if len(repos) == 0 {
+			continue
+		}
+
+		members, err := fetchTeamMembers(ghExec, org, team.Slug, "all")
+
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+
+			return 1
+		}
+
+		teamRepos[team.Slug] = repos
+		teamMembers[team.Slug] = members
+	}
+
+	var buf bytes.Buffer
+
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+
+	if err := encoder.Encode(buildGeneratedConfig(org, teams, teamRepos, teamMembers)); err != nil {
+		fmt.Fprintf(stderr, "could not encode config: %v\n", err)
+
+		return 1
+	}
+
+	if err := encoder.Close(); err != nil {
+		fmt.Fprintf(stderr, "could not encode config: %v\n", err)
+
+		return 1
+	}
+
+	fmt.Fprint(stdout, buf.String())
+
+	return 0
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+func Test_run_Generate(t *testing.T) {
+	t.Parallel()
+
+	responses := map[string]string{
+		"orgs/octo-org/teams": `[{"slug":"platform","description":"keeps the lights on"}][{"slug":"backend","description":""},{"slug":"readers","description":"can only look"}]`,
+		"orgs/octo-org/teams/backend/repos": `[
+			{"full_name":"octo-org/Billing","permissions":{"push":true}},
+			{"full_name":"octo-org/web-app","permissions":{"push":false}}
+		]`,
+		"orgs/octo-org/teams/platform/repos": `[
+			{"full_name":"octo-org/billing","permissions":{"push":true}},
+			{"full_name":"octo-org/infra","permissions":{"push":true}}
+		]`,
+		"orgs/octo-org/teams/readers/repos":             `[{"full_name":"octo-org/billing","permissions":{"push":false}}]`,
+		"orgs/octo-org/teams/backend/members?role=all":  `[{"login":"octocat"},{"login":"priyak"}]`,
+		"orgs/octo-org/teams/platform/members?role=all": `[{"login":"octodog"}]`,
+		"orgs/empty-org/teams":                          `[]`,
+		"orgs/broken-org/teams":                         `[{"slug":"nope","description":""}]`,
+	}
+
+	tests := []struct {
+		name string
+		args []string
+		exit int
+	}{
+		{
+			name: "when generating a config for an organization",
+			args: []string{"generate", "--org", "octo-org"},
+			exit: 0,
+		},
+		{
+			name: "when the organization does not have any teams",
+			args: []string{"generate", "--org", "empty-org"},
+			exit: 0,
+		},
+		{
+			name: "when the organization does not exist",
+			args: []string{"generate", "--org", "nope-org"},
+			exit: 1,
+		},
+		{
+			name: "when the repositories of a team cannot be fetched",
+			args: []string{"generate", "--org", "broken-org"},
+			exit: 1,
+		},
+		{
+			name: "when no organization is given",
+			args: []string{"generate"},
+			exit: 1,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+
+			var calls [][]string
+
+			got := run(tt.args, stdout, stderr, fakeTeamsGh(t, responses, &calls))
+
+			if got != tt.exit {
+				t.Errorf("run() = %v, want %v", got, tt.exit)
+			}
+
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stdout))
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stderr))
+			snaps.MatchJSON(t, calls)
+		})
+	}
+}
@@ -0,0 +1,272 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+// printGroups writes the given groups in alphabetical order, along with their
+// descriptions and reviewers
+func printGroups(w io.Writer, groups map[string]group) {
+	names := make([]string, 0, len(groups))
+
+	for name := range groups {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+
+	for _, name := range names {
+		g := groups[name]
+
+		if g.Description == "" {
+			fmt.Fprintf(w, "  %s\n", name)
+		} else {
+			fmt.Fprintf(w, "  %s - %s\n", name, g.Description)
+		}
+
+		for _, reviewer := range g.Reviewers {
+			fmt.Fprintf(w, "    - %s\n", reviewer)
+		}
+	}
+}
+
+// listGroups prints the groups that are available for the given repository,
+// including the global groups
+func listGroups(stdout, stderr io.Writer, conf config, repo string) int {
+	groups, hasRepoGroups := conf.Repositories[strings.ToLower(repo)]
+	globalGroups, hasGlobalGroups := conf.Repositories["*"]
+
+	if !hasRepoGroups && !hasGlobalGroups {
+		fmt.Fprintf(stderr, "no reviewers are configured for %s\n", repo)
+
+		return 1
+	}
+
+	if hasRepoGroups {
+		fmt.Fprintf(stdout, "groups for %s:\n", repo)
+		printGroups(stdout, groups)
+	}
+
+	if hasGlobalGroups {
+		if hasRepoGroups {
+			fmt.Fprintln(stdout)
+		}
+
+		fmt.Fprintln(stdout, "global groups:")
+		printGroups(stdout, globalGroups)
+	}
+
+	return 0
+}
+
+// findGroupInAnyRepository looks for a group with the given name in every
+// configured repository, preferring the global groups if they have it, and
+// returns the repository it was found in along with its reviewers
+//
+// if multiple repositories define the group differently then there is no way
+// of knowing which one is wanted, so an error is returned instead
+func findGroupInAnyRepository(conf config, name string) (string, []reviewer, error) {
+	if g, ok := conf.Repositories["*"][name]; ok {
+		return "*", g.Reviewers, g.referenceErr
+	}
+
+	repos := make([]string, 0, len(conf.Repositories))
+
+	for repo, groups := range conf.Repositories {
+		if _, ok := groups[name]; ok {
+			repos = append(repos, repo)
+		}
+	}
+
+	if len(repos) == 0 {
+		return "", nil, errGroupNotConfigured
+	}
+
+	slices.Sort(repos)
+
+	for _, repo := range repos {
+		if err := conf.Repositories[repo][name].referenceErr; err != nil {
+			return "", nil, err
+		}
+	}
+
+	reviewers := conf.Repositories[repos[0]][name].Reviewers
+
+	for _, repo := range repos[1:] {
+		other := conf.Repositories[repo][name].Reviewers
+
+		if !slices.EqualFunc(reviewers, other, func(a, b reviewer) bool {
+			return strings.EqualFold(a.Handle, b.Handle)
+		}) {
+			return "", nil, fmt.Errorf(
+				"the %s group is defined differently by multiple repositories: %s",
+				name,
+				strings.Join(repos, ", "),
+			)
+		}
+	}
+
+	return repos[0], reviewers, nil
+}
+
+// alwaysGroup is the name of the group whose members are included in every
+// request for reviews on a repository, regardless of the group being used
+const alwaysGroup = "always"
+
+// includeAlwaysGroup adds the members of the "always" group of the repository
+// to the reviewers if they are not already present, returning the handles of
+// those that were added
+func includeAlwaysGroup(conf config, repo, group string, reviewers []reviewer) ([]reviewer, map[string]bool, error) {
+	always, ok := conf.Repositories[strings.ToLower(repo)][alwaysGroup]
+
+	if !ok || group == alwaysGroup {
+		return reviewers, nil, nil
+	}
+
+	if always.referenceErr != nil {
+		return nil, nil, always.referenceErr
+	}
+
+	added := make(map[string]bool)
+	reviewers = slices.Clone(reviewers)
+
+	for _, r := range withMethod(always.Reviewers, always.Method) {
+		if slices.ContainsFunc(reviewers, func(existing reviewer) bool {
+			return strings.EqualFold(existing.Handle, r.Handle)
+		}) {
+			continue
+		}
+
+		reviewers = append(reviewers, r)
+		added[strings.ToLower(r.Handle)] = true
+	}
+
+	return reviewers, added, nil
+}
+
+// resolveFallback returns the first group in the fallback chain of the given
+// group that has any reviewers, along with those reviewers
+func resolveFallback(ghExec ghExecutor, conf config, repo, group string) (string, []reviewer, error) {
+	repo = strings.ToLower(repo)
+
+	for _, name := range conf.Repositories[repo][group].Fallback {
+		reviewers, err := determineReviewers(conf, repo, name)
+
+		if errors.Is(err, errGroupNotConfigured) {
+			return "", nil, fmt.Errorf("the %s group falls back to the %s group, which does not exist", group, name)
+		}
+
+		if err != nil {
+			return "", nil, err
+		}
+
+		reviewers, err = expandDynamicReviewers(ghExec, reviewers)
+
+		if err != nil {
+			return "", nil, err
+		}
+
+		if len(reviewers) > 0 {
+			return name, reviewers, nil
+		}
+	}
+
+	return "", nil, nil
+}
+
+// fetchCurrentUser returns the login of the user that gh is authenticated as
+func fetchCurrentUser(ghExec ghExecutor) (string, error) {
+	out, errMsg := ghExec("api", "user", "--jq", ".login")
+
+	if errMsg != "" {
+		return "", fmt.Errorf("could not determine the current user: %s", strings.TrimSpace(errMsg))
+	}
+
+	return strings.TrimSpace(out), nil
+}
+
+// inferGroupFromTeams returns the first group (by name) in the repository that
+// is linked to a team which the given user is a member of, along with the team
+func inferGroupFromTeams(ghExec ghExecutor, conf config, repo, login string) (string, string, error) {
+	groups := conf.Repositories[strings.ToLower(repo)]
+	names := make([]string, 0, len(groups))
+
+	for name, g := range groups {
+		if g.Team != "" {
+			names = append(names, name)
+		}
+	}
+
+	slices.Sort(names)
+
+	cache := &teamMemberCache{ghExec: ghExec, members: make(map[string][]string)}
+
+	for _, name := range names {
+		team := groups[name].Team
+		members, _, err := cache.membersOf(team)
+
+		if err != nil {
+			return "", "", err
+		}
+
+		if slices.ContainsFunc(members, func(member string) bool {
+			return strings.EqualFold(member, login)
+		}) {
+			return name, team, nil
+		}
+	}
+
+	return "", "", nil
+}
+
+// excludeReviewer returns the reviewers without the given login
+func excludeReviewer(reviewers []reviewer, login string) []reviewer {
+	return slices.DeleteFunc(slices.Clone(reviewers), func(r reviewer) bool {
+		return strings.EqualFold(r.Handle, login)
+	})
+}
+
+// hasDefaultGroup checks if one of the groups has been set as the default_group
+// of its repository
+func hasDefaultGroup(groups map[string]group) bool {
+	for _, g := range groups {
+		if g.isDefault {
+			return true
+		}
+	}
+
+	return false
+}
+
+// findDefaultGroup returns the group to use for the repository when a group has
+// not been requested, which is the default_group of the repository if it has
+// one, otherwise that of the settings, falling back to the "default" group
+func findDefaultGroup(conf config, repo string) string {
+	groups := conf.Repositories[strings.ToLower(repo)]
+
+	for _, name := range sortedNames(groups) {
+		if groups[name].isDefault {
+			return name
+		}
+	}
+
+	name := conf.Settings.DefaultGroup
+
+	if name == "" {
+		return "default"
+	}
+
+	// repositories that only have a group named default, such as those using the
+	// shorthand for it, keep using that group
+	if _, ok := groups[name]; !ok {
+		if _, ok := groups["default"]; ok {
+			return "default"
+		}
+	}
+
+	return name
+}
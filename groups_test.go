@@ -0,0 +1,567 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+func Test_run_FromAny(t *testing.T) {
+	t.Parallel()
+
+	config := `
+		repositories:
+			octocat/hello-world:
+				- octocat
+			octocat/spoon-knife:
+				security: [octopus, octodog]
+				infra: [octopus]
+			octocat/linguist:
+				security: [OctoPus, octodog]
+				infra: [octodog]
+	`
+
+	tests := []struct {
+		name   string
+		args   []string
+		config string
+		exit   int
+	}{
+		{
+			name:   "when the group is in another repository",
+			args:   []string{"--from-any", "--from", "security", "123"},
+			config: config,
+			exit:   0,
+		},
+		{
+			name:   "when the group is in another repository and search is enabled in the config",
+			args:   []string{"--from", "security", "123"},
+			config: "settings:\n  group_search: any\n" + dedent(t, config),
+			exit:   0,
+		},
+		{
+			name:   "when the group is in another repository but searching is not enabled",
+			args:   []string{"--from", "security", "123"},
+			config: config,
+			exit:   1,
+		},
+		{
+			name:   "when the group is in the current repository",
+			args:   []string{"--from-any", "123"},
+			config: config,
+			exit:   0,
+		},
+		{
+			name: "when the group is also a global group",
+			args: []string{"--from-any", "--from", "security", "123"},
+			config: `
+				repositories:
+					'*':
+						security: [octokitten]
+					octocat/hello-world:
+						- octocat
+					octocat/spoon-knife:
+						security: [octopus, octodog]
+			`,
+			exit: 0,
+		},
+		{
+			name:   "when the group is defined differently by multiple repositories",
+			args:   []string{"--from-any", "--from", "infra", "123"},
+			config: config,
+			exit:   1,
+		},
+		{
+			name:   "when the group is not in any repository",
+			args:   []string{"--from-any", "--from", "nope", "123"},
+			config: config,
+			exit:   1,
+		},
+		{
+			name:   "when the current repository is not configured",
+			args:   []string{"--from-any", "--repo", "octocat/octocat.github.io", "--from", "security", "123"},
+			config: config,
+			exit:   0,
+		},
+		{
+			name:   "when using the global groups",
+			args:   []string{"--from-any", "-gf", "security", "123"},
+			config: config,
+			exit:   1,
+		},
+		{
+			name:   "when the group search setting is invalid",
+			args:   []string{"--from", "security", "123"},
+			config: "settings:\n  group_search: everywhere\n" + dedent(t, config),
+			exit:   1,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			configDir := writeConfigFileInTempDir(t, dedent(t, tt.config))
+
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+
+			var ghExecArgs []string
+
+			got := run(
+				append([]string{"--config-dir", configDir, "--repo", "octocat/hello-world"}, tt.args...),
+				stdout,
+				stderr,
+				func(args ...string) (string, string) {
+					ghExecArgs = args
+
+					return "https://github.com/octocat/hello-world/pull/123", ""
+				},
+			)
+
+			if got != tt.exit {
+				t.Errorf("run() = %v, want %v", got, tt.exit)
+			}
+
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stdout))
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stderr))
+			snaps.MatchJSON(t, ghExecArgs)
+		})
+	}
+}
+
+func Test_run_AlwaysGroup(t *testing.T) {
+	t.Parallel()
+
+	config := `
+		repositories:
+			'*':
+				security: [octokitten, compliance-bot]
+			octocat/hello-world:
+				default: [octocat, octopus]
+				infra: [octodog]
+				always:
+					- octopus
+					- handle: compliance-bot
+						name: Compliance
+	`
+
+	tests := []struct {
+		name string
+		args []string
+		exit int
+	}{
+		{
+			name: "when requesting reviews from the default group",
+			args: []string{"123"},
+			exit: 0,
+		},
+		{
+			name: "when requesting reviews from another group",
+			args: []string{"--from", "infra", "123"},
+			exit: 0,
+		},
+		{
+			name: "when requesting reviews from a global group",
+			args: []string{"-gf", "security", "123"},
+			exit: 0,
+		},
+		{
+			name: "when requesting reviews from the always group",
+			args: []string{"--from", "always", "123"},
+			exit: 0,
+		},
+		{
+			name: "when doing a dry-run",
+			args: []string{"--dry-run", "--from", "infra", "123"},
+			exit: 0,
+		},
+		{
+			name: "when requesting reviews on a repository without an always group",
+			args: []string{"--repo", "octocat/spoon-knife", "-gf", "security", "123"},
+			exit: 0,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			configDir := writeConfigFileInTempDir(t, dedent(t, config))
+
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+
+			var ghExecArgs []string
+
+			got := run(
+				append([]string{"--config-dir", configDir, "--repo", "octocat/hello-world"}, tt.args...),
+				stdout,
+				stderr,
+				func(args ...string) (string, string) {
+					ghExecArgs = args
+
+					return "https://github.com/octocat/hello-world/pull/123", ""
+				},
+			)
+
+			if got != tt.exit {
+				t.Errorf("run() = %v, want %v", got, tt.exit)
+			}
+
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stdout))
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stderr))
+			snaps.MatchJSON(t, ghExecArgs)
+		})
+	}
+}
+
+func Test_run_FallbackGroups(t *testing.T) {
+	t.Parallel()
+
+	maintainers := map[string]string{
+		"orgs/octo-org/teams/infra/members?role=maintainer": `[]`,
+	}
+
+	config := `
+		repositories:
+			octocat/hello-world:
+				default:
+					reviewers: []
+					fallback: [seniors, anyone]
+				infra:
+					reviewers: [team-maintainers:octo-org/infra]
+					fallback: [anyone]
+				seniors:
+					reviewers: []
+				anyone: [octocat, octopus]
+				security:
+					reviewers: [octodog]
+					fallback: [anyone]
+				broken:
+					reviewers: []
+					fallback: [nope]
+				empty:
+					reviewers: []
+					fallback: [seniors]
+	`
+
+	tests := []struct {
+		name string
+		args []string
+		exit int
+	}{
+		{
+			name: "when the group has no reviewers",
+			args: []string{"123"},
+			exit: 0,
+		},
+		{
+			name: "when the group has no reviewers after expanding teams",
+			args: []string{"--from", "infra", "123"},
+			exit: 0,
+		},
+		{
+			name: "when the group has reviewers",
+			args: []string{"--from", "security", "123"},
+			exit: 0,
+		},
+		{
+			name: "when doing a dry-run",
+			args: []string{"--dry-run", "123"},
+			exit: 0,
+		},
+		{
+			name: "when the fallback group does not exist",
+			args: []string{"--from", "broken", "123"},
+			exit: 1,
+		},
+		{
+			name: "when every fallback group is empty",
+			args: []string{"--from", "empty", "123"},
+			exit: 0,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			configDir := writeConfigFileInTempDir(t, dedent(t, config))
+
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+
+			var calls [][]string
+
+			got := run(
+				append([]string{"--config-dir", configDir, "--repo", "octocat/hello-world"}, tt.args...),
+				stdout,
+				stderr,
+				fakeTeamsGh(t, maintainers, &calls),
+			)
+
+			if got != tt.exit {
+				t.Errorf("run() = %v, want %v", got, tt.exit)
+			}
+
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stdout))
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stderr))
+			snaps.MatchJSON(t, calls)
+		})
+	}
+}
+
+func Test_run_GroupFromTeam(t *testing.T) {
+	t.Parallel()
+
+	responses := map[string]string{
+		"user": "octocat",
+		"orgs/octo-org/teams/backend/members?role=all":  `[{"login":"OctoCat"},{"login":"octopus"}]`,
+		"orgs/octo-org/teams/frontend/members?role=all": `[{"login":"octodog"}]`,
+	}
+
+	config := `
+		settings:
+			group_from_team: true
+		repositories:
+			octocat/hello-world:
+				default: [octokitten]
+				backend:
+					team: octo-org/backend
+					reviewers: [octocat, octopus]
+				frontend:
+					team: octo-org/frontend
+					reviewers: [octodog]
+			octocat/spoon-knife:
+				default: [octokitten]
+				frontend:
+					team: octo-org/frontend
+					reviewers: [octodog]
+			octocat/linguist:
+				default: [octokitten]
+				backend:
+					team: octo-org/nope
+					reviewers: [octocat]
+	`
+
+	tests := []struct {
+		name   string
+		args   []string
+		config string
+		exit   int
+	}{
+		{
+			name:   "when the user is a member of a team linked to a group",
+			args:   []string{"123"},
+			config: config,
+			exit:   0,
+		},
+		{
+			name:   "when the user is not a member of any team linked to a group",
+			args:   []string{"--repo", "octocat/spoon-knife", "123"},
+			config: config,
+			exit:   0,
+		},
+		{
+			name:   "when a group is explicitly requested",
+			args:   []string{"--from", "frontend", "123"},
+			config: config,
+			exit:   0,
+		},
+		{
+			name:   "when a team cannot be fetched",
+			args:   []string{"--repo", "octocat/linguist", "123"},
+			config: config,
+			exit:   1,
+		},
+		{
+			name: "when the setting is not enabled",
+			args: []string{"123"},
+			config: `
+				repositories:
+					octocat/hello-world:
+						default: [octokitten]
+						backend:
+							team: octo-org/backend
+							reviewers: [octocat, octopus]
+			`,
+			exit: 0,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			configDir := writeConfigFileInTempDir(t, dedent(t, tt.config))
+
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+
+			var calls [][]string
+
+			got := run(
+				append([]string{"--config-dir", configDir, "--repo", "octocat/hello-world"}, tt.args...),
+				stdout,
+				stderr,
+				fakeTeamsGh(t, responses, &calls),
+			)
+
+			if got != tt.exit {
+				t.Errorf("run() = %v, want %v", got, tt.exit)
+			}
+
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stdout))
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stderr))
+			snaps.MatchJSON(t, calls)
+		})
+	}
+}
+
+func Test_run_DefaultGroup(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		args   []string
+		config string
+		exit   int
+	}{
+		{
+			name: "when the repository has a default group",
+			args: []string{"123"},
+			config: `
+				repositories:
+					octocat/hello-world:
+						default_group: team
+						team: [octocat]
+						owners: [octopus]
+			`,
+			exit: 0,
+		},
+		{
+			name: "when explicitly requesting a group",
+			args: []string{"--from", "owners", "123"},
+			config: `
+				repositories:
+					octocat/hello-world:
+						default_group: team
+						team: [octocat]
+						owners: [octopus]
+			`,
+			exit: 0,
+		},
+		{
+			name: "when the settings have a default group",
+			args: []string{"123"},
+			config: `
+				settings:
+					default_group: team
+				repositories:
+					octocat/hello-world:
+						team: [octocat]
+						owners: [octopus]
+			`,
+			exit: 0,
+		},
+		{
+			name: "when the repository and the settings have a default group",
+			args: []string{"123"},
+			config: `
+				settings:
+					default_group: team
+				repositories:
+					octocat/hello-world:
+						default_group: owners
+						team: [octocat]
+						owners: [octopus]
+			`,
+			exit: 0,
+		},
+		{
+			name: "when the settings have a default group that the repository does not",
+			args: []string{"123"},
+			config: `
+				settings:
+					default_group: team
+				repositories:
+					octocat/hello-world: [octodog]
+			`,
+			exit: 0,
+		},
+		{
+			name: "when the owner has a default group",
+			args: []string{"123"},
+			config: `
+				owners:
+					octocat:
+						default_group: team
+						team: [octocat]
+				repositories:
+					octocat/hello-world:
+						owners: [octopus]
+			`,
+			exit: 0,
+		},
+		{
+			name: "when the owner and the repository have a default group",
+			args: []string{"123"},
+			config: `
+				owners:
+					octocat:
+						default_group: team
+						team: [octocat]
+				repositories:
+					octocat/hello-world:
+						default_group: owners
+						owners: [octopus]
+			`,
+			exit: 0,
+		},
+		{
+			name: "when the default group does not exist",
+			args: []string{"123"},
+			config: `
+				repositories:
+					octocat/hello-world:
+						default_group: team
+						owners: [octopus]
+			`,
+			exit: 1,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			configDir := writeConfigFileInTempDir(t, dedent(t, tt.config))
+
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+
+			var ghExecArgs []string
+
+			got := run(
+				append([]string{"--config-dir", configDir, "--repo", "octocat/hello-world"}, tt.args...),
+				stdout,
+				stderr,
+				func(args ...string) (string, string) {
+					ghExecArgs = args
+
+					return "https://github.com/octocat/hello-world/pull/123", ""
+				},
+			)
+
+			if got != tt.exit {
+				t.Errorf("run() = %v, want %v", got, tt.exit)
+			}
+
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stdout))
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stderr))
+			snaps.MatchJSON(t, ghExecArgs)
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// guardAction controls what happens when a pull request fails a guard
+type guardAction string
+
+const (
+	guardWarn  guardAction = "warn"
+	guardBlock guardAction = "block"
+)
+
+func (a *guardAction) UnmarshalYAML(value *yaml.Node) error {
+	var action string
+
+	if err := value.Decode(&action); err != nil {
+		return err
+	}
+
+	switch guardAction(action) {
+	case guardWarn, guardBlock:
+		*a = guardAction(action)
+	default:
+		return fmt.Errorf("line %d: guard must be either warn or block, not `%s`", value.Line, action)
+	}
+
+	return nil
+}
+
+type sizeGuard struct {
+	MaxLines int         `yaml:"max_lines"`
+	Action   guardAction `yaml:"action"`
+}
+
+func (g *sizeGuard) UnmarshalYAML(value *yaml.Node) error {
+	type rawSizeGuard sizeGuard
+
+	guard := rawSizeGuard{Action: guardWarn}
+
+	if err := value.Decode(&guard); err != nil {
+		return err
+	}
+
+	if guard.MaxLines < 1 {
+		return fmt.Errorf("line %d: size guard must allow at least one line, not `%d`", value.Line, guard.MaxLines)
+	}
+
+	*g = sizeGuard(guard)
+
+	return nil
+}
+
+// guards are checks that are made against a pull request before requesting
+// reviews on it, acting as a lightweight pre-review checklist
+type guards struct {
+	Description guardAction `yaml:"description"`
+	LinkedIssue guardAction `yaml:"linked_issue"`
+	Size        *sizeGuard  `yaml:"size"`
+}
+
+func (g guards) isEmpty() bool {
+	return g.Description == "" && g.LinkedIssue == "" && g.Size == nil
+}
+
+// guardFailure is a guard that a pull request did not pass
+type guardFailure struct {
+	action  guardAction
+	message string
+}
+
+// checkGuards returns the guards that the pull request does not pass, using
+// the given name to refer to it in the failure messages
+func checkGuards(g guards, pr pullRequest, name string) []guardFailure {
+	var failures []guardFailure
+
+	if g.Description != "" && strings.TrimSpace(pr.Body) == "" {
+		failures = append(failures, guardFailure{g.Description, name + " does not have a description"})
+	}
+
+	if g.LinkedIssue != "" && len(pr.ClosingIssuesReferences) == 0 {
+		failures = append(failures, guardFailure{g.LinkedIssue, name + " is not linked to an issue"})
+	}
+
+	if g.Size != nil {
+		if lines := pr.Additions + pr.Deletions; lines > g.Size.MaxLines {
+			failures = append(failures, guardFailure{
+				g.Size.Action,
+				fmt.Sprintf("%s changes %d lines, which is more than %d", name, lines, g.Size.MaxLines),
+			})
+		}
+	}
+
+	return failures
+}
+
+// enforceGuards checks the pull request against the configured guards,
+// outputting any failures and returning false if any of them should block
+// reviews from being requested
+func enforceGuards(stderr io.Writer, ghExec ghExecutor, g guards, repo, target, name string) bool {
+	pr, err := fetchPullRequest(ghExec, repo, target, "body", "closingIssuesReferences", "additions", "deletions")
+
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+
+		return false
+	}
+
+	blocked := false
+
+	for _, failure := range checkGuards(g, pr, name) {
+		if failure.action == guardBlock {
+			fmt.Fprintln(stderr, failure.message)
+
+			blocked = true
+		} else {
+			fmt.Fprintf(stderr, "warning: %s\n", failure.message)
+		}
+	}
+
+	if blocked {
+		fmt.Fprintf(stderr, "not requesting reviews as %s did not pass the configured guards (use --force to request them anyway)\n", name)
+	}
+
+	return !blocked
+}
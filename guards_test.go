@@ -0,0 +1,168 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+// fakeGuardsGh acts as gh for a pull request with the given details
+func fakeGuardsGh(t *testing.T, details string, calls *[][]string) ghExecutor {
+	t.Helper()
+
+	return func(args ...string) (string, string) {
+		t.Helper()
+
+		*calls = append(*calls, args)
+
+		switch {
+		case len(args) > 1 && args[0] == "pr" && args[1] == "view":
+			return details, ""
+		case len(args) > 1 && args[0] == "pr" && args[1] == "edit":
+			return "https://github.com/octocat/hello-world/pull/123", ""
+		}
+
+		t.Errorf("unexpected call to gh: %v", args)
+
+		return "", ""
+	}
+}
+
+func Test_run_Guards(t *testing.T) {
+	t.Parallel()
+
+	good := `{"body":"fixes the thing","closingIssuesReferences":[{"number":1}],"additions":10,"deletions":5}`
+	bad := `{"body":"","closingIssuesReferences":[],"additions":400,"deletions":200}`
+
+	tests := []struct {
+		name    string
+		args    []string
+		config  string
+		details string
+		exit    int
+	}{
+		{
+			name: "when the pull request passes every guard",
+			args: []string{"123"},
+			config: `
+				settings:
+					guards:
+						description: block
+						linked_issue: block
+						size:
+							max_lines: 500
+							action: block
+			`,
+			details: good,
+			exit:    0,
+		},
+		{
+			name: "when the pull request fails guards that warn",
+			args: []string{"123"},
+			config: `
+				settings:
+					guards:
+						description: warn
+						linked_issue: warn
+						size:
+							max_lines: 500
+			`,
+			details: bad,
+			exit:    0,
+		},
+		{
+			name: "when the pull request fails guards that block",
+			args: []string{"123"},
+			config: `
+				settings:
+					guards:
+						description: block
+						linked_issue: warn
+						size:
+							max_lines: 500
+							action: block
+			`,
+			details: bad,
+			exit:    1,
+		},
+		{
+			name: "when forcing reviews to be requested",
+			args: []string{"--force", "123"},
+			config: `
+				settings:
+					guards:
+						description: block
+			`,
+			details: bad,
+			exit:    0,
+		},
+		{
+			name: "when doing a dry-run",
+			args: []string{"--dry-run", "123"},
+			config: `
+				settings:
+					guards:
+						description: block
+			`,
+			details: bad,
+			exit:    1,
+		},
+		{
+			name: "when a guard action is invalid",
+			args: []string{"123"},
+			config: `
+				settings:
+					guards:
+						description: error
+			`,
+			details: bad,
+			exit:    1,
+		},
+		{
+			name: "when the size guard does not have a maximum",
+			args: []string{"123"},
+			config: `
+				settings:
+					guards:
+						size:
+							action: block
+			`,
+			details: bad,
+			exit:    1,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			configDir := writeConfigFileInTempDir(t, dedent(t, tt.config+`
+				repositories:
+					octocat/hello-world:
+						- octocat
+						- octopus
+			`))
+
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+
+			var calls [][]string
+
+			got := run(
+				append([]string{"--config-dir", configDir, "--repo", "octocat/hello-world"}, tt.args...),
+				stdout,
+				stderr,
+				fakeGuardsGh(t, tt.details, &calls),
+			)
+
+			if got != tt.exit {
+				t.Errorf("run() = %v, want %v", got, tt.exit)
+			}
+
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stdout))
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stderr))
+			snaps.MatchJSON(t, calls)
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// the name of the alias that is used for the push hook if one is not given
+const defaultHookAlias = "pushrr"
+
+// buildPushHookScript builds a shell script that pushes the current branch,
+// and then requests reviews if there is an open pull request for it
+func buildPushHookScript(rrArgs []string) string {
+	rr := strings.TrimSpace("gh rr " + quoteArgs(rrArgs))
+
+	return fmt.Sprintf(`git push "$@" && if gh pr view --json number >/dev/null 2>&1; then %s; fi`, rr)
+}
+
+// runHookCommand installs or uninstalls a gh alias that chains together pushing
+// with requesting reviews, since git does not support "post-push" hooks
+func runHookCommand(stdout, stderr io.Writer, ghExec ghExecutor, args []string, rrArgs []string) int {
+	if len(args) == 0 || (args[0] != "install" && args[0] != "uninstall") {
+		fmt.Fprintln(stderr, "please specify if the hook should be installed or uninstalled")
+
+		return 1
+	}
+
+	name := defaultHookAlias
+
+	if len(args) > 1 {
+		name = args[1]
+	}
+
+	if args[0] == "uninstall" {
+		if _, errMsg := ghExec("alias", "delete", name); errMsg != "" {
+			fmt.Fprintf(stderr, "could not uninstall hook: %s\n", strings.TrimSpace(errMsg))
+
+			return 1
+		}
+
+		fmt.Fprintf(stdout, "removed the `gh %s` alias\n", name)
+
+		return 0
+	}
+
+	if _, errMsg := ghExec("alias", "set", "--clobber", "--shell", name, buildPushHookScript(rrArgs)); errMsg != "" {
+		fmt.Fprintf(stderr, "could not install hook: %s\n", strings.TrimSpace(errMsg))
+
+		return 1
+	}
+
+	fmt.Fprintf(stdout, "use `gh %s` to push your branch and request reviews if it has an open pull request\n", name)
+
+	return 0
+}
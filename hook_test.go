@@ -0,0 +1,94 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+func Test_run_Hook(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		args   []string
+		ghExec ghExecutor
+		exit   int
+	}{
+		{
+			name:   "when installing the hook",
+			args:   []string{"hook", "install"},
+			ghExec: expectCallToGh(t, "octocat/hello-world", "1"),
+			exit:   0,
+		},
+		{
+			name:   "when installing the hook with a custom name",
+			args:   []string{"hook", "install", "pr-push"},
+			ghExec: expectCallToGh(t, "octocat/hello-world", "1"),
+			exit:   0,
+		},
+		{
+			name:   "when installing the hook with a group",
+			args:   []string{"hook", "install", "-gf", "it's-security"},
+			ghExec: expectCallToGh(t, "octocat/hello-world", "1"),
+			exit:   0,
+		},
+		{
+			name:   "when uninstalling the hook",
+			args:   []string{"hook", "uninstall"},
+			ghExec: expectCallToGh(t, "octocat/hello-world", "1"),
+			exit:   0,
+		},
+		{
+			name: "when gh fails",
+			args: []string{"hook", "uninstall", "pr-push"},
+			ghExec: func(_ ...string) (string, string) {
+				return "", "no such alias pr-push"
+			},
+			exit: 1,
+		},
+		{
+			name:   "when no action is given",
+			args:   []string{"hook"},
+			ghExec: expectNoCallToGh(t),
+			exit:   1,
+		},
+		{
+			name:   "when an unknown action is given",
+			args:   []string{"hook", "reinstall"},
+			ghExec: expectNoCallToGh(t),
+			exit:   1,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+
+			var ghExecArgs []string
+
+			got := run(
+				tt.args,
+				stdout,
+				stderr,
+				func(args ...string) (string, string) {
+					ghExecArgs = args
+
+					return tt.ghExec(args...)
+				},
+			)
+
+			if got != tt.exit {
+				t.Errorf("run() = %v, want %v", got, tt.exit)
+			}
+
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stdout))
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stderr))
+			snaps.MatchJSON(t, ghExecArgs)
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"maps"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// hostConfig is the groups for repositories on a particular host, such as a
+// GitHub Enterprise Server instance
+type hostConfig struct {
+	Repositories repositories `yaml:"repositories"`
+	Owners       repositories `yaml:"owners"`
+}
+
+// hosts are the sections of the config for each host, which are keyed by the
+// lowercase name of the host as hostnames are not case-sensitive
+type hosts map[string]hostConfig
+
+func (h *hosts) UnmarshalYAML(value *yaml.Node) error {
+	var sections map[string]hostConfig
+
+	if err := value.Decode(&sections); err != nil {
+		return err
+	}
+
+	*h = hosts{}
+
+	// the order is fixed so that sections for the same host that are written
+	// differently are always merged the same way
+	for _, name := range sortedNames(sections) {
+		*h = mergeHosts(*h, hosts{strings.ToLower(name): sections[name]})
+	}
+
+	return nil
+}
+
+// mergeHosts layers the groups of each host in the overlay on top of those of
+// the same host in the base
+func mergeHosts(base, overlay hosts) hosts {
+	if len(overlay) == 0 {
+		return base
+	}
+
+	merged := make(hosts, len(base)+len(overlay))
+	maps.Copy(merged, base)
+
+	for host, hc := range overlay {
+		merged[host] = hostConfig{
+			Repositories: mergeRepositories(merged[host].Repositories, hc.Repositories),
+			Owners:       mergeRepositories(merged[host].Owners, hc.Owners),
+		}
+	}
+
+	return merged
+}
+
+// resolveHostConfig returns the config with the repositories and owners of the
+// section for the given host taking precedence over those at the top level;
+// repositories in the section are configured as OWNER/REPO, so are qualified
+// with the host if that is how the repository being targeted was given
+func resolveHostConfig(conf config, host, repo string) config {
+	section, ok := conf.Hosts[strings.ToLower(host)]
+
+	if !ok {
+		return conf
+	}
+
+	qualify := strings.Count(repo, "/") == 2
+	repos := make(repositories, len(section.Repositories))
+
+	for name, groups := range section.Repositories {
+		if qualify && name != "*" {
+			name = strings.ToLower(host) + "/" + name
+		}
+
+		repos[name] = groups
+	}
+
+	conf.Repositories = mergeRepositories(conf.Repositories, repos)
+	conf.Owners = mergeRepositories(conf.Owners, section.Owners)
+
+	return conf
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+func Test_run_WithHosts(t *testing.T) {
+	t.Parallel()
+
+	config := `
+		repositories:
+			'*':
+				security: [octokitten]
+			octocat/hello-world:
+				- octocat
+				- octopus
+		hosts:
+			GitHub.Example.com:
+				repositories:
+					'*':
+						security: [octobear]
+					octocat/hello-world:
+						- octodog
+				owners:
+					octo-org:
+						default: [octocorn]
+	`
+
+	tests := []struct {
+		name   string
+		args   []string
+		config string
+		exit   int
+	}{
+		{
+			name:   "when the repository is on the default host",
+			args:   []string{"--repo", "octocat/hello-world", "--dry-run", "123"},
+			config: config,
+			exit:   0,
+		},
+		{
+			name:   "when the repository is on a configured host",
+			args:   []string{"--repo", "github.example.com/octocat/hello-world", "--dry-run", "123"},
+			config: config,
+			exit:   0,
+		},
+		{
+			name:   "when using global groups of a configured host",
+			args:   []string{"--repo", "github.example.com/octocat/hello-world", "-g", "--from", "security", "--dry-run", "123"},
+			config: config,
+			exit:   0,
+		},
+		{
+			name:   "when using owner groups of a configured host",
+			args:   []string{"--repo", "github.example.com/octo-org/billing", "--dry-run", "123"},
+			config: config,
+			exit:   0,
+		},
+		{
+			name:   "when using owner groups of another host",
+			args:   []string{"--repo", "octo-org/billing", "--dry-run", "123"},
+			config: config,
+			exit:   1,
+		},
+		{
+			name:   "when the repository is on a host without a section",
+			args:   []string{"--repo", "ghe.example.com/octocat/hello-world", "--dry-run", "123"},
+			config: config,
+			exit:   1,
+		},
+		{
+			name: "when sections for the same host are written differently",
+			args: []string{"--repo", "github.example.com/octocat/hello-world", "--dry-run", "123"},
+			config: `
+				hosts:
+					GitHub.Example.com:
+						repositories:
+							octocat/hello-world:
+								default: [octodog]
+								infra: [octopus]
+					github.example.com:
+						repositories:
+							octocat/hello-world:
+								default: [octocat]
+			`,
+			exit: 0,
+		},
+		{
+			name:   "when listing groups",
+			args:   []string{"--repo", "github.example.com/octocat/hello-world", "groups"},
+			config: config,
+			exit:   0,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			configDir := writeConfigFileInTempDir(t, dedent(t, tt.config))
+
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+
+			got := run(
+				append([]string{"--config-dir", configDir}, tt.args...),
+				stdout,
+				stderr,
+				expectNoCallToGh(t),
+			)
+
+			if got != tt.exit {
+				t.Errorf("run() = %v, want %v", got, tt.exit)
+			}
+
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stdout))
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stderr))
+		})
+	}
+}
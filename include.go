@@ -0,0 +1,81 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+)
+
+// configIncludes lists the other configs whose repositories should be merged
+// into the config, relative to the directory of the config
+type configIncludes struct {
+	Include []string `yaml:"include"`
+}
+
+// configSource is a config that contributes to the resolved config, along with
+// the config that included it if it was included
+type configSource struct {
+	file       string
+	includedBy string
+	conf       config
+}
+
+// contribution returns what the source contributes to the config that it is a
+// part of, which is only its groups if it was included by another config
+func (s configSource) contribution() config {
+	if s.includedBy == "" {
+		return s.conf
+	}
+
+	return config{Repositories: s.conf.Repositories, Owners: s.conf.Owners}
+}
+
+// collectConfigSources returns the config at the given path along with all of
+// the configs that it includes, in order of precedence from lowest to highest,
+// with the config itself taking precedence over everything it includes
+//
+// configs cannot include any of the configs that are already including them
+func collectConfigSources(file string, including []string) ([]configSource, error) {
+	conf, includes, err := readConfigFile(file)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(includes) == 0 {
+		return []configSource{{file: file, conf: conf}}, nil
+	}
+
+	abs, err := filepath.Abs(file)
+
+	if err != nil {
+		return nil, err
+	}
+
+	including = append(slices.Clone(including), abs)
+
+	var sources []configSource
+
+	for _, include := range includes {
+		p := include
+
+		if !filepath.IsAbs(p) {
+			p = filepath.Join(filepath.Dir(abs), p)
+		}
+
+		if slices.Contains(including, p) {
+			return nil, fmt.Errorf("could not include %s in %s, as that would create a cycle", include, file)
+		}
+
+		included, err := collectConfigSources(p, including)
+
+		if err != nil {
+			return nil, fmt.Errorf("could not include %s in %s: %v", include, file, err)
+		}
+
+		included[len(included)-1].includedBy = file
+		sources = append(sources, included...)
+	}
+
+	return append(sources, configSource{file: file, conf: conf}), nil
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"bytes"
+	"maps"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+func Test_run_WithIncludes(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		config string
+		files  map[string]string
+		exit   int
+	}{
+		{
+			name: "when including configs relative to the config",
+			config: `
+				include:
+					- teams/backend.yml
+					- teams/security.yml
+				repositories:
+					octocat/hello-world:
+						default: [octocat]
+			`,
+			files: map[string]string{
+				"teams/backend.yml": `
+					repositories:
+						octocat/hello-world:
+							default: [octodog]
+							infra: [octodog, octopus]
+				`,
+				"teams/security.yml": `
+					repositories:
+						'*':
+							security: [octokitten]
+						octocat/hello-world:
+							infra: [octobear]
+				`,
+			},
+			exit: 0,
+		},
+		{
+			name: "when including configs with absolute paths",
+			config: `
+				include: ['{{dir}}/backend.yml']
+				repositories: {}
+			`,
+			files: map[string]string{
+				"backend.yml": `
+					repositories:
+						octocat/hello-world:
+							default: [octodog]
+				`,
+			},
+			exit: 0,
+		},
+		{
+			name: "when included configs include other configs",
+			config: `
+				include: [teams/backend.yml]
+			`,
+			files: map[string]string{
+				"teams/backend.yml": `
+					include: [infra.yml]
+					repositories:
+						octocat/hello-world:
+							default: [octodog]
+				`,
+				"teams/infra.yml": `
+					repositories:
+						octocat/hello-world:
+							infra: [octopus]
+				`,
+			},
+			exit: 0,
+		},
+		{
+			name: "when an included config does not exist",
+			config: `
+				include: [teams/backend.yml]
+				repositories:
+					octocat/hello-world:
+						default: [octocat]
+			`,
+			exit: 1,
+		},
+		{
+			name: "when an included config is invalid",
+			config: `
+				include: [teams/backend.yml]
+			`,
+			files: map[string]string{
+				"teams/backend.yml": `
+					repositories: [
+				`,
+			},
+			exit: 1,
+		},
+		{
+			name: "when configs include each other",
+			config: `
+				include: [teams/backend.yml]
+			`,
+			files: map[string]string{
+				"teams/backend.yml": `
+					include: [../gh-rr.yml]
+				`,
+			},
+			exit: 1,
+		},
+		{
+			name: "when include is not a list",
+			config: `
+				include: true
+			`,
+			exit: 1,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			configDir := writeConfigFileInTempDir(t, "")
+			files := map[string]string{"gh-rr.yml": tt.config}
+
+			maps.Copy(files, tt.files)
+
+			for name, content := range files {
+				content = strings.ReplaceAll(dedent(t, content), "{{dir}}", filepath.ToSlash(configDir))
+				p := filepath.Join(configDir, filepath.FromSlash(name))
+
+				if err := os.MkdirAll(filepath.Dir(p), 0750); err != nil {
+					t.Fatal(err)
+				}
+
+				if err := os.WriteFile(p, []byte(content), 0600); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+
+			got := run(
+				[]string{"--config-dir", configDir, "--repo", "octocat/hello-world", "groups"},
+				stdout,
+				stderr,
+				expectNoCallToGh(t),
+			)
+
+			if got != tt.exit {
+				t.Errorf("run() = %v, want %v", got, tt.exit)
+			}
+
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stdout))
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stderr))
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+type initOptions struct {
+	file      string
+	stdin     io.Reader
+	repo      string
+	group     string
+	reviewers []string
+	isDryRun  bool
+
+	// canPrompt is whether questions can be asked for anything that has not
+	// been given, rather than needing everything up front
+	canPrompt bool
+
+	// confirm is whether to check that a config should be created at all
+	// before asking anything else, such as when one is missing on first run
+	confirm bool
+}
+
+// splitHandles splits the handles given as an answer, which can be separated
+// by commas and/or spaces
+func splitHandles(answer string) []string {
+	return strings.FieldsFunc(answer, func(r rune) bool {
+		return r == ',' || r == ' '
+	})
+}
+
+// buildStarterConfig creates a config document with a single group for the
+// repository, along with a comment pointing to where to learn more
+func buildStarterConfig(repo, group string, reviewers []string) *yaml.Node {
+	handles := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+
+	for _, handle := range reviewers {
+		handles.Content = append(handles.Content, scalarNode(strings.TrimPrefix(handle, "@")))
+	}
+
+	groups := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	groups.Content = append(groups.Content, scalarNode(group), handles)
+
+	repos := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	repos.Content = append(repos.Content, scalarNode(strings.ToLower(repo)), groups)
+
+	root := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	root.Content = append(root.Content, scalarNode("repositories"), repos)
+	root.HeadComment = "created using `gh rr init`, see https://github.com/G-Rath/gh-rr#usage\n" +
+		"for everything else that can be configured"
+
+	return &yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{root}}
+}
+
+// initConfig writes a starter config with a group for a repository, asking for
+// whatever has not been given as an option
+func initConfig(stdout, stderr io.Writer, opts initOptions) int {
+	if _, err := os.Stat(opts.file); err == nil {
+		fmt.Fprintf(stderr, "%s already exists, so there is nothing to initialize\n", opts.file)
+
+		return 1
+	} else if !errors.Is(err, os.ErrNotExist) {
+		fmt.Fprintln(stderr, err)
+
+		return 1
+	}
+
+	if len(opts.reviewers) == 0 && !opts.canPrompt {
+		fmt.Fprintln(stderr, "please provide the people to request reviews from, as questions can only be asked in a terminal when prompts are not disabled")
+
+		return 1
+	}
+
+	repo, group, reviewers := opts.repo, opts.group, opts.reviewers
+	p := prompter{stdout: stdout, scanner: bufio.NewScanner(opts.stdin)}
+
+	if opts.confirm && !p.confirm(fmt.Sprintf("%s does not exist yet, would you like to create it now", opts.file)) {
+		fmt.Fprintf(stderr, "please create %s to configure your repositories\n", opts.file)
+
+		return 1
+	}
+
+	// only ask questions if the reviewers have not been given up front
+	if len(reviewers) == 0 {
+		repo = p.ask("Which repository do you want to request reviews on", repo)
+		group = p.ask("What should the group of reviewers be called", group)
+		reviewers = splitHandles(p.ask("Who should reviews be requested from (separated by spaces or commas)", ""))
+	}
+
+	if _, _, found := strings.Cut(repo, "/"); !found {
+		fmt.Fprintln(stderr, "repository should be in the format of <owner>/<repository>")
+
+		return 1
+	}
+
+	if len(reviewers) == 0 {
+		fmt.Fprintln(stderr, "please provide at least one person to request reviews from")
+
+		return 1
+	}
+
+	out, err := encodeConfigDocument(buildStarterConfig(repo, group, reviewers))
+
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+
+		return 1
+	}
+
+	if opts.isDryRun {
+		fmt.Fprintf(stdout, "would have written %s with:\n%s", opts.file, out)
+
+		return 0
+	}
+
+	if err := os.MkdirAll(filepath.Dir(opts.file), 0700); err != nil {
+		fmt.Fprintf(stderr, "could not save config: %v\n", err)
+
+		return 1
+	}
+
+	if err := os.WriteFile(opts.file, out, 0600); err != nil {
+		fmt.Fprintf(stderr, "could not save config: %v\n", err)
+
+		return 1
+	}
+
+	fmt.Fprintf(stdout, "wrote %s\n", opts.file)
+
+	if group == "default" {
+		fmt.Fprintf(stdout, "use `gh rr` within %s to request reviews from the group\n", repo)
+	} else {
+		fmt.Fprintf(stdout, "use `gh rr --from %s` within %s to request reviews from the group\n", group, repo)
+	}
+
+	return 0
+}
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+func Test_run_Init(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		args   []string
+		config string
+		exit   int
+	}{
+		{
+			name:   "when giving the reviewers",
+			args:   []string{"init", "--repo", "octocat/Hello-World", "octocat", "@octopus"},
+			config: "",
+			exit:   0,
+		},
+		{
+			name:   "when giving the name of the group",
+			args:   []string{"init", "--repo", "octocat/hello-world", "--from", "frontend", "octocat"},
+			config: "",
+			exit:   0,
+		},
+		{
+			name:   "when doing a dry-run",
+			args:   []string{"init", "--repo", "octocat/hello-world", "--dry-run", "octocat"},
+			config: "",
+			exit:   0,
+		},
+		{
+			name:   "when the repository is not valid",
+			args:   []string{"init", "--repo", "hello-world", "octocat"},
+			config: "",
+			exit:   1,
+		},
+		{
+			name:   "when not giving the reviewers outside of a terminal",
+			args:   []string{"init", "--repo", "octocat/hello-world"},
+			config: "",
+			exit:   1,
+		},
+		{
+			name:   "when there is already a config",
+			args:   []string{"init", "--repo", "octocat/hello-world", "octocat"},
+			config: "repositories: {octocat/hello-world: [octopus]}",
+			exit:   1,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			configDir := writeConfigFileInTempDir(t, tt.config)
+
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+
+			got := run(
+				append([]string{"--config-dir", configDir}, tt.args...),
+				stdout,
+				stderr,
+				expectNoCallToGh(t),
+			)
+
+			if got != tt.exit {
+				t.Errorf("run() = %v, want %v", got, tt.exit)
+			}
+
+			config, _ := os.ReadFile(filepath.Join(configDir, "gh-rr.yml"))
+
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stdout))
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stderr))
+			snaps.MatchSnapshot(t, string(config))
+		})
+	}
+}
+
+func Test_initConfig_Interactive(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		repo    string
+		answers string
+		confirm bool
+		exit    int
+	}{
+		{
+			name:    "when accepting the defaults",
+			repo:    "octocat/hello-world",
+			answers: "\n\noctocat, octopus\n",
+			exit:    0,
+		},
+		{
+			name:    "when answering every question",
+			repo:    "octocat/hello-world",
+			answers: "octocat/spoon-knife\nbackend\noctocat octopus\n",
+			exit:    0,
+		},
+		{
+			name:    "when the repository cannot be detected",
+			repo:    "",
+			answers: "octocat/hello-world\n\noctocat\n",
+			exit:    0,
+		},
+		{
+			name:    "when not giving any reviewers",
+			repo:    "octocat/hello-world",
+			answers: "\n\n\n",
+			exit:    1,
+		},
+		{
+			name:    "when there are no answers",
+			repo:    "octocat/hello-world",
+			answers: "",
+			exit:    1,
+		},
+		{
+			name:    "when accepting the offer to create a config",
+			repo:    "octocat/hello-world",
+			answers: "\n\n\noctocat\n",
+			confirm: true,
+			exit:    0,
+		},
+		{
+			name:    "when saying yes to creating a config",
+			repo:    "octocat/hello-world",
+			answers: "yes\n\nbackend\noctocat\n",
+			confirm: true,
+			exit:    0,
+		},
+		{
+			name:    "when declining the offer to create a config",
+			repo:    "octocat/hello-world",
+			answers: "n\n",
+			confirm: true,
+			exit:    1,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			configDir := writeConfigFileInTempDir(t, "")
+
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+
+			got := initConfig(stdout, stderr, initOptions{
+				file:      filepath.Join(configDir, "gh-rr.yml"),
+				stdin:     strings.NewReader(tt.answers),
+				repo:      tt.repo,
+				group:     "default",
+				canPrompt: true,
+				confirm:   tt.confirm,
+			})
+
+			if got != tt.exit {
+				t.Errorf("initConfig() = %v, want %v", got, tt.exit)
+			}
+
+			config, _ := os.ReadFile(filepath.Join(configDir, "gh-rr.yml"))
+
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stdout))
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stderr))
+			snaps.MatchSnapshot(t, string(config))
+		})
+	}
+}
@@ -0,0 +1,242 @@
+package main
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+type membershipOptions struct {
+	repo     string
+	args     []string
+	isLeave  bool
+	isDryRun bool
+}
+
+// repositoryFile is a file within a repository, as returned by the contents api
+type repositoryFile struct {
+	Content string `json:"content"`
+	SHA     string `json:"sha"`
+}
+
+// fetchSharedConfig returns the shared config of the repository, along with its
+// sha which is needed to update it
+func fetchSharedConfig(ghExec ghExecutor, repo string) ([]byte, string, error) {
+	return fetchConfigFile(ghExec, repo, sharedConfigPath, "")
+}
+
+// fetchConfigFile returns the config at the given path within the repository
+// as of the given ref (or the default branch if empty), along with its sha
+func fetchConfigFile(ghExec ghExecutor, repo, file, ref string) ([]byte, string, error) {
+	endpoint := fmt.Sprintf("repos/%s/contents/%s", repo, file)
+
+	if ref != "" {
+		endpoint += "?ref=" + url.QueryEscape(ref)
+	}
+
+	out, errMsg := ghExec("api", endpoint)
+
+	if errMsg != "" {
+		if strings.Contains(errMsg, "HTTP 404") {
+			return nil, "", fmt.Errorf("%s does not have a config at %s", repo, file)
+		}
+
+		return nil, "", fmt.Errorf("could not get the config of %s: %s", repo, strings.TrimSpace(errMsg))
+	}
+
+	var f repositoryFile
+
+	if err := json.Unmarshal([]byte(out), &f); err != nil {
+		return nil, "", fmt.Errorf("could not parse the config of %s: %w", repo, err)
+	}
+
+	// the content is split over multiple lines
+	content, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(f.Content, "\n", ""))
+
+	if err != nil {
+		return nil, "", fmt.Errorf("could not decode the config of %s: %w", repo, err)
+	}
+
+	return content, f.SHA, nil
+}
+
+// proposeSharedConfigChange opens a pull request against the repository which
+// updates the shared config to the given content, returning its url
+func proposeSharedConfigChange(ghExec ghExecutor, repo, sha, branch, title string, content []byte) (string, error) {
+	base, errMsg := ghExec("api", "repos/"+repo, "--jq", ".default_branch")
+
+	if errMsg != "" {
+		return "", fmt.Errorf("could not get the default branch of %s: %s", repo, strings.TrimSpace(errMsg))
+	}
+
+	base = strings.TrimSpace(base)
+
+	head, errMsg := ghExec("api", fmt.Sprintf("repos/%s/git/ref/heads/%s", repo, base), "--jq", ".object.sha")
+
+	if errMsg != "" {
+		return "", fmt.Errorf("could not get the latest commit on %s: %s", base, strings.TrimSpace(errMsg))
+	}
+
+	_, errMsg = ghExec(
+		"api", fmt.Sprintf("repos/%s/git/refs", repo),
+		"-f", "ref=refs/heads/"+branch,
+		"-f", "sha="+strings.TrimSpace(head),
+	)
+
+	if errMsg != "" {
+		return "", fmt.Errorf("could not create branch %s: %s", branch, strings.TrimSpace(errMsg))
+	}
+
+	_, errMsg = ghExec(
+		"api", "-X", "PUT", fmt.Sprintf("repos/%s/contents/%s", repo, sharedConfigPath),
+		"-f", "message="+title,
+		"-f", "content="+base64.StdEncoding.EncodeToString(content),
+		"-f", "sha="+sha,
+		"-f", "branch="+branch,
+	)
+
+	if errMsg != "" {
+		return "", fmt.Errorf("could not update %s: %s", sharedConfigPath, strings.TrimSpace(errMsg))
+	}
+
+	url, errMsg := ghExec(
+		"pr", "create",
+		"--repo", repo,
+		"--base", base,
+		"--head", branch,
+		"--title", title,
+		"--body", "This was opened by `gh rr`.",
+	)
+
+	if errMsg != "" {
+		return "", fmt.Errorf("could not open pull request: %s", strings.TrimSpace(errMsg))
+	}
+
+	return strings.TrimSpace(url), nil
+}
+
+// changeMembership adds or removes the current user from a group in the shared
+// config of the repository, by opening a pull request so that the change can
+// be reviewed like any other
+func changeMembership(stdout, stderr io.Writer, ghExec ghExecutor, opts membershipOptions) int {
+	if len(opts.args) != 1 {
+		fmt.Fprintln(stderr, "please provide the name of the group")
+
+		return 1
+	}
+
+	name := opts.args[0]
+
+	login, err := fetchCurrentUser(ghExec)
+
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+
+		return 1
+	}
+
+	original, sha, err := fetchSharedConfig(ghExec, opts.repo)
+
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+
+		return 1
+	}
+
+	if isSopsEncrypted(original) {
+		fmt.Fprintf(stderr, "%s is encrypted with sops, so it cannot be edited by gh-rr\n", sharedConfigPath)
+
+		return 1
+	}
+
+	var doc yaml.Node
+
+	if err := yaml.Unmarshal(original, &doc); err != nil {
+		fmt.Fprintln(stderr, describeYAMLError(sharedConfigPath, original, err))
+
+		return 1
+	}
+
+	found := false
+	changed := false
+
+	walkGroups(&doc, func(loc groupLocation, _ *yaml.Node, reviewers *yaml.Node) {
+		if loc.profile != "" || loc.host != "" || loc.isOwner || loc.repo != strings.ToLower(opts.repo) || loc.group != name {
+			return
+		}
+
+		found = true
+
+		isMember := slices.ContainsFunc(reviewers.Content, func(node *yaml.Node) bool {
+			return strings.EqualFold(reviewerNodeHandle(node), login)
+		})
+
+		switch {
+		case opts.isLeave && isMember:
+			reviewers.Content = slices.DeleteFunc(reviewers.Content, func(node *yaml.Node) bool {
+				return strings.EqualFold(reviewerNodeHandle(node), login)
+			})
+			changed = true
+		case !opts.isLeave && !isMember:
+			reviewers.Content = append(reviewers.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: login})
+			changed = true
+		}
+	})
+
+	if !found {
+		fmt.Fprintf(stderr, "%s does not have a group named %s\n", opts.repo, name)
+
+		return 1
+	}
+
+	action, verb, preposition := "join", "add", "to"
+	title := fmt.Sprintf("Add @%s to the %s group", login, name)
+
+	if opts.isLeave {
+		action, verb, preposition = "leave", "remove", "from"
+		title = fmt.Sprintf("Remove @%s from the %s group", login, name)
+	}
+
+	if !changed {
+		if opts.isLeave {
+			fmt.Fprintf(stdout, "you are not in the %s group\n", name)
+		} else {
+			fmt.Fprintf(stdout, "you are already in the %s group\n", name)
+		}
+
+		return 0
+	}
+
+	content, err := encodeConfigDocument(&doc)
+
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+
+		return 1
+	}
+
+	if opts.isDryRun {
+		fmt.Fprintln(stdout, unifiedDiff(sharedConfigPath, string(original), string(content)))
+		fmt.Fprintf(stdout, "would have opened a pull request on %s to %s you %s the %s group\n", opts.repo, verb, preposition, name)
+
+		return 0
+	}
+
+	url, err := proposeSharedConfigChange(ghExec, opts.repo, sha, fmt.Sprintf("gh-rr/%s-%s-%s", action, name, login), title, content)
+
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+
+		return 1
+	}
+
+	fmt.Fprintf(stdout, "opened %s to %s you %s the %s group\n", url, verb, preposition, name)
+
+	return 0
+}
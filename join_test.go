@@ -0,0 +1,146 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+// fakeJoinGh acts as gh for octocat, in a repository with the given shared config
+func fakeJoinGh(t *testing.T, config string, calls *[][]string) ghExecutor {
+	t.Helper()
+
+	return func(args ...string) (string, string) {
+		t.Helper()
+
+		*calls = append(*calls, args)
+
+		switch {
+		case len(args) > 1 && args[0] == "api" && args[1] == "user":
+			return "octocat", ""
+		case len(args) > 1 && args[0] == "api" && args[1] == "repos/octocat/hello-world/contents/.github/gh-rr.yml":
+			if config == "" {
+				return "", "gh: Not Found (HTTP 404)"
+			}
+
+			content := base64.StdEncoding.EncodeToString([]byte(config))
+
+			return fmt.Sprintf(`{"content":"%s\n","sha":"abc123"}`, content), ""
+		case len(args) > 1 && args[0] == "api" && args[1] == "repos/octocat/hello-world":
+			return "main", ""
+		case len(args) > 1 && args[0] == "api" && args[1] == "repos/octocat/hello-world/git/ref/heads/main":
+			return "def456", ""
+		case len(args) > 1 && args[0] == "api" && args[1] == "repos/octocat/hello-world/git/refs":
+			return "{}", ""
+		case len(args) > 3 && args[0] == "api" && args[1] == "-X" && args[2] == "PUT":
+			return "{}", ""
+		case len(args) > 1 && args[0] == "pr" && args[1] == "create":
+			return "https://github.com/octocat/hello-world/pull/7", ""
+		}
+
+		t.Errorf("unexpected call to gh: %v", args)
+
+		return "", ""
+	}
+}
+
+func Test_run_JoinAndLeave(t *testing.T) {
+	t.Parallel()
+
+	config := dedent(t, `
+		repositories:
+			octocat/hello-world:
+				# the people who know the most about infra
+				infra: [octodog]
+				security:
+					description: security folks
+					reviewers:
+						- octocat
+						- octopus
+	`)
+
+	tests := []struct {
+		name   string
+		args   []string
+		config string
+		exit   int
+	}{
+		{
+			name:   "when joining a group",
+			args:   []string{"join", "infra"},
+			config: config,
+			exit:   0,
+		},
+		{
+			name:   "when joining a group as a dry-run",
+			args:   []string{"join", "--dry-run", "infra"},
+			config: config,
+			exit:   0,
+		},
+		{
+			name:   "when joining a group you are already in",
+			args:   []string{"join", "security"},
+			config: config,
+			exit:   0,
+		},
+		{
+			name:   "when leaving a group",
+			args:   []string{"leave", "security"},
+			config: config,
+			exit:   0,
+		},
+		{
+			name:   "when leaving a group you are not in",
+			args:   []string{"leave", "infra"},
+			config: config,
+			exit:   0,
+		},
+		{
+			name:   "when the group does not exist",
+			args:   []string{"join", "docs"},
+			config: config,
+			exit:   1,
+		},
+		{
+			name:   "when the repository does not have a shared config",
+			args:   []string{"join", "infra"},
+			config: "",
+			exit:   1,
+		},
+		{
+			name:   "when no group is given",
+			args:   []string{"join"},
+			config: config,
+			exit:   1,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+
+			var calls [][]string
+
+			got := run(
+				append([]string{"--repo", "octocat/hello-world"}, tt.args...),
+				stdout,
+				stderr,
+				fakeJoinGh(t, tt.config, &calls),
+			)
+
+			if got != tt.exit {
+				t.Errorf("run() = %v, want %v", got, tt.exit)
+			}
+
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stdout))
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stderr))
+			snaps.MatchJSON(t, calls)
+		})
+	}
+}
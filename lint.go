@@ -0,0 +1,158 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// sameGroup checks if the two groups are strict duplicates of each other,
+// ignoring the case of the handles of their reviewers
+func sameGroup(a, b group) bool {
+	if !slices.EqualFunc(a.Reviewers, b.Reviewers, func(x, y reviewer) bool {
+		return strings.EqualFold(x.Handle, y.Handle) && x.Name == y.Name && x.Chat == y.Chat && x.Timezone == y.Timezone
+	}) {
+		return false
+	}
+
+	a.Reviewers = nil
+	b.Reviewers = nil
+
+	return reflect.DeepEqual(a, b)
+}
+
+// lintGlobalGroups returns the problems with how the global groups are used,
+// being global groups that are shadowed by a group of the same name in every
+// repository, and repository groups that are duplicates of a global group
+func lintGlobalGroups(conf config) []string {
+	globalGroups, ok := conf.Repositories["*"]
+
+	if !ok {
+		return nil
+	}
+
+	repos := make([]string, 0, len(conf.Repositories))
+
+	for repo := range conf.Repositories {
+		if repo != "*" {
+			repos = append(repos, repo)
+		}
+	}
+
+	slices.Sort(repos)
+
+	names := make([]string, 0, len(globalGroups))
+
+	for name := range globalGroups {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+
+	var problems []string
+
+	for _, name := range names {
+		shadowed := len(repos) > 0
+
+		for _, repo := range repos {
+			if _, ok := conf.Repositories[repo][name]; !ok {
+				shadowed = false
+
+				break
+			}
+		}
+
+		if shadowed {
+			problems = append(problems, fmt.Sprintf(
+				"the global %s group is shadowed by a group of the same name in every repository, so consider removing it",
+				name,
+			))
+		}
+	}
+
+	for _, repo := range repos {
+		for _, name := range names {
+			g, ok := conf.Repositories[repo][name]
+
+			if ok && sameGroup(g, globalGroups[name]) {
+				problems = append(problems, fmt.Sprintf(
+					"the %s group of %s is the same as the global %s group, so consider removing it in favor of the global group",
+					name,
+					repo,
+					name,
+				))
+			}
+		}
+	}
+
+	return problems
+}
+
+// lintArchivedRepositories returns the repositories in the config that have
+// been archived, as they can no longer have reviews requested on them
+func lintArchivedRepositories(ghExec ghExecutor, conf config) ([]string, error) {
+	repos := make([]string, 0, len(conf.Repositories))
+
+	for repo := range conf.Repositories {
+		if repo != "*" && !isRepositoryPattern(repo) {
+			repos = append(repos, repo)
+		}
+	}
+
+	slices.Sort(repos)
+
+	var problems []string
+
+	for _, repo := range repos {
+		archived, err := isRepositoryArchived(ghExec, repo)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if archived {
+			problems = append(problems, fmt.Sprintf("%s is archived, so consider removing it", repo))
+		}
+	}
+
+	return problems, nil
+}
+
+// lintConfig outputs any problems with the config that is at the given path,
+// which while valid are likely to be cruft that could be consolidated
+func lintConfig(stdout, stderr io.Writer, ghExec ghExecutor, file string) int {
+	conf, err := parseConfig(file)
+
+	if err != nil {
+		printParseConfigError(stderr, file, err)
+
+		return 1
+	}
+
+	problems := lintGlobalGroups(conf)
+	archived, err := lintArchivedRepositories(ghExec, conf)
+
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+
+		return 1
+	}
+
+	problems = append(problems, archived...)
+
+	if len(problems) == 0 {
+		fmt.Fprintf(stdout, "no problems found in %s\n", file)
+
+		return 0
+	}
+
+	for _, problem := range problems {
+		fmt.Fprintf(stdout, "  - %s\n", problem)
+	}
+
+	fmt.Fprintf(stdout, "\nfound %s in %s\n", describeProblemCount(len(problems)), file)
+
+	return 1
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+func Test_run_Lint(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		config   string
+		archived []string
+		exit     int
+	}{
+		{
+			name: "when there are no problems",
+			config: `
+				repositories:
+					'*':
+						security: [octokitten]
+					octocat/hello-world:
+						default: [octocat]
+						security: [octopus]
+					octocat/spoon-knife:
+						default: [octodog]
+			`,
+			exit: 0,
+		},
+		{
+			name: "when there are no global groups",
+			config: `
+				repositories:
+					octocat/hello-world:
+						default: [octocat]
+			`,
+			exit: 0,
+		},
+		{
+			name: "when a global group is shadowed in every repository",
+			config: `
+				repositories:
+					'*':
+						default: [octokitten]
+						security: [octokitten]
+					octocat/hello-world:
+						default: [octocat]
+					octocat/spoon-knife:
+						default: [octodog]
+			`,
+			exit: 1,
+		},
+		{
+			name: "when a repository group duplicates a global group",
+			config: `
+				repositories:
+					'*':
+						security: [octokitten, octopus]
+					octocat/hello-world:
+						default: [octocat]
+						security: [OctoKitten, octopus]
+					octocat/spoon-knife:
+						default: [octodog]
+			`,
+			exit: 1,
+		},
+		{
+			name: "when a repository group differs from a global group by more than its reviewers",
+			config: `
+				repositories:
+					'*':
+						security: [octokitten]
+					octocat/hello-world:
+						security:
+							description: the security team
+							reviewers: [octokitten]
+					octocat/spoon-knife:
+						default: [octodog]
+			`,
+			exit: 0,
+		},
+		{
+			name: "when there are only global groups",
+			config: `
+				repositories:
+					'*':
+						security: [octokitten]
+			`,
+			exit: 0,
+		},
+		{
+			name: "when a repository is archived",
+			config: `
+				repositories:
+					'*':
+						security: [octokitten]
+					octocat/*:
+						default: [octocat]
+					octocat/hello-world:
+						default: [octocat]
+					octocat/spoon-knife:
+						default: [octodog]
+			`,
+			archived: []string{"octocat/spoon-knife"},
+			exit:     1,
+		},
+		{
+			name: "when a repository cannot be checked",
+			config: `
+				repositories:
+					octocat/missing:
+						default: [octocat]
+			`,
+			exit: 1,
+		},
+		{
+			name:   "when the config is invalid",
+			config: "repositories: [",
+			exit:   1,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			configDir := writeConfigFileInTempDir(t, dedent(t, tt.config))
+
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+
+			var calls [][]string
+
+			got := run(
+				[]string{"--config-dir", configDir, "lint"},
+				stdout,
+				stderr,
+				fakeArchivedGh(t, tt.archived, &calls),
+			)
+
+			if got != tt.exit {
+				t.Errorf("run() = %v, want %v", got, tt.exit)
+			}
+
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stdout))
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stderr))
+			snaps.MatchJSON(t, calls)
+		})
+	}
+}
@@ -1,45 +1,298 @@
 package main
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"os"
+	"os/exec"
+	"path"
 	"path/filepath"
+	"slices"
 	"strings"
+	"text/template"
+	"time"
+
+	// timezones of reviewers need to be loadable on systems without a tz
+	// database, which includes most Windows machines
+	_ "time/tzdata"
 
 	"github.com/cli/go-gh/v2"
-	"github.com/cli/go-gh/v2/pkg/repository"
 	flag "github.com/spf13/pflag"
 	"gopkg.in/yaml.v3"
 )
 
 type config struct {
-	Repositories repositories `yaml:"repositories"`
+	Extends       string              `yaml:"extends"`
+	Repositories  repositories        `yaml:"repositories"`
+	Owners        repositories        `yaml:"owners"`
+	Hosts         hosts               `yaml:"hosts"`
+	Aliases       map[string]string   `yaml:"aliases"`
+	Profiles      map[string]profile  `yaml:"profiles"`
+	Notifications notifications       `yaml:"notifications"`
+	Authors       []authorRule        `yaml:"authors"`
+	Settings      settings            `yaml:"settings"`
+	Phases        map[string][]string `yaml:"phases"`
+	Required      map[string][]string `yaml:"required"`
+}
+
+type profile struct {
+	config `yaml:",inline"`
+	Match  profileMatcher `yaml:"match"`
+}
+
+// profileMatcher describes the repositories that a profile should be
+// automatically selected for, based on their host and owner
+type profileMatcher struct {
+	Hosts  []string `yaml:"hosts"`
+	Owners []string `yaml:"owners"`
+}
+
+// matchesAny checks if the given value matches any of the patterns, ignoring case
+func matchesAny(patterns []string, value string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(strings.ToLower(pattern), strings.ToLower(value)); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matches checks if the given repository should be handled by the profile,
+// which is only the case if at least one matcher has been configured
+func (m profileMatcher) matches(host, owner string) bool {
+	if len(m.Hosts) == 0 && len(m.Owners) == 0 {
+		return false
+	}
+
+	if len(m.Hosts) > 0 && !matchesAny(m.Hosts, host) {
+		return false
+	}
+
+	if len(m.Owners) > 0 && !matchesAny(m.Owners, owner) {
+		return false
+	}
+
+	return true
 }
 
-type repositories map[string]map[string][]string
+type repositories map[string]map[string]group
 type repositoryGroups struct {
-	Groups map[string][]string
+	Groups         map[string]group
+	Teams          []string
+	ReviewersPerPR int
+	DefaultGroup   string
 }
 
-func (rg *repositoryGroups) UnmarshalYAML(unmarshal func(interface{}) error) error {
-	var group []string
+// groupsFromTeamsKey is the key within the groups of a repository that lists
+// teams which should each be a group of the same name, rather than a group
+const groupsFromTeamsKey = "groups_from_teams"
 
-	// allow an array to be provided as a shorthand for the default group
-	if err := unmarshal(&group); err == nil {
-		rg.Groups = map[string][]string{"default": group}
+// reviewersPerPRKey is the key within the groups of a repository that sets how
+// many reviewers each of its groups should request on a pull request by default
+const reviewersPerPRKey = "reviewers_per_pr"
 
-		return nil
+// defaultGroupKey is the key within the groups of a repository that sets which
+// of its groups is used when a group has not been requested
+const defaultGroupKey = "default_group"
+
+// isRepositorySettingKey checks if the key within the groups of a repository
+// is for a setting of the repository, rather than being a group
+func isRepositorySettingKey(key string) bool {
+	return key == groupsFromTeamsKey || key == reviewersPerPRKey || key == defaultGroupKey
+}
+
+type group struct {
+	Description    string         `yaml:"description"`
+	Reviewers      []reviewer     `yaml:"reviewers"`
+	SLA            sla            `yaml:"sla"`
+	Team           string         `yaml:"team"`
+	Fallback       []string       `yaml:"fallback"`
+	Escalation     escalation     `yaml:"escalation"`
+	Notify         *notifications `yaml:"notify"`
+	ReviewersPerPR int            `yaml:"reviewers_per_pr"`
+	Extends        []string       `yaml:"extends"`
+	Method         requestMethod  `yaml:"method"`
+
+	// isDefault is whether the group is used when a group has not been
+	// requested, which comes from the repository that it is a part of
+	isDefault bool
+
+	// referenceErr is why the groups that this group extends or references could
+	// not be resolved, which is only reported if the group is actually used
+	referenceErr error
+}
+
+type reviewer struct {
+	Handle   string `yaml:"handle"`
+	Name     string `yaml:"name"`
+	Chat     string `yaml:"chat"`
+	Timezone string `yaml:"tz"`
+
+	// method is how the reviewer is attached to pull requests, which comes
+	// from the group that they are being requested as part of
+	method requestMethod
+}
+
+func (r *reviewer) UnmarshalYAML(value *yaml.Node) error {
+	// allow a string to be provided as a shorthand for just the handle
+	if value.Kind == yaml.ScalarNode {
+		return value.Decode(&r.Handle)
+	}
+
+	type rawReviewer reviewer
+
+	if err := value.Decode((*rawReviewer)(r)); err != nil {
+		return err
+	}
+
+	// this is in the same format as yaml.v3 uses, so we get a nice snippet
+	if r.Handle == "" {
+		return fmt.Errorf("line %d: reviewers must have a handle", value.Line)
+	}
+
+	if r.Timezone != "" {
+		if _, err := time.LoadLocation(r.Timezone); err != nil {
+			line := value.Line
+
+			// point at the timezone itself unless it came from a merge key
+			if tz := mappingValue(value, "tz"); tz != nil {
+				line = tz.Line
+			}
+
+			return fmt.Errorf("line %d: tz must be an IANA timezone like Pacific/Auckland, not `%s`", line, r.Timezone)
+		}
+	}
+
+	return nil
+}
+
+// String returns a human-friendly description of the reviewer, using their
+// name, chat handle, and timezone if they are known
+func (r reviewer) String() string {
+	if r.Name == "" && r.Chat == "" && r.Timezone == "" {
+		return r.Handle
+	}
+
+	name := r.Name
+
+	if name == "" {
+		name = r.Handle
+	}
+
+	details := []string{"@" + r.Handle}
+
+	if r.Chat != "" {
+		details = append(details, r.Chat+" on chat")
+	}
+
+	if r.Timezone != "" {
+		details = append(details, "in "+r.Timezone)
+	}
+
+	return fmt.Sprintf("%s (%s)", name, strings.Join(details, ", "))
+}
+
+func (g *group) UnmarshalYAML(value *yaml.Node) error {
+	// allow an array to be provided as a shorthand for just the reviewers
+	if value.Kind == yaml.SequenceNode {
+		return value.Decode(&g.Reviewers)
 	}
 
-	if err := unmarshal(&rg.Groups); err != nil {
+	type rawGroup group
+
+	if err := value.Decode((*rawGroup)(g)); err != nil {
 		return err
 	}
 
+	if g.ReviewersPerPR < 0 {
+		return fmt.Errorf("line %d: reviewers_per_pr must be at least 1, not `%d`", value.Line, g.ReviewersPerPR)
+	}
+
 	return nil
 }
 
+func (rg *repositoryGroups) UnmarshalYAML(value *yaml.Node) error {
+	// allow an array to be provided as a shorthand for the default group
+	if value.Kind == yaml.SequenceNode {
+		var reviewers []reviewer
+
+		if err := value.Decode(&reviewers); err != nil {
+			return err
+		}
+
+		rg.Groups = map[string]group{"default": {Reviewers: reviewers}}
+
+		return nil
+	}
+
+	if value.Kind == yaml.MappingNode {
+		groups := *value
+		groups.Content = nil
+
+		for i := 0; i+1 < len(value.Content); i += 2 {
+			switch value.Content[i].Value {
+			case groupsFromTeamsKey:
+				if err := value.Content[i+1].Decode(&rg.Teams); err != nil {
+					return err
+				}
+			case reviewersPerPRKey:
+				if err := value.Content[i+1].Decode(&rg.ReviewersPerPR); err != nil {
+					return err
+				}
+			case defaultGroupKey:
+				if err := value.Content[i+1].Decode(&rg.DefaultGroup); err != nil {
+					return err
+				}
+			default:
+				groups.Content = append(groups.Content, value.Content[i], value.Content[i+1])
+			}
+		}
+
+		value = &groups
+	}
+
+	return value.Decode(&rg.Groups)
+}
+
+// addGroupsFromTeams adds a group for each of the teams, named after the team
+// and requesting reviews from the team as a whole; teams without an org are
+// assumed to be in the org that owns the repository, and groups that have
+// been explicitly configured are left as is
+func addGroupsFromTeams(repo string, groups map[string]group, teams []string) (map[string]group, error) {
+	if len(teams) > 0 && groups == nil {
+		groups = make(map[string]group, len(teams))
+	}
+
+	for _, team := range teams {
+		org, slug, found := strings.Cut(team, "/")
+
+		if !found {
+			if repo == "*" {
+				return nil, fmt.Errorf("the global %s must be in the format of <org>/<team>, not `%s`", groupsFromTeamsKey, team)
+			}
+
+			org, _, _ = strings.Cut(repo, "/")
+			slug = team
+
+			if strings.Contains(org, "*") {
+				return nil, fmt.Errorf("the %s of %s must be in the format of <org>/<team>, not `%s`", groupsFromTeamsKey, repo, team)
+			}
+		}
+
+		if _, ok := groups[slug]; ok {
+			continue
+		}
+
+		groups[slug] = group{Reviewers: []reviewer{{Handle: org + "/" + slug}}}
+	}
+
+	return groups, nil
+}
+
 func (r *repositories) UnmarshalYAML(unmarshal func(interface{}) error) error {
 	var repos map[string]repositoryGroups
 
@@ -47,53 +300,261 @@ func (r *repositories) UnmarshalYAML(unmarshal func(interface{}) error) error {
 		return err
 	}
 
+	if *r == nil {
+		*r = repositories{}
+	}
+
 	for s, v := range repos {
-		(*r)[strings.ToLower(s)] = v.Groups
+		groups, err := addGroupsFromTeams(s, v.Groups, v.Teams)
+
+		if err != nil {
+			return err
+		}
+
+		if v.ReviewersPerPR < 0 {
+			return fmt.Errorf("the reviewers_per_pr of %s must be at least 1, not `%d`", s, v.ReviewersPerPR)
+		}
+
+		for name, g := range groups {
+			if g.ReviewersPerPR == 0 {
+				g.ReviewersPerPR = v.ReviewersPerPR
+				groups[name] = g
+			}
+		}
+
+		if v.DefaultGroup != "" {
+			g, ok := groups[v.DefaultGroup]
+
+			if !ok {
+				return fmt.Errorf("the default_group of %s is %s, which is not one of its groups", s, v.DefaultGroup)
+			}
+
+			g.isDefault = true
+			groups[v.DefaultGroup] = g
+		}
+
+		(*r)[strings.ToLower(s)] = groups
 	}
 
 	return nil
 }
 
+// isJSONConfig checks if the config file is json rather than yaml, based on its
+// extension
+func isJSONConfig(file string) bool {
+	return strings.EqualFold(filepath.Ext(file), ".json")
+}
+
 func parseConfig(file string) (config, error) {
-	conf := config{Repositories: map[string]map[string][]string{}}
+	sources, err := collectConfigSources(file, nil)
+
+	if err != nil {
+		return config{Repositories: repositories{}}, err
+	}
+
+	conf := sources[len(sources)-1].conf
+	repos := repositories{}
+	owners := repositories{}
+
+	for _, source := range sources {
+		repos = mergeRepositories(repos, source.contribution().Repositories)
+		owners = mergeRepositories(owners, source.contribution().Owners)
+	}
+
+	conf.Repositories = repos
 
+	if len(owners) > 0 {
+		conf.Owners = owners
+	}
+
+	return conf, nil
+}
+
+// readConfigSource reads the contents of the config at the given path,
+// decrypting it first if it has been encrypted with sops
+func readConfigSource(file string) ([]byte, error) {
 	out, err := os.ReadFile(file)
 
 	if err != nil {
-		return conf, err
+		return nil, err
+	}
+
+	if isSopsEncrypted(out) {
+		return decryptSopsFile(file)
+	}
+
+	return out, nil
+}
+
+// readConfigFile parses the config at the given path along with the paths of
+// the configs that it includes, without parsing those configs
+func readConfigFile(file string) (config, []string, error) {
+	conf := config{Repositories: repositories{}}
+
+	out, err := readConfigSource(file)
+
+	if err != nil {
+		return conf, nil, err
+	}
+
+	// json is valid yaml, so this just makes sure the config is not using any
+	// yaml-only syntax that whatever generated it might not expect
+	if isJSONConfig(file) {
+		if err := json.Unmarshal(out, new(any)); err != nil {
+			return conf, nil, fmt.Errorf("could not parse %s: %w", file, err)
+		}
 	}
 
+	var directives configIncludes
+
 	err = yaml.Unmarshal(out, &conf)
 
+	if err == nil {
+		err = yaml.Unmarshal(out, &directives)
+	}
+
+	if err != nil {
+		return conf, nil, describeYAMLError(file, out, err)
+	}
+
+	return conf, directives.Include, nil
+}
+
+var errProfileNotConfigured = errors.New("profile is not configured")
+
+// parseUnresolvedConfig parses the config along with what it extends, without
+// resolving the groups for any particular repository, for commands that report
+// on every repository in the config
+func parseUnresolvedConfig(ghExec ghExecutor, confPath, configFile, profile string) (config, error) {
+	if configFile != "" {
+		confPath = configFile
+	}
+
+	conf, err := parseConfig(confPath)
+
 	if err != nil {
 		return conf, err
 	}
 
-	return conf, nil
+	conf, _, err = resolveExtendedConfig(ghExec, conf)
+
+	if err != nil {
+		return conf, err
+	}
+
+	if profile == "" {
+		profile = os.Getenv("GH_RR_PROFILE")
+	}
+
+	conf, err = selectProfile(conf, profile)
+
+	if err != nil {
+		return conf, err
+	}
+
+	conf.Repositories = resolveReviewerAliases(conf.Repositories, conf.Aliases)
+	conf.Repositories = resolveGroupReferences(conf.Repositories)
+
+	// every repository is reported on, so any group that is broken matters
+	return conf, findGroupReferenceError(conf.Repositories)
+}
+
+// selectProfile returns the configuration for the named profile, or the
+// top-level configuration if no profile has been named
+func selectProfile(conf config, name string) (config, error) {
+	if name == "" {
+		return conf, nil
+	}
+
+	profile, ok := conf.Profiles[name]
+
+	if !ok {
+		return config{}, errProfileNotConfigured
+	}
+
+	if profile.Repositories == nil {
+		profile.Repositories = repositories{}
+	}
+
+	return profile.config, nil
+}
+
+// matchProfile returns the name of the first profile (in alphabetical order)
+// whose matcher matches the given repository, or an empty string if none do
+func matchProfile(conf config, host, owner string) string {
+	names := make([]string, 0, len(conf.Profiles))
+
+	for name := range conf.Profiles {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+
+	for _, name := range names {
+		if conf.Profiles[name].Match.matches(host, owner) {
+			return name
+		}
+	}
+
+	return ""
 }
 
 var errRepositoryNotConfigured = errors.New("no reviewers are configured for repository")
 var errGroupNotConfigured = errors.New("repository is not configured with group")
 
-func determineReviewers(conf config, repository string, group string) ([]string, error) {
+func determineReviewers(conf config, repository string, group string) ([]reviewer, error) {
 	if _, ok := conf.Repositories[repository]; !ok {
-		return []string{}, errRepositoryNotConfigured
+		return []reviewer{}, errRepositoryNotConfigured
 	}
 
-	reviewers, ok := conf.Repositories[repository][group]
+	g, ok := conf.Repositories[repository][group]
 
 	if !ok {
-		return []string{}, errGroupNotConfigured
+		return []reviewer{}, errGroupNotConfigured
 	}
 
-	return reviewers, nil
+	if g.referenceErr != nil {
+		return []reviewer{}, g.referenceErr
+	}
+
+	return withMethod(g.Reviewers, g.Method), nil
+}
+
+// printReviewersError outputs a friendly message for errors returned by determineReviewers
+func printReviewersError(stderr io.Writer, err error, repo, group string) {
+	if errors.Is(err, errRepositoryNotConfigured) {
+		fmt.Fprintf(stderr, "no reviewers are configured for %s\n", repo)
+	} else if errors.Is(err, errGroupNotConfigured) {
+		fmt.Fprintf(stderr, "%s does not have a group named %s\n", repo, group)
+	} else {
+		fmt.Fprintf(stderr, "%v\n", err)
+	}
+}
+
+// printParseConfigError outputs a friendly message for errors returned by parseConfig
+func printParseConfigError(stderr io.Writer, confPath string, err error) {
+	if errors.Is(err, os.ErrNotExist) {
+		// todo: this could probably be worded better
+		fmt.Fprintf(stderr, "please create %s to configure your repositories\n", confPath)
+	} else if errors.Is(err, errSopsNotFound) {
+		fmt.Fprintf(stderr, "%s is encrypted with sops, which needs to be installed to decrypt it\n", confPath)
+	} else {
+		fmt.Fprintf(stderr, "%v\n", err)
+	}
 }
 
-func buildAddReviewersArgs(repository string, target string, reviewers []string) []string {
+func buildAddReviewersArgs(repository string, target string, reviewers []reviewer) []string {
 	args := []string{"pr", "edit", target, "--repo", repository}
 
 	for _, reviewer := range reviewers {
-		args = append(args, "--add-reviewer", reviewer)
+		switch reviewer.method {
+		case methodAssignee:
+			args = append(args, "--add-assignee", reviewer.Handle)
+		case methodMention:
+			// mentions are made with a comment rather than by editing
+		case methodReviewer, "":
+			args = append(args, "--add-reviewer", reviewer.Handle)
+		}
 	}
 
 	return args
@@ -112,106 +573,1323 @@ func mustGetUserHomeDir() string {
 	return dir
 }
 
-// ghExecutor invokes a gh command in a subprocess and captures the output and error streams
-type ghExecutor = func(args ...string) (stdout, stderr string)
+// levenshtein calculates the number of single-character edits required to
+// change one string into the other
+func levenshtein(a, b string) int {
+	ar, br := []rune(a), []rune(b)
+	prev := make([]int, len(br)+1)
+	curr := make([]int, len(br)+1)
 
-func run(args []string, stdout, stderr io.Writer, ghExec ghExecutor) int {
-	cli := flag.NewFlagSet("gh rr", flag.ContinueOnError)
+	for j := range prev {
+		prev[j] = j
+	}
 
-	repoF := cli.StringP("repo", "R", "", "select another repository using the [HOST/]OWNER/REPO format")
-	group := cli.StringP("from", "f", "default", "group of users to request review from")
-	globalGroups := cli.BoolP("global", "g", false, "use the global reviewer groups")
-	configDir := cli.String("config-dir", mustGetUserHomeDir(), "directory to search for the configuration file")
-	isDryRun := cli.Bool("dry-run", false, "outputs instead of executing gh")
+	for i := 1; i <= len(ar); i++ {
+		curr[0] = i
 
-	cli.SetOutput(stderr)
+		for j := 1; j <= len(br); j++ {
+			cost := 1
 
-	err := cli.Parse(args)
+			if ar[i-1] == br[j-1] {
+				cost = 0
+			}
 
-	if err != nil {
-		if errors.Is(err, flag.ErrHelp) {
-			return 0
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
 		}
 
-		fmt.Fprintln(stderr, err)
-
-		return 1
+		prev, curr = curr, prev
 	}
 
-	target := cli.Arg(0)
+	return prev[len(br)]
+}
 
-	repo := *repoF
+// suggestFlags returns the names of the flags that are closest to the given
+// name, which is expected to be the name of a flag that does not exist
+func suggestFlags(cli *flag.FlagSet, name string) []string {
+	const maxDistance = 2
 
-	if repo == "" {
-		currentRepo, err := repository.Current()
+	type suggestion struct {
+		name     string
+		distance int
+	}
 
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "could not determine repository: %v\n", err)
+	var suggestions []suggestion
 
-			return 1
+	cli.VisitAll(func(f *flag.Flag) {
+		distance := levenshtein(name, f.Name)
+
+		if distance <= maxDistance || strings.HasPrefix(f.Name, name) {
+			suggestions = append(suggestions, suggestion{f.Name, distance})
 		}
+	})
 
-		repo = fmt.Sprintf("%s/%s", currentRepo.Owner, currentRepo.Name)
-	}
+	slices.SortStableFunc(suggestions, func(a, b suggestion) int {
+		return a.distance - b.distance
+	})
 
-	if _, _, found := strings.Cut(repo, "/"); !found || strings.HasPrefix(repo, "http") {
-		fmt.Fprintln(stderr, "repository should be in the format of <owner>/<repository>")
+	names := make([]string, 0, len(suggestions))
 
-		return 1
+	for _, s := range suggestions {
+		names = append(names, "--"+s.name)
 	}
 
-	confPath := filepath.Join(*configDir, "gh-rr.yml")
-	conf, err := parseConfig(confPath)
+	return names
+}
 
-	if err != nil {
-		if errors.Is(err, os.ErrNotExist) {
-			// todo: this could probably be worded better
-			fmt.Fprintf(stderr, "please create %s to configure your repositories\n", confPath)
-		} else {
-			fmt.Fprintf(stderr, "%v\n", err)
-		}
+// describeFlagError enhances errors about unknown flags with suggestions for
+// flags that the user might have meant to use
+func describeFlagError(cli *flag.FlagSet, err error) string {
+	msg := err.Error()
 
-		return 1
+	var name string
+
+	if n, ok := strings.CutPrefix(msg, "unknown flag: --"); ok {
+		name = n
+	} else if _, n, ok := strings.Cut(msg, "' in -"); ok && strings.HasPrefix(msg, "unknown shorthand flag: ") {
+		// a longhand flag is likely to have been used with a single dash
+		name = n
 	}
 
-	repo2 := repo
+	if name == "" {
+		return msg
+	}
 
-	if *globalGroups {
-		repo2 = "*"
+	suggestions := suggestFlags(cli, name)
+
+	if len(suggestions) == 0 {
+		return msg
 	}
-	reviewers, err := determineReviewers(conf, strings.ToLower(repo2), *group)
 
-	if err != nil {
-		if errors.Is(err, errRepositoryNotConfigured) {
-			fmt.Fprintf(stderr, "no reviewers are configured for %s\n", repo)
-		} else if errors.Is(err, errGroupNotConfigured) {
-			fmt.Fprintf(stderr, "%s does not have a group named %s\n", repo, *group)
-		} else {
-			fmt.Fprintf(stderr, "%v\n", err)
-		}
+	if len(suggestions) == 1 {
+		return fmt.Sprintf("%s, did you mean %s?", msg, suggestions[0])
+	}
 
-		return 1
+	last := len(suggestions) - 1
+
+	return fmt.Sprintf(
+		"%s, did you mean %s or %s?",
+		msg,
+		strings.Join(suggestions[:last], ", "),
+		suggestions[last],
+	)
+}
+
+// commands are the subcommands that can be given as the first argument, which
+// is otherwise treated as the pull request to request reviews on
+var commands = []string{"groups", "assign-issues", "hook", "alias", "remind", "sla", "who", "offboard", "onboard", "generate", "sync", "queue", "flush", "advance", "coverage", "open-config", "simulate", "ready", "join", "leave", "lint", "diff-config", "explain-config", "config", "snooze", "init", "resolve"}
+
+// parseCommand returns the subcommand that has been requested (if any), along
+// with the remaining positional arguments; commands are not recognised after
+// "--", so that branches with the same name as a command can still be targeted
+func parseCommand(cli *flag.FlagSet) (string, []string) {
+	if cli.NArg() > 0 && cli.ArgsLenAtDash() != 0 && slices.Contains(commands, cli.Arg(0)) {
+		return cli.Arg(0), cli.Args()[1:]
 	}
 
-	if *isDryRun {
-		fmt.Fprintf(stdout, "would have used `gh pr edit --repo %s` to request reviews from:\n", repo)
-	} else {
-		url, errMsg := ghExec(buildAddReviewersArgs(repo, target, reviewers)...)
+	return "", cli.Args()
+}
 
-		if errMsg != "" {
-			fmt.Fprintf(stdout, "\ncould not add reviewers: %s\n", strings.TrimSpace(errMsg))
+// the host that repositories are assumed to be on if not otherwise known
+const defaultHost = "github.com"
 
-			return 1
+// ghExecutor invokes a gh command in a subprocess and captures the output and error streams
+type ghExecutor = func(args ...string) (stdout, stderr string)
+
+// newGhExecutor returns a ghExecutor that uses the gh executable at the given
+// path, rather than whichever one would be found on the PATH
+func newGhExecutor(ghPath string) ghExecutor {
+	return func(args ...string) (string, string) {
+		stdout := &bytes.Buffer{}
+		stderr := &bytes.Buffer{}
+
+		cmd := exec.Command(ghPath, args...)
+		cmd.Stdout = stdout
+		cmd.Stderr = stderr
+
+		if err := cmd.Run(); err != nil && stderr.Len() == 0 {
+			return "", fmt.Sprintf("could not run %s: %v", ghPath, err)
 		}
 
-		fmt.Fprintf(stdout, "requested reviews on %s from:\n", url)
+		return strings.TrimSpace(stdout.String()), stderr.String()
 	}
+}
 
-	for _, reviewer := range reviewers {
-		fmt.Fprintf(stdout, "  - %s\n", reviewer)
+// useToken has gh authenticate with the given token rather than whatever it has
+// stored, by setting the environment variables that gh gives precedence to
+func useToken(token string) error {
+	for _, name := range []string{"GH_TOKEN", "GH_ENTERPRISE_TOKEN"} {
+		if err := os.Setenv(name, token); err != nil {
+			return fmt.Errorf("could not set %s: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+func run(args []string, stdout, stderr io.Writer, ghExec ghExecutor) int {
+	return runWith(args, stdout, stderr, ghExec, runOptions{})
+}
+
+// runOptions are things that a run can be given by another run, rather than
+// working them out itself
+type runOptions struct {
+	// sharedConfig is the shared config of the checkout that a milestone sweep
+	// was started from, as each pull request is requested with --repo
+	sharedConfig string
+}
+
+func runWith(args []string, stdout, stderr io.Writer, ghExec ghExecutor, opts runOptions) int {
+	cli := flag.NewFlagSet("gh rr", flag.ContinueOnError)
+
+	repoF := cli.StringP("repo", "R", "", "select another repository using the [HOST/]OWNER/REPO format")
+	group := cli.StringP("from", "f", "default", "group of users to request review from")
+	globalGroups := cli.BoolP("global", "g", false, "use the global reviewer groups")
+	fromAny := cli.Bool("from-any", false, "use the group from any repository if the current one does not have it")
+	configDir := cli.String("config-dir", mustGetUserHomeDir(), "directory to search for the configuration file")
+	configFile := cli.String("config", "", "path or url of the configuration file to use instead of the one in the config directory")
+	configTTL := cli.Duration("config-ttl", 0, "how long to reuse a configuration file downloaded from a url before downloading it again")
+	isDryRun := cli.Bool("dry-run", false, "outputs instead of executing gh")
+	profile := cli.String("profile", "", "name of the profile in the configuration file to use (default $GH_RR_PROFILE)")
+	sweepLabel := cli.String("sweep", "", "assign all open unassigned issues with this label (assign-issues only)")
+	days := cli.Int("days", 2, "number of days a review request can go unanswered before reminding (remind only)")
+	reRequest := cli.Bool("re-request", false, "re-request reviews as well as commenting (remind only)")
+	format := cli.String("format", "text", "output format, either text, csv (sla only), or json")
+	check := cli.Bool("check", false, "only check the config for drift, which is the default (sync only)")
+	write := cli.Bool("write", false, "update the config instead of only checking it (sync only)")
+	org := cli.String("org", "", "organization to generate a config for (generate only)")
+	onboardGroups := cli.StringSlice("groups", nil, "groups to add the person to (onboard only)")
+	onboardRepos := cli.StringSlice("repos", nil, "repositories to add the person to groups in, supporting * wildcards (onboard only)")
+	order := cli.String("order", "", "order to request reviews in, either config, alphabetical, or shuffle (default from settings, otherwise config)")
+	waitChecks := cli.Bool("wait-checks", false, "wait for the checks of the pull request to pass before requesting reviews")
+	requireChecks := cli.Bool("require-checks", false, "fail instead of requesting reviews if the checks of the pull request have not passed")
+	checksTimeout := cli.Duration("checks-timeout", 30*time.Minute, "how long to wait for checks to finish (wait-checks only)")
+	checksInterval := cli.Duration("checks-interval", 15*time.Second, "how often to poll the checks while waiting (wait-checks only)")
+	search := cli.String("search", "", "request reviews on every open pull request matching this search query")
+	milestoneSweep := cli.String("milestone-sweep", "", "request reviews on every open pull request in this milestone, routing each based on the config")
+	base := cli.String("base", "", "base branch of the pull request to pick, for when its branch has multiple open pull requests")
+	head := cli.String("head", "", "head branch of the pull request to pick in the [OWNER:]BRANCH format, for when forks share branch names")
+	force := cli.Bool("force", false, "request reviews even if the pull request does not pass the configured guards")
+	outputTemplate := cli.String("template", "", "go template for customizing the output after requesting reviews (default from settings)")
+	explain := cli.Bool("explain", false, "output what the group is for and why any of its reviewers were skipped")
+	exceptTeams := cli.StringSlice("except-team", nil, "team in the ORG/SLUG format whose members should not be requested (default from settings)")
+	urgent := cli.Bool("urgent", false, "mark the request as urgent by labelling the pull request and highlighting notifications")
+	strict := cli.Bool("strict", false, "error if the config has any keys that are not known")
+	workload := cli.Bool("workload", false, "show how many open review requests each reviewer currently has")
+	record := cli.String("record", "", "save every call made to gh to this file, so that they can be replayed")
+	replay := cli.String("replay", "", "respond to calls to gh using a file saved with --record, instead of running gh")
+	since := cli.String("since", "", "date to simulate routing pull requests from, in the format of YYYY-MM-DD (simulate only)")
+	until := cli.String("until", "", "date to simulate routing pull requests until, or to snooze reminders until, in the format of YYYY-MM-DD (simulate and snooze only)")
+	openDir := cli.Bool("open", false, "open the directory containing the configuration file (open-config only)")
+	token := cli.String("token", "", "token to authenticate with instead of the one stored by gh (default $GH_RR_TOKEN)")
+	ghPath := cli.String("gh-path", "", "path to the gh executable to use (default $GH_RR_GH_PATH)")
+
+	if len(args) > 0 && args[0] == completionCommand {
+		return runCompletion(stdout, cli, args[1:])
+	}
+
+	cli.SetOutput(stderr)
+
+	err := cli.Parse(args)
+
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+
+		fmt.Fprintln(stderr, describeFlagError(cli, err))
+
+		return 1
+	}
+
+	remoteConfig := ""
+
+	// configs from a url are downloaded so that they can be used like any other
+	if isRemoteConfig(*configFile) {
+		remoteConfig = *configFile
+		*configFile, err = fetchRemoteConfig(stderr, *configDir, remoteConfig, *configTTL)
+
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+
+			return 1
+		}
+	}
+
+	personalPaths, defaultPath := personalConfigPaths(*configDir, cli.Changed("config-dir"))
+	personalPath := findPersonalConfig(personalPaths, defaultPath)
+	confPath := personalPath
+
+	// commands that work with a single config use the one given with --config
+	if *configFile != "" {
+		confPath = *configFile
+	}
+
+	if *ghPath == "" {
+		*ghPath = os.Getenv("GH_RR_GH_PATH")
+	}
+
+	if *ghPath != "" {
+		ghExec = newGhExecutor(*ghPath)
+	}
+
+	if *token == "" {
+		*token = os.Getenv("GH_RR_TOKEN")
+	}
+
+	if *token != "" {
+		if err := useToken(*token); err != nil {
+			fmt.Fprintln(stderr, err)
+
+			return 1
+		}
+	}
+
+	if *record != "" && *replay != "" {
+		fmt.Fprintln(stderr, "--record and --replay cannot be used together")
+
+		return 1
+	}
+
+	if *record != "" {
+		ghExec = newRecordingGhExecutor(stderr, *record, ghExec)
+	}
+
+	if *replay != "" {
+		var err error
+
+		ghExec, err = newReplayingGhExecutor(*replay)
+
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+
+			return 1
+		}
+	}
+
+	command, positionals := parseCommand(cli)
+	target := ""
+
+	// ready is the same as requesting reviews, just with the pull request being
+	// marked as ready for review first
+	isReady := command == "ready"
+
+	if isReady {
+		command = ""
+	}
+
+	if len(positionals) > 0 {
+		target = positionals[0]
+	}
+
+	// references like OWNER/REPO#NUMBER select both the repository and the pull
+	// request, the same as when they are given separately
+	if reference, number, ok := parsePullRequestReference(target); ok {
+		*repoF = reference
+		target = number
+	}
+
+	// the repository to explain can be given the same as any other target
+	if command == "explain-config" && len(positionals) > 0 {
+		if len(positionals) > 1 {
+			fmt.Fprintln(stderr, "only one repository can be explained at a time")
+
+			return 1
+		}
+
+		*repoF = positionals[0]
+	}
+
+	// validating only reads the config, so it can be downloaded from a url
+	isValidate := command == "config" && len(positionals) > 0 && positionals[0] == "validate"
+
+	if remoteConfig != "" && (command == "offboard" || command == "onboard" || (command == "config" && !isValidate) || command == "init" || (command == "sync" && *write)) {
+		fmt.Fprintf(stderr, "%s cannot be edited as it was downloaded from a url\n", remoteConfig)
+
+		return 1
+	}
+
+	switch command {
+	case "hook":
+		return runHookCommand(stdout, stderr, ghExec, positionals, forwardedFlags(cli))
+	case "alias":
+		return runAliasCommand(stdout, stderr, ghExec, positionals, forwardedFlags(cli))
+	case "offboard":
+		return offboard(stdout, stderr, confPath, positionals, *isDryRun)
+	case "sync":
+		if *check && *write {
+			fmt.Fprintln(stderr, "--check and --write cannot be used together")
+
+			return 1
+		}
+
+		return syncTeams(stdout, stderr, ghExec, syncOptions{
+			file:     confPath,
+			write:    *write,
+			isDryRun: *isDryRun,
+		})
+	case "flush":
+		conf, err := parseConfig(confPath)
+
+		if err != nil {
+			printParseConfigError(stderr, confPath, err)
+
+			return 1
+		}
+
+		hours := defaultWorkingHours
+
+		if conf.Settings.WorkingHours != nil {
+			hours = *conf.Settings.WorkingHours
+		}
+
+		return flushQueue(stdout, stderr, ghExec, flushOptions{
+			file:     filepath.Join(*configDir, "gh-rr-queue.json"),
+			hours:    hours,
+			now:      time.Now(),
+			isDryRun: *isDryRun,
+		})
+	case "sla", "who":
+		conf, err := parseUnresolvedConfig(ghExec, confPath, *configFile, *profile)
+
+		if errors.Is(err, errProfileNotConfigured) {
+			fmt.Fprintf(stderr, "%s does not have a profile named %s\n", confPath, *profile)
+
+			return 1
+		}
+
+		if err != nil {
+			printParseConfigError(stderr, confPath, err)
+
+			return 1
+		}
+
+		if command == "sla" {
+			return reportSLAs(stdout, stderr, ghExec, conf, *format)
+		}
+
+		return printMemberships(stdout, stderr, ghExec, conf, positionals)
+	case "diff-config":
+		return printConfigDiff(stdout, stderr, ghExec, diffConfigOptions{
+			sources: positionals,
+			current: confPath,
+		})
+	case "config":
+		if isValidate {
+			if len(positionals) > 1 {
+				confPath = positionals[1]
+			}
+
+			return validateConfig(stdout, stderr, confPath)
+		}
+
+		if len(positionals) > 0 && slices.Contains(configGroupCommands, positionals[0]) {
+			return editConfigGroups(stdout, stderr, configGroupOptions{
+				file:     confPath,
+				command:  positionals[0],
+				args:     positionals[1:],
+				group:    *group,
+				isDryRun: *isDryRun,
+			})
+		}
+
+		if len(positionals) > 0 && positionals[0] == "edit" {
+			return editConfig(stdout, stderr, editConfigOptions{
+				file:   confPath,
+				editor: findEditor(),
+			})
+		}
+
+		return syncConfig(stdout, stderr, ghExec, configSyncOptions{
+			file:     confPath,
+			args:     positionals,
+			isDryRun: *isDryRun,
+		})
+	case "lint":
+		return lintConfig(stdout, stderr, ghExec, confPath)
+	case "open-config":
+		return printConfigPaths(stdout, stderr, personalPaths, *configFile, *openDir)
+	case "generate":
+		return generateConfig(stdout, stderr, ghExec, *org)
+	case "onboard":
+		return onboard(stdout, stderr, onboardOptions{
+			file:     confPath,
+			args:     positionals,
+			groups:   *onboardGroups,
+			repos:    *onboardRepos,
+			isDryRun: *isDryRun,
+		})
+	case "init":
+		opts := initOptions{
+			file:      confPath,
+			stdin:     os.Stdin,
+			repo:      *repoF,
+			group:     *group,
+			reviewers: positionals,
+			isDryRun:  *isDryRun,
+			canPrompt: canPrompt(),
+		}
+
+		// the current repository is only a suggestion, so it's fine if there is not one
+		if opts.repo == "" {
+			if currentRepo, err := currentRepository(); err == nil {
+				opts.repo = fmt.Sprintf("%s/%s", currentRepo.Owner, currentRepo.Name)
+			}
+		}
+
+		return initConfig(stdout, stderr, opts)
+	}
+
+	repo := *repoF
+	host := defaultHost
+
+	if repo == "" {
+		currentRepo, err := currentRepository()
+
+		if err != nil {
+			fmt.Fprintf(stderr, "could not determine repository: %v\n", err)
+
+			return 1
+		}
+
+		repo = fmt.Sprintf("%s/%s", currentRepo.Owner, currentRepo.Name)
+		host = currentRepo.Host
+	}
+
+	// repositories on other hosts can be selected using HOST/OWNER/REPO
+	if strings.Count(repo, "/") == 2 {
+		host, _, _ = strings.Cut(repo, "/")
+	}
+
+	if _, _, found := strings.Cut(repo, "/"); !found || strings.HasPrefix(repo, "http") {
+		fmt.Fprintln(stderr, "repository should be in the format of <owner>/<repository>")
+
+		return 1
+	}
+
+	sharedPath := opts.sharedConfig
+
+	// the shared config can only be found when within a checkout of the repository
+	if *repoF == "" {
+		sharedPath = findLocalSharedConfig()
+	}
+
+	if *milestoneSweep != "" {
+		if command != "" || isReady || *search != "" {
+			fmt.Fprintln(stderr, "--milestone-sweep can only be used when requesting reviews")
+
+			return 1
+		}
+
+		if len(positionals) > 0 || *base != "" || *head != "" {
+			fmt.Fprintln(stderr, "--milestone-sweep cannot be used with a pull request")
+
+			return 1
+		}
+
+		if *format == "json" {
+			fmt.Fprintln(stderr, "--format json cannot be used with --milestone-sweep")
+
+			return 1
+		}
+
+		// the host has to be kept when the repository was inferred from the checkout
+		sweepRepo := repo
+
+		if host != defaultHost && strings.Count(repo, "/") == 1 {
+			sweepRepo = host + "/" + repo
+		}
+
+		args := sweepArgs(forwardedFlags(cli), sweepRepo)
+
+		return sweepMilestone(stdout, stderr, ghExec, milestoneSweepOptions{
+			repo:      repo,
+			milestone: *milestoneSweep,
+			request: func(number string) int {
+				return runWith(append(slices.Clone(args), number), stdout, stderr, ghExec, runOptions{sharedConfig: sharedPath})
+			},
+		})
+	}
+
+	if *base != "" || *head != "" {
+		number, err := findBranchPullRequest(ghExec, repo, target, *base, *head)
+
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+
+			return 1
+		}
+
+		target = number
+	}
+
+	// the shared config is edited rather than the personal one
+	if command == "join" || command == "leave" {
+		return changeMembership(stdout, stderr, ghExec, membershipOptions{
+			repo:     repo,
+			args:     positionals,
+			isLeave:  command == "leave",
+			isDryRun: *isDryRun,
+		})
+	}
+
+	conf, failedPath, err := parseLayeredConfig(personalPath, sharedPath, *configFile)
+
+	// offer to set things up for first-time users rather than just telling
+	// them to go and create a config themselves
+	if errors.Is(err, os.ErrNotExist) && failedPath == confPath && canPrompt() {
+		return initConfig(stdout, stderr, initOptions{
+			file:      confPath,
+			stdin:     os.Stdin,
+			repo:      repo,
+			group:     *group,
+			canPrompt: true,
+			confirm:   true,
+		})
+	}
+
+	if err != nil {
+		printParseConfigError(stderr, failedPath, err)
+
+		return 1
+	}
+
+	if *strict || conf.Settings.Strict {
+		for _, file := range []string{personalPath, sharedPath, *configFile} {
+			if file == "" {
+				continue
+			}
+
+			if err := checkConfigStrictly(file); err != nil && !errors.Is(err, os.ErrNotExist) {
+				printParseConfigError(stderr, file, err)
+
+				return 1
+			}
+		}
+	}
+
+	conf, extended, err := resolveExtendedConfig(ghExec, conf)
+
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+
+		return 1
+	}
+
+	if *profile == "" {
+		*profile = os.Getenv("GH_RR_PROFILE")
+	}
+
+	if *profile == "" {
+		owner, _, _ := strings.Cut(repo, "/")
+		*profile = matchProfile(conf, host, owner)
+	}
+
+	conf, err = selectProfile(conf, *profile)
+
+	if err != nil {
+		fmt.Fprintf(stderr, "%s does not have a profile named %s\n", confPath, *profile)
+
+		return 1
+	}
+
+	conf = resolveHostConfig(conf, host, repo)
+
+	// repositories without groups of their own use those of a matching pattern,
+	// and then use the groups of their owner for any groups they do not have
+	conf.Repositories = resolveRepositoryPattern(conf.Repositories, repo)
+	conf.Repositories = resolveOwnerGroups(conf, repo)
+	conf.Repositories = resolveReviewerAliases(conf.Repositories, conf.Aliases)
+	conf.Repositories = resolveGroupReferences(conf.Repositories)
+
+	if command == "groups" {
+		return listGroups(stdout, stderr, conf, repo)
+	}
+
+	if command == "explain-config" {
+		return explainConfig(stdout, stderr, explainOptions{
+			host:     host,
+			repo:     repo,
+			personal: personalPath,
+			shared:   sharedPath,
+			extra:    *configFile,
+			extends:  conf.Extends,
+			extended: extended,
+			profile:  *profile,
+		})
+	}
+
+	if command == "simulate" {
+		if len(positionals) > 0 {
+			fmt.Fprintln(stderr, "simulate checks every pull request created in the given dates, so does not take any arguments")
+
+			return 1
+		}
+
+		return simulateRouting(stdout, stderr, ghExec, conf, simulateOptions{
+			repo:  repo,
+			since: *since,
+			until: *until,
+		})
+	}
+
+	if command == "coverage" {
+		if len(positionals) > 1 {
+			fmt.Fprintln(stderr, "coverage can only be checked on one pull request at a time")
+
+			return 1
+		}
+
+		return reportCoverage(stdout, stderr, ghExec, conf, repo, target)
+	}
+
+	if command == "advance" {
+		if len(positionals) > 1 {
+			fmt.Fprintln(stderr, "reviews can only be advanced on one pull request at a time")
+
+			return 1
+		}
+
+		return advance(stdout, stderr, ghExec, conf, advanceOptions{
+			repo:     repo,
+			target:   target,
+			isDryRun: *isDryRun,
+		})
+	}
+
+	if command == "snooze" {
+		if len(positionals) > 1 {
+			fmt.Fprintln(stderr, "reminders can only be snoozed on one pull request at a time")
+
+			return 1
+		}
+
+		return snooze(stdout, stderr, ghExec, snoozeOptions{
+			repo:      repo,
+			target:    target,
+			until:     *until,
+			statePath: stateFilePath(*configDir),
+			stateGist: conf.Settings.StateGist,
+			isDryRun:  *isDryRun,
+		})
+	}
+
+	if (command == "" || command == "queue") && conf.Settings.OnArchived != "" {
+		archived, err := isRepositoryArchived(ghExec, repo)
+
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+
+			return 1
+		}
+
+		if archived {
+			return reportArchived(stderr, conf.Settings.OnArchived, repo)
+		}
+	}
+
+	// the group that is used when one has not been requested can be configured
+	defaultGroup := *group
+
+	if !cli.Changed("from") {
+		if *globalGroups {
+			defaultGroup = findDefaultGroup(conf, "*")
+		} else {
+			defaultGroup = findDefaultGroup(conf, repo)
+		}
+
+		*group = defaultGroup
+	}
+
+	// only consult the author rules when a group has not been explicitly requested
+	if (command == "" || command == "queue") && *search == "" && len(conf.Authors) > 0 && !cli.Changed("from") {
+		author, err := fetchPullRequestAuthor(ghExec, repo, target)
+
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+
+			return 1
+		}
+
+		if rule, ok := findAuthorRule(conf.Authors, author); ok {
+			if rule.Skip {
+				fmt.Fprintf(stdout, "not requesting reviews as the pull request was authored by %s\n", author)
+
+				return 0
+			}
+
+			if rule.Group != "" {
+				*group = rule.Group
+			}
+		}
+	}
+
+	if (command == "" || command == "queue") && *search == "" && len(conf.Settings.SkipPaths) > 0 && !*force {
+		skip, err := shouldSkipPullRequest(ghExec, conf.Settings.SkipPaths, repo, target)
+
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+
+			return 1
+		}
+
+		if skip {
+			fmt.Fprintln(stdout, "not requesting reviews as the pull request only changes files that do not need reviewing")
+
+			return 0
+		}
+	}
+
+	repo2 := repo
+
+	if *globalGroups {
+		repo2 = "*"
+	}
+
+	var currentUser string
+
+	// only infer the group when one has not been explicitly requested or picked by an author rule
+	if (command == "" || command == "queue" || command == "resolve") && conf.Settings.GroupFromTeam && !cli.Changed("from") && *group == defaultGroup {
+		currentUser, err = fetchCurrentUser(ghExec)
+
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+
+			return 1
+		}
+
+		inferred, team, err := inferGroupFromTeams(ghExec, conf, repo2, currentUser)
+
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+
+			return 1
+		}
+
+		if inferred != "" {
+			fmt.Fprintf(stderr, "using the %s group as you are a member of %s\n", inferred, team)
+
+			*group = inferred
+		}
+	}
+
+	reviewers, err := determineReviewers(conf, strings.ToLower(repo2), *group)
+
+	if err != nil && !*globalGroups && (*fromAny || conf.Settings.GroupSearch == groupSearchAny) {
+		var foundIn string
+
+		foundIn, reviewers, err = findGroupInAnyRepository(conf, *group)
+
+		if err == nil {
+			repo2 = foundIn
+
+			if foundIn == "*" {
+				fmt.Fprintf(stderr, "using the global %s group\n", *group)
+			} else {
+				fmt.Fprintf(stderr, "using the %s group from %s\n", *group, foundIn)
+			}
+		}
+	}
+
+	if err != nil {
+		printReviewersError(stderr, err, repo, *group)
+
+		return 1
+	}
+
+	if *explain {
+		if description := conf.Repositories[strings.ToLower(repo2)][*group].Description; description != "" {
+			fmt.Fprintf(stderr, "the %s group is for %s\n", *group, description)
+		}
+	}
+
+	reviewers, err = expandDynamicReviewers(ghExec, reviewers)
+
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+
+		return 1
+	}
+
+	// recent contributors can only be found for a single pull request
+	if (command == "" || command == "queue") && *search == "" {
+		reviewers, err = expandContributors(ghExec, repo, target, reviewers)
+
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+
+			return 1
+		}
+	} else {
+		reviewers = withoutContributors(reviewers)
+	}
+
+	// people cannot review their own pull requests
+	if currentUser != "" {
+		reviewers = excludeReviewer(reviewers, currentUser)
+	}
+
+	var optedOut map[string]string
+
+	members := &teamMemberCache{ghExec: ghExec, members: make(map[string][]string)}
+
+	if len(conf.Settings.OptedOut) > 0 {
+		optedOut, err = resolveOptedOut(members, conf.Settings.OptedOut)
+
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+
+			return 1
+		}
+
+		reviewers = excludeOptedOut(stderr, reviewers, optedOut, *explain)
+	}
+
+	if !cli.Changed("except-team") {
+		*exceptTeams = conf.Settings.ExceptTeams
+	}
+
+	var excepted map[string]string
+
+	if len(*exceptTeams) > 0 {
+		excepted, err = resolveExceptTeams(members, *exceptTeams)
+
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+
+			return 1
+		}
+
+		reviewers = excludeTeamMembers(stderr, reviewers, excepted, *explain)
+	}
+
+	if len(reviewers) == 0 {
+		fallback, fallbackReviewers, err := resolveFallback(ghExec, conf, repo2, *group)
+
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+
+			return 1
+		}
+
+		if fallback != "" {
+			fmt.Fprintf(stderr, "the %s group does not have any reviewers, so using the %s group instead\n", *group, fallback)
+
+			*group = fallback
+			reviewers = excludeOptedOut(stderr, fallbackReviewers, optedOut, *explain)
+			reviewers = excludeTeamMembers(stderr, reviewers, excepted, *explain)
+		}
+	}
+
+	var alwaysRequested map[string]bool
+
+	if command == "" || command == "queue" || command == "resolve" {
+		reviewers, alwaysRequested, err = includeAlwaysGroup(conf, repo, *group, reviewers)
+
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+
+			return 1
+		}
+
+		reviewers, err = expandDynamicReviewers(ghExec, reviewers)
+
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+
+			return 1
+		}
+	}
+
+	if command == "assign-issues" {
+		return assignIssues(stdout, stderr, ghExec, assignIssuesOptions{
+			repo:       repo,
+			groupRepo:  repo2,
+			group:      *group,
+			reviewers:  reviewers,
+			issues:     positionals,
+			sweepLabel: *sweepLabel,
+			statePath:  stateFilePath(*configDir),
+			stateGist:  conf.Settings.StateGist,
+			isDryRun:   *isDryRun,
+		})
+	}
+
+	if command == "remind" {
+		if len(positionals) > 0 {
+			fmt.Fprintln(stderr, "remind checks every open pull request, so does not take any arguments")
+
+			return 1
+		}
+
+		esc, err := resolveEscalation(ghExec, conf, repo2, *group)
+
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+
+			return 1
+		}
+
+		st, err := readState(ghExec, stateFilePath(*configDir), conf.Settings.StateGist)
+
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+
+			return 1
+		}
+
+		return remind(stdout, stderr, ghExec, remindOptions{
+			repo:       repo,
+			group:      *group,
+			reviewers:  reviewers,
+			days:       *days,
+			reRequest:  *reRequest,
+			template:   conf.Settings.ReminderTemplate,
+			escalation: esc,
+			snoozed:    st.Snoozed,
+			isDryRun:   *isDryRun,
+		})
+	}
+
+	if *order == "" {
+		*order = string(conf.Settings.Order)
+	}
+
+	if *order != "" {
+		if !reviewerOrder(*order).isValid() {
+			fmt.Fprintf(stderr, "unsupported order %s, must be one of config, alphabetical, or shuffle\n", *order)
+
+			return 1
+		}
+
+		reviewers = sortReviewers(reviewers, reviewerOrder(*order))
+	}
+
+	if command == "resolve" {
+		if len(positionals) > 0 {
+			fmt.Fprintln(stderr, "resolve does not look at any pull requests, so does not take any arguments")
+
+			return 1
+		}
+
+		if *format != "text" && *format != "json" {
+			fmt.Fprintf(stderr, "unsupported format %s, must be either text or json\n", *format)
+
+			return 1
+		}
+
+		return printResolution(stdout, stderr, resolveOptions{
+			repo:            repo,
+			group:           *group,
+			reviewers:       reviewers,
+			alwaysRequested: alwaysRequested,
+			format:          *format,
+		})
+	}
+
+	var urgentLabel string
+
+	if *urgent {
+		urgentLabel = conf.Settings.UrgentLabel
+
+		if urgentLabel == "" {
+			urgentLabel = defaultUrgentLabel
+		}
+	}
+
+	if command == "queue" {
+		if *urgent {
+			fmt.Fprintln(stderr, "urgent requests cannot be queued")
+
+			return 1
+		}
+
+		if len(positionals) > 1 {
+			fmt.Fprintln(stderr, "reviews can only be queued on one pull request at a time")
+
+			return 1
+		}
+
+		return queueRequest(stdout, stderr, ghExec, queueOptions{
+			file:      filepath.Join(*configDir, "gh-rr-queue.json"),
+			host:      host,
+			repo:      repo,
+			target:    target,
+			group:     *group,
+			reviewers: reviewers,
+			isDryRun:  *isDryRun,
+		})
+	}
+
+	if *waitChecks && *requireChecks {
+		fmt.Fprintln(stderr, "--wait-checks and --require-checks cannot be used together")
+
+		return 1
+	}
+
+	if *format != "text" && *format != "json" {
+		fmt.Fprintf(stderr, "unsupported format %s, must be either text or json\n", *format)
+
+		return 1
+	}
+
+	if *search != "" {
+		if isReady {
+			fmt.Fprintln(stderr, "ready cannot be used with --search")
+
+			return 1
+		}
+
+		if cli.Changed("template") {
+			fmt.Fprintln(stderr, "--template cannot be used with --search")
+
+			return 1
+		}
+
+		if len(positionals) > 0 {
+			fmt.Fprintln(stderr, "--search cannot be used with a pull request")
+
+			return 1
+		}
+
+		if *format == "json" {
+			fmt.Fprintln(stderr, "--format json cannot be used with --search")
+
+			return 1
+		}
+
+		opts := searchOptions{
+			repo:            repo,
+			query:           *search,
+			group:           *group,
+			reviewers:       reviewers,
+			perPullRequest:  conf.Repositories[strings.ToLower(repo2)][*group].ReviewersPerPR,
+			order:           reviewerOrder(*order),
+			alwaysRequested: alwaysRequested,
+			notifications:   notificationsFor(conf, repo2, *group),
+			urgentLabel:     urgentLabel,
+			isDryRun:        *isDryRun,
+		}
+
+		if !*force {
+			opts.guards = conf.Settings.Guards
+			opts.skipPaths = conf.Settings.SkipPaths
+		}
+
+		if *waitChecks || *requireChecks {
+			opts.checks = &checksOptions{
+				repo:     repo,
+				wait:     *waitChecks,
+				timeout:  *checksTimeout,
+				interval: *checksInterval,
+			}
+		}
+
+		// the same as with a single pull request, author rules only apply when a
+		// group has not been explicitly requested
+		if len(conf.Authors) > 0 && !cli.Changed("from") {
+			args := sweepArgs(forwardedFlags(cli), qualifyRepository(host, repo))
+
+			opts.authors = conf.Authors
+			opts.route = func(number, group string) int {
+				return runWith(append(slices.Clone(args), "--from", group, number), stdout, stderr, ghExec, runOptions{sharedConfig: sharedPath})
+			}
+		}
+
+		return requestOnSearchResults(stdout, stderr, ghExec, opts)
+	}
+
+	if *format == "json" {
+		if isReady {
+			fmt.Fprintln(stderr, "--format json cannot be used with ready")
+
+			return 1
+		}
+
+		if cli.Changed("template") {
+			fmt.Fprintln(stderr, "--template cannot be used with --format json")
+
+			return 1
+		}
+
+		// a plan of what would be done is output when doing a dry-run, rather
+		// than the result of what has been done
+		if *isDryRun {
+			if err := printPlan(stdout, newPlan(repo, target, *group, reviewers, urgentLabel)); err != nil {
+				fmt.Fprintln(stderr, err)
+
+				return 1
+			}
+
+			return 0
+		}
+	}
+
+	if *outputTemplate == "" {
+		*outputTemplate = conf.Settings.OutputTemplate
+	}
+
+	var tmpl *template.Template
+
+	if *outputTemplate != "" && *format != "json" {
+		tmpl, err = parseOutputTemplate(*outputTemplate)
+
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+
+			return 1
+		}
+	}
+
+	if !conf.Settings.Guards.isEmpty() && !*force {
+		if !enforceGuards(stderr, ghExec, conf.Settings.Guards, repo, target, "the pull request") {
+			return 1
+		}
+	}
+
+	var workloads map[string]int
+
+	// the workloads are fetched first so they do not include this pull request
+	if *workload {
+		workloads, err = fetchWorkloads(ghExec, reviewers)
+
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+
+			return 1
+		}
+	}
+
+	var url string
+
+	if *isDryRun {
+		if tmpl == nil && isReady {
+			fmt.Fprintf(stdout, "would have used `gh pr ready --repo %s` and `gh pr edit --repo %s` to request reviews from:\n", repo, repo)
+		} else if tmpl == nil {
+			fmt.Fprintf(stdout, "would have used `gh pr edit --repo %s` to request reviews from:\n", repo)
+		}
+	} else {
+		if *waitChecks || *requireChecks {
+			err := gateOnChecks(stderr, ghExec, checksOptions{
+				repo:     repo,
+				target:   target,
+				wait:     *waitChecks,
+				timeout:  *checksTimeout,
+				interval: *checksInterval,
+			})
+
+			if err != nil {
+				fmt.Fprintln(stderr, err)
+
+				return 1
+			}
+		}
+
+		if isReady {
+			if err := markReadyForReview(ghExec, repo, target); err != nil {
+				fmt.Fprintln(stderr, err)
+
+				return 1
+			}
+		}
+
+		if conf.Settings.OnNoop != "" {
+			pr, err := fetchPullRequest(ghExec, repo, target, "reviewRequests")
+
+			if err != nil {
+				fmt.Fprintln(stderr, err)
+
+				return 1
+			}
+
+			if hasRequestedReviewFromAll(pr, reviewers) {
+				return reportNoop(stdout, stderr, conf.Settings.OnNoop, *group)
+			}
+		}
+
+		url, err = attachReviewers(ghExec, repo, target, reviewers, urgentLabel)
+
+		if err != nil {
+			fmt.Fprintf(stdout, "\ncould not add reviewers: %v\n", err)
+
+			return 1
+		}
+
+		if tmpl == nil && isReady {
+			fmt.Fprintf(stdout, "marked %s as ready for review and requested reviews from:\n", url)
+		} else if tmpl == nil && *format != "json" {
+			fmt.Fprintf(stdout, "requested reviews on %s from:\n", url)
+		}
+	}
+
+	exit := 0
+
+	if *format == "json" {
+		res, err := fetchRequestResult(ghExec, repo, target, *group, reviewers)
+
+		if err == nil {
+			err = printRequestResult(stdout, res)
+			url = res.URL
+		}
+
+		// reviews have already been requested, so notifications are still sent
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+
+			exit = 1
+		}
+	} else if tmpl != nil {
+		err := printOutput(stdout, tmpl, outputData{
+			Repository:  repo,
+			PullRequest: target,
+			URL:         url,
+			Group:       *group,
+			Reviewers:   reviewers,
+			DryRun:      *isDryRun,
+		})
+
+		// reviews have already been requested, so notifications are still sent
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+
+			exit = 1
+		}
+	} else {
+		for _, reviewer := range reviewers {
+			var notes []string
+
+			if alwaysRequested[strings.ToLower(reviewer.Handle)] {
+				notes = append(notes, "always requested")
+			}
+
+			if note := describeMethod(reviewer); note != "" {
+				notes = append(notes, note)
+			}
+
+			if count, ok := workloads[strings.ToLower(reviewer.Handle)]; ok {
+				notes = append(notes, describeWorkload(count))
+			}
+
+			if len(notes) == 0 {
+				fmt.Fprintf(stdout, "  - %s\n", reviewer)
+			} else {
+				fmt.Fprintf(stdout, "  - %s (%s)\n", reviewer, strings.Join(notes, ", "))
+			}
+		}
+
+		printUrgent(stdout, urgentLabel, *isDryRun)
+	}
+
+	if !*isDryRun {
+		sendNotifications(stderr, notificationsFor(conf, repo2, *group), reviewRequest{
+			Repository: repo,
+			Group:      *group,
+			URL:        url,
+			Reviewers:  reviewers,
+			Urgent:     *urgent,
+		})
 	}
 
-	return 0
+	return exit
 }
 
 func main() {
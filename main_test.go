@@ -3,6 +3,8 @@ package main
 import (
 	"bytes"
 	"os"
+	"path/filepath"
+	"runtime"
 	"slices"
 	"testing"
 
@@ -58,6 +60,66 @@ func Test_run(t *testing.T) {
 			},
 			exit: 1,
 		},
+		{
+			name: "when a partial flag is requested",
+			args: args{
+				args:   []string{"--dry"},
+				ghExec: expectNoCallToGh(t),
+				config: `
+					repositories:
+						octocat/hello-world:
+							default:
+								- octodog
+								- octopus
+				`,
+			},
+			exit: 1,
+		},
+		{
+			name: "when a mistyped flag is requested",
+			args: args{
+				args:   []string{"--form", "infra"},
+				ghExec: expectNoCallToGh(t),
+				config: `
+					repositories:
+						octocat/hello-world:
+							default:
+								- octodog
+								- octopus
+				`,
+			},
+			exit: 1,
+		},
+		{
+			name: "when a mistyped flag is close to multiple flags",
+			args: args{
+				args:   []string{"--ro"},
+				ghExec: expectNoCallToGh(t),
+				config: `
+					repositories:
+						octocat/hello-world:
+							default:
+								- octodog
+								- octopus
+				`,
+			},
+			exit: 1,
+		},
+		{
+			name: "when a longhand flag is used with a single dash",
+			args: args{
+				args:   []string{"-dry-run"},
+				ghExec: expectNoCallToGh(t),
+				config: `
+					repositories:
+						octocat/hello-world:
+							default:
+								- octodog
+								- octopus
+				`,
+			},
+			exit: 1,
+		},
 		{
 			name: "when no arguments are provided",
 			args: args{
@@ -169,6 +231,38 @@ func Test_run(t *testing.T) {
 			},
 			exit: 1,
 		},
+		{
+			name: "when the config file is invalid deeper within the file",
+			args: args{
+				args:   []string{"123"},
+				ghExec: expectNoCallToGh(t),
+				config: `
+					repositories:
+						octocat/hello-world:
+							default:
+								- octodog
+							infra: octopus
+						octocat/hello-sunshine:
+							- octodog
+				`,
+			},
+			exit: 1,
+		},
+		{
+			name: "when the config file has a syntax error",
+			args: args{
+				args:   []string{"123"},
+				ghExec: expectNoCallToGh(t),
+				config: `
+					repositories:
+						octocat/hello-world:
+							default:
+								- octodog
+							 - octopus
+				`,
+			},
+			exit: 1,
+		},
 		{
 			name: "when the repository does not exist in config",
 			args: args{
@@ -231,6 +325,71 @@ func Test_run(t *testing.T) {
 			},
 			exit: 0,
 		},
+		{
+			name: "when doing a dry-run with json output",
+			args: args{
+				args:   []string{"--dry-run", "--format", "json", "123"},
+				ghExec: expectNoCallToGh(t),
+				config: `
+					repositories:
+						octocat/hello-world:
+							default:
+								- octocat
+								- octodog
+				`,
+			},
+			exit: 0,
+		},
+		{
+			name: "when doing a dry-run with json output for an urgent request with mentions",
+			args: args{
+				args:   []string{"--dry-run", "--format", "json", "--urgent", "123"},
+				ghExec: expectNoCallToGh(t),
+				config: `
+					repositories:
+						octocat/hello-world:
+							default:
+								- octocat
+								- octodog
+							always:
+								method: mention-comment
+								reviewers: [octobear]
+				`,
+			},
+			exit: 0,
+		},
+		{
+			name: "when doing a dry-run with json output for a group that is assigned",
+			args: args{
+				args:   []string{"--dry-run", "--format", "json", "--from", "triage", "123"},
+				ghExec: expectNoCallToGh(t),
+				config: `
+					repositories:
+						octocat/hello-world:
+							default:
+								- octocat
+							triage:
+								method: assignee
+								reviewers: [octokitten]
+				`,
+			},
+			exit: 0,
+		},
+		{
+			name: "when using an unsupported output format",
+			args: args{
+				args:   []string{"--dry-run", "--format", "csv", "123"},
+				ghExec: expectNoCallToGh(t),
+				config: `
+					repositories:
+						octocat/hello-world:
+							default:
+								- octocat
+								- octodog
+				`,
+			},
+			exit: 1,
+		},
 		{
 			name: "when an explicit group is provided using the longhand flag",
 			args: args{
@@ -299,170 +458,504 @@ func Test_run(t *testing.T) {
 			exit: 1,
 		},
 		{
-			name: "when repo case is different to whats in the config",
+			name: "when a group has a description",
 			args: args{
-				args:   []string{"-R", "OctoCat/hello-sunshine", "123"},
-				ghExec: expectCallToGh(t, "OctoCat/hello-sunshine", "123"),
+				args:   []string{"--from", "infra", "123"},
+				ghExec: expectCallToGh(t, "octocat/hello-world", "123"),
 				config: `
 					repositories:
 						octocat/hello-world:
-							default:
-								- octocat
-						octocat/Hello-Sunshine:
-							default:
-								- octodog
-								- octopus
+							infra:
+								description: people who know about our infrastructure
+								reviewers:
+									- octodog
+									- octopus
 				`,
 			},
 			exit: 0,
 		},
-	}
-	for _, tt := range tests {
-		tt := tt
-		t.Run(tt.name, func(t *testing.T) {
-			t.Parallel()
-
-			configDir := writeConfigFileInTempDir(t, dedent(t, tt.args.config))
-
-			stdout := &bytes.Buffer{}
-			stderr := &bytes.Buffer{}
-
-			a := []string{"--config-dir", configDir}
-
-			// quietly explicitly set the repo, since otherwise it'll be inferred from
-			// the actual repo using git which is most likely going to be G-Rath/gh-rr
-			if !slices.Contains(tt.args.args, "--repo") {
-				a = append(a, "--repo", "octocat/hello-world")
-			}
-
-			a = append(a, tt.args.args...)
-
-			var ghExecArgs []string
-
-			got := run(a, stdout, stderr, func(args ...string) (stdout, stderr string) {
-				t.Helper()
-
-				ghExecArgs = args
-
-				return tt.args.ghExec(args...)
-			})
-
-			if got != tt.exit {
-				t.Errorf("run() = %v, want %v", got, tt.exit)
-			}
-
-			snaps.MatchSnapshot(t, normalizeStdStream(t, stdout))
-			snaps.MatchSnapshot(t, normalizeStdStream(t, stderr))
-			snaps.MatchJSON(t, ghExecArgs)
-		})
-	}
-}
-
-func Test_run_GlobalGroups(t *testing.T) {
-	t.Parallel()
-
-	type args struct {
-		args   []string
-		config string
-		ghExec ghExecutor
-	}
-	tests := []struct {
-		name string
-		args args
-		exit int
-	}{
 		{
-			name: "when using the longhand flag",
+			name: "when reviewers have metadata",
 			args: args{
-				args:   []string{"--global", "--from", "security"},
-				ghExec: expectCallToGh(t, "octocat/hello-world", "1"),
+				args:   []string{"123"},
+				ghExec: expectCallToGh(t, "octocat/hello-world", "123"),
 				config: `
 					repositories:
-						'*':
-							security:
-								- octodog
 						octocat/hello-world:
-							default:
-								- octodog
-								- octopus
+							- handle: priyak
+								name: Priya K
+								chat: '@priya'
+							- handle: octodog
+								name: Octo Dog
+							- handle: octopus
+							- octocat
 				`,
 			},
 			exit: 0,
 		},
 		{
-			name: "when using the shorthand flag",
+			name: "when reviewers have timezones",
 			args: args{
-				args:   []string{"-g", "--from", "security"},
-				ghExec: expectCallToGh(t, "octocat/hello-world", "1"),
+				args:   []string{"123"},
+				ghExec: expectCallToGh(t, "octocat/hello-world", "123"),
 				config: `
 					repositories:
-						'*':
-							security:
-								- octodog
 						octocat/hello-world:
-							default:
-								- octodog
-								- octopus
+							- handle: priyak
+								name: Priya K
+								chat: '@priya'
+								tz: Pacific/Auckland
+							- handle: octodog
+								tz: America/New_York
+							- octocat
 				`,
 			},
 			exit: 0,
 		},
 		{
-			name: "when combining shorthand flags",
+			name: "when reviewers have an unknown timezone",
 			args: args{
-				args:   []string{"-gf", "security"},
-				ghExec: expectCallToGh(t, "octocat/hello-world", "1"),
+				args:   []string{"123"},
+				ghExec: expectNoCallToGh(t),
 				config: `
 					repositories:
-						'*':
-							security:
-								- octodog
 						octocat/hello-world:
-							default:
-								- octodog
-								- octopus
+							- handle: octodog
+								tz: Middle-earth/Shire
 				`,
 			},
-			exit: 0,
+			exit: 1,
 		},
 		{
-			name: "when the repo has a group with the same name",
+			name: "when reviewers have metadata but no handle",
 			args: args{
-				args:   []string{"-gf", "security"},
-				ghExec: expectCallToGh(t, "octocat/hello-world", "1"),
+				args:   []string{"123"},
+				ghExec: expectNoCallToGh(t),
 				config: `
 					repositories:
-						'*':
-							security:
-								- octodog
 						octocat/hello-world:
-							security:
-								- octopus
+							- handle: octodog
+							- name: Octo Pus
+								chat: '@octopus'
 				`,
 			},
-			exit: 0,
+			exit: 1,
 		},
 		{
-			name: "when the repo has a group with the same name but the global one does not exist",
+			name: "when on_noop is not a known behavior",
 			args: args{
-				args:   []string{"-gf", "security"},
+				args:   []string{"123"},
 				ghExec: expectNoCallToGh(t),
 				config: `
+					settings:
+						on_noop: ignore
 					repositories:
-						'*':
-							platforms:
-								- octodog
 						octocat/hello-world:
-							security:
-								- octopus
+							- octodog
 				`,
 			},
 			exit: 1,
 		},
 		{
-			name: "when a specific repository is given that is not in the config",
+			name: "when listing groups",
 			args: args{
-				args:   []string{"-gf", "security", "-R", "octocat/hello-sunshine"},
+				args:   []string{"groups"},
+				ghExec: expectNoCallToGh(t),
+				config: `
+					repositories:
+						'*':
+							security:
+								description: our security champions
+								reviewers: [octocat]
+						octocat/hello-world:
+							infra:
+								description: people who know about our infrastructure
+								reviewers:
+									- octodog
+									- octopus
+							default:
+								- octocat
+						octocat/hello-sunshine:
+							- octodog
+				`,
+			},
+			exit: 0,
+		},
+		{
+			name: "when listing groups and there are no global groups",
+			args: args{
+				args:   []string{"groups"},
+				ghExec: expectNoCallToGh(t),
+				config: `
+					repositories:
+						octocat/hello-world:
+							- octocat
+				`,
+			},
+			exit: 0,
+		},
+		{
+			name: "when listing groups and only global groups are configured",
+			args: args{
+				args:   []string{"groups"},
+				ghExec: expectNoCallToGh(t),
+				config: `
+					repositories:
+						'*':
+							security: [octocat]
+				`,
+			},
+			exit: 0,
+		},
+		{
+			name: "when listing groups and the repository is not configured",
+			args: args{
+				args:   []string{"groups"},
+				ghExec: expectNoCallToGh(t),
+				config: `
+					repositories:
+						octocat/hello-sunshine:
+							- octodog
+				`,
+			},
+			exit: 1,
+		},
+		{
+			name: "when targeting a branch named groups",
+			args: args{
+				args:   []string{"--", "groups"},
+				ghExec: expectCallToGh(t, "octocat/hello-world", "groups"),
+				config: `
+					repositories:
+						octocat/hello-world:
+							- octocat
+				`,
+			},
+			exit: 0,
+		},
+		{
+			name: "when a profile is selected",
+			args: args{
+				args:   []string{"--profile", "work", "123"},
+				ghExec: expectCallToGh(t, "octocat/hello-world", "123"),
+				config: `
+					repositories:
+						octocat/hello-world:
+							- octocat
+					profiles:
+						work:
+							repositories:
+								octocat/hello-world:
+									- octodog
+									- octopus
+						oss:
+							repositories:
+								octocat/hello-world:
+									- octocat
+				`,
+			},
+			exit: 0,
+		},
+		{
+			name: "when profiles exist but none is selected",
+			args: args{
+				args:   []string{"123"},
+				ghExec: expectCallToGh(t, "octocat/hello-world", "123"),
+				config: `
+					repositories:
+						octocat/hello-world:
+							- octocat
+					profiles:
+						work:
+							repositories:
+								octocat/hello-world:
+									- octodog
+									- octopus
+				`,
+			},
+			exit: 0,
+		},
+		{
+			name: "when the selected profile does not exist",
+			args: args{
+				args:   []string{"--profile", "oss", "123"},
+				ghExec: expectNoCallToGh(t),
+				config: `
+					profiles:
+						work:
+							repositories:
+								octocat/hello-world:
+									- octodog
+									- octopus
+				`,
+			},
+			exit: 1,
+		},
+		{
+			name: "when a profile matches the owner of the repository",
+			args: args{
+				args:   []string{"123"},
+				ghExec: expectCallToGh(t, "octocat/hello-world", "123"),
+				config: `
+					repositories:
+						octocat/hello-world:
+							- octocat
+					profiles:
+						oss:
+							match:
+								owners: [g-rath]
+							repositories:
+								octocat/hello-world:
+									- octopus
+						work:
+							match:
+								owners: [OctoCat]
+							repositories:
+								octocat/hello-world:
+									- octodog
+				`,
+			},
+			exit: 0,
+		},
+		{
+			name: "when a profile matches the owner of the repository using a pattern",
+			args: args{
+				args:   []string{"123"},
+				ghExec: expectCallToGh(t, "octocat/hello-world", "123"),
+				config: `
+					repositories:
+						octocat/hello-world:
+							- octocat
+					profiles:
+						work:
+							match:
+								owners: [octo*]
+							repositories:
+								octocat/hello-world:
+									- octodog
+				`,
+			},
+			exit: 0,
+		},
+		{
+			name: "when a profile matches the owner but not the host of the repository",
+			args: args{
+				args:   []string{"123"},
+				ghExec: expectCallToGh(t, "octocat/hello-world", "123"),
+				config: `
+					repositories:
+						octocat/hello-world:
+							- octocat
+					profiles:
+						work:
+							match:
+								hosts: [github.example.com]
+								owners: [octocat]
+							repositories:
+								octocat/hello-world:
+									- octodog
+				`,
+			},
+			exit: 0,
+		},
+		{
+			name: "when a profile is explicitly selected over one that matches",
+			args: args{
+				args:   []string{"--profile", "oss", "123"},
+				ghExec: expectCallToGh(t, "octocat/hello-world", "123"),
+				config: `
+					profiles:
+						oss:
+							repositories:
+								octocat/hello-world:
+									- octopus
+						work:
+							match:
+								owners: [octocat]
+							repositories:
+								octocat/hello-world:
+									- octodog
+				`,
+			},
+			exit: 0,
+		},
+		{
+			name: "when the selected profile has no repositories",
+			args: args{
+				args:   []string{"--profile", "work", "123"},
+				ghExec: expectNoCallToGh(t),
+				config: `
+					profiles:
+						work: {}
+				`,
+			},
+			exit: 1,
+		},
+		{
+			name: "when repo case is different to whats in the config",
+			args: args{
+				args:   []string{"-R", "OctoCat/hello-sunshine", "123"},
+				ghExec: expectCallToGh(t, "OctoCat/hello-sunshine", "123"),
+				config: `
+					repositories:
+						octocat/hello-world:
+							default:
+								- octocat
+						octocat/Hello-Sunshine:
+							default:
+								- octodog
+								- octopus
+				`,
+			},
+			exit: 0,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			configDir := writeConfigFileInTempDir(t, dedent(t, tt.args.config))
+
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+
+			a := []string{"--config-dir", configDir}
+
+			// quietly explicitly set the repo, since otherwise it'll be inferred from
+			// the actual repo using git which is most likely going to be G-Rath/gh-rr
+			if !slices.Contains(tt.args.args, "--repo") {
+				a = append(a, "--repo", "octocat/hello-world")
+			}
+
+			a = append(a, tt.args.args...)
+
+			var ghExecArgs []string
+
+			got := run(a, stdout, stderr, func(args ...string) (stdout, stderr string) {
+				t.Helper()
+
+				ghExecArgs = args
+
+				return tt.args.ghExec(args...)
+			})
+
+			if got != tt.exit {
+				t.Errorf("run() = %v, want %v", got, tt.exit)
+			}
+
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stdout))
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stderr))
+			snaps.MatchJSON(t, ghExecArgs)
+		})
+	}
+}
+
+func Test_run_GlobalGroups(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		args   []string
+		config string
+		ghExec ghExecutor
+	}
+	tests := []struct {
+		name string
+		args args
+		exit int
+	}{
+		{
+			name: "when using the longhand flag",
+			args: args{
+				args:   []string{"--global", "--from", "security"},
+				ghExec: expectCallToGh(t, "octocat/hello-world", "1"),
+				config: `
+					repositories:
+						'*':
+							security:
+								- octodog
+						octocat/hello-world:
+							default:
+								- octodog
+								- octopus
+				`,
+			},
+			exit: 0,
+		},
+		{
+			name: "when using the shorthand flag",
+			args: args{
+				args:   []string{"-g", "--from", "security"},
+				ghExec: expectCallToGh(t, "octocat/hello-world", "1"),
+				config: `
+					repositories:
+						'*':
+							security:
+								- octodog
+						octocat/hello-world:
+							default:
+								- octodog
+								- octopus
+				`,
+			},
+			exit: 0,
+		},
+		{
+			name: "when combining shorthand flags",
+			args: args{
+				args:   []string{"-gf", "security"},
+				ghExec: expectCallToGh(t, "octocat/hello-world", "1"),
+				config: `
+					repositories:
+						'*':
+							security:
+								- octodog
+						octocat/hello-world:
+							default:
+								- octodog
+								- octopus
+				`,
+			},
+			exit: 0,
+		},
+		{
+			name: "when the repo has a group with the same name",
+			args: args{
+				args:   []string{"-gf", "security"},
+				ghExec: expectCallToGh(t, "octocat/hello-world", "1"),
+				config: `
+					repositories:
+						'*':
+							security:
+								- octodog
+						octocat/hello-world:
+							security:
+								- octopus
+				`,
+			},
+			exit: 0,
+		},
+		{
+			name: "when the repo has a group with the same name but the global one does not exist",
+			args: args{
+				args:   []string{"-gf", "security"},
+				ghExec: expectNoCallToGh(t),
+				config: `
+					repositories:
+						'*':
+							platforms:
+								- octodog
+						octocat/hello-world:
+							security:
+								- octopus
+				`,
+			},
+			exit: 1,
+		},
+		{
+			name: "when a specific repository is given that is not in the config",
+			args: args{
+				args:   []string{"-gf", "security", "-R", "octocat/hello-sunshine"},
 				ghExec: expectCallToGh(t, "octocat/hello-sunshine", "1"),
 				config: `
 					repositories:
@@ -566,3 +1059,395 @@ func Test_run_WithNoHomeVar(t *testing.T) {
 
 	t.Errorf("function did not panic when home directory could not be found")
 }
+
+func Test_run_WithProfileEnvVar(t *testing.T) {
+	t.Setenv("GH_RR_PROFILE", "work")
+
+	configDir := writeConfigFileInTempDir(t, dedent(t, `
+		repositories:
+			octocat/hello-world:
+				- octocat
+		profiles:
+			work:
+				repositories:
+					octocat/hello-world:
+						- octodog
+	`))
+
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+
+	got := run(
+		[]string{"--config-dir", configDir, "--repo", "octocat/hello-world", "--dry-run"},
+		stdout,
+		stderr,
+		expectNoCallToGh(t),
+	)
+
+	if got != 0 {
+		t.Errorf("run() = %v, want %v", got, 0)
+	}
+
+	snaps.MatchSnapshot(t, normalizeStdStream(t, stdout))
+	snaps.MatchSnapshot(t, normalizeStdStream(t, stderr))
+}
+
+// writeFakeGh creates a fake gh executable which records the arguments it was
+// called with in a "calls" file that lives alongside it
+func writeFakeGh(t *testing.T) string {
+	t.Helper()
+
+	binDir := writeConfigFileInTempDir(t, "")
+
+	script := "#!/bin/sh\necho \"$@\" >> \"$(dirname \"$0\")/calls\"\necho https://github.com/octocat/hello-world/pull/1\n"
+
+	err := os.WriteFile(filepath.Join(binDir, "gh"), []byte(script), 0700) //nolint:gosec // it needs to be executable
+	if err != nil {
+		t.Fatalf("could not create fake gh: %v", err)
+	}
+
+	return filepath.Join(binDir, "gh")
+}
+
+func readFakeGhCalls(t *testing.T, ghPath string) string {
+	t.Helper()
+
+	calls, _ := os.ReadFile(filepath.Join(filepath.Dir(ghPath), "calls"))
+
+	return string(calls)
+}
+
+func Test_run_WithGhPath(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake gh binary is a shell script")
+	}
+
+	ghPath := writeFakeGh(t)
+
+	configDir := writeConfigFileInTempDir(t, dedent(t, `
+		repositories:
+			octocat/hello-world:
+				- octocat
+	`))
+
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+
+	got := run(
+		[]string{"--config-dir", configDir, "--repo", "octocat/hello-world", "--gh-path", ghPath, "1"},
+		stdout,
+		stderr,
+		expectNoCallToGh(t),
+	)
+
+	if got != 0 {
+		t.Errorf("run() = %v, want %v", got, 0)
+	}
+
+	snaps.MatchSnapshot(t, normalizeStdStream(t, stdout))
+	snaps.MatchSnapshot(t, normalizeStdStream(t, stderr))
+	snaps.MatchSnapshot(t, readFakeGhCalls(t, ghPath))
+}
+
+func Test_run_WithGhPathEnvVar(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake gh binary is a shell script")
+	}
+
+	ghPath := writeFakeGh(t)
+
+	t.Setenv("GH_RR_GH_PATH", ghPath)
+
+	configDir := writeConfigFileInTempDir(t, dedent(t, `
+		repositories:
+			octocat/hello-world:
+				- octocat
+	`))
+
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+
+	got := run(
+		[]string{"--config-dir", configDir, "--repo", "octocat/hello-world", "1"},
+		stdout,
+		stderr,
+		expectNoCallToGh(t),
+	)
+
+	if got != 0 {
+		t.Errorf("run() = %v, want %v", got, 0)
+	}
+
+	snaps.MatchSnapshot(t, normalizeStdStream(t, stdout))
+	snaps.MatchSnapshot(t, normalizeStdStream(t, stderr))
+	snaps.MatchSnapshot(t, readFakeGhCalls(t, ghPath))
+}
+
+func Test_run_WithMissingGhPath(t *testing.T) {
+	t.Parallel()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("the error message is different on windows")
+	}
+
+	configDir := writeConfigFileInTempDir(t, dedent(t, `
+		repositories:
+			octocat/hello-world:
+				- octocat
+	`))
+
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+
+	got := run(
+		[]string{"--config-dir", configDir, "--repo", "octocat/hello-world", "--gh-path", filepath.Join(configDir, "gh"), "1"},
+		stdout,
+		stderr,
+		expectNoCallToGh(t),
+	)
+
+	if got != 1 {
+		t.Errorf("run() = %v, want %v", got, 1)
+	}
+
+	snaps.MatchSnapshot(t, normalizeStdStream(t, stdout))
+	snaps.MatchSnapshot(t, normalizeStdStream(t, stderr))
+}
+
+// writeFakeTokenGh creates a fake gh executable which records the token it was
+// called with in a "calls" file that lives alongside it
+func writeFakeTokenGh(t *testing.T) string {
+	t.Helper()
+
+	binDir := writeConfigFileInTempDir(t, "")
+
+	script := "#!/bin/sh\necho \"$GH_TOKEN $GH_ENTERPRISE_TOKEN $@\" >> \"$(dirname \"$0\")/calls\"\necho https://github.com/octocat/hello-world/pull/1\n"
+
+	err := os.WriteFile(filepath.Join(binDir, "gh"), []byte(script), 0700) //nolint:gosec // it needs to be executable
+	if err != nil {
+		t.Fatalf("could not create fake gh: %v", err)
+	}
+
+	return filepath.Join(binDir, "gh")
+}
+
+func Test_run_WithToken(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake gh binary is a shell script")
+	}
+
+	// ensure the token set by gh-rr is reset once the test is done
+	t.Setenv("GH_TOKEN", "")
+	t.Setenv("GH_ENTERPRISE_TOKEN", "")
+
+	ghPath := writeFakeTokenGh(t)
+
+	configDir := writeConfigFileInTempDir(t, dedent(t, `
+		repositories:
+			octocat/hello-world:
+				- octocat
+	`))
+
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+
+	got := run(
+		[]string{"--config-dir", configDir, "--repo", "octocat/hello-world", "--gh-path", ghPath, "--token", "ghp_flag", "1"},
+		stdout,
+		stderr,
+		expectNoCallToGh(t),
+	)
+
+	if got != 0 {
+		t.Errorf("run() = %v, want %v", got, 0)
+	}
+
+	snaps.MatchSnapshot(t, normalizeStdStream(t, stdout))
+	snaps.MatchSnapshot(t, normalizeStdStream(t, stderr))
+	snaps.MatchSnapshot(t, readFakeGhCalls(t, ghPath))
+}
+
+func Test_run_WithTokenEnvVar(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake gh binary is a shell script")
+	}
+
+	// ensure the token set by gh-rr is reset once the test is done
+	t.Setenv("GH_TOKEN", "")
+	t.Setenv("GH_ENTERPRISE_TOKEN", "")
+	t.Setenv("GH_RR_TOKEN", "ghp_env")
+
+	ghPath := writeFakeTokenGh(t)
+
+	configDir := writeConfigFileInTempDir(t, dedent(t, `
+		repositories:
+			octocat/hello-world:
+				- octocat
+	`))
+
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+
+	got := run(
+		[]string{"--config-dir", configDir, "--repo", "octocat/hello-world", "--gh-path", ghPath, "1"},
+		stdout,
+		stderr,
+		expectNoCallToGh(t),
+	)
+
+	if got != 0 {
+		t.Errorf("run() = %v, want %v", got, 0)
+	}
+
+	snaps.MatchSnapshot(t, normalizeStdStream(t, stdout))
+	snaps.MatchSnapshot(t, normalizeStdStream(t, stderr))
+	snaps.MatchSnapshot(t, readFakeGhCalls(t, ghPath))
+}
+
+// these tests cannot be run in parallel as they change the environment
+func Test_run_WithConfigEnvVar(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		env  string
+		exit int
+	}{
+		{
+			name: "when GH_RR_CONFIG is set",
+			args: []string{"--repo", "octocat/hello-world", "123"},
+			env:  "rr-work.yml",
+			exit: 0,
+		},
+		{
+			name: "when GH_RR_CONFIG is set along with --config-dir",
+			args: []string{"--config-dir", "{{home}}", "--repo", "octocat/hello-world", "123"},
+			env:  "rr-work.yml",
+			exit: 0,
+		},
+		{
+			name: "when GH_RR_CONFIG is set to a file that does not exist",
+			args: []string{"--repo", "octocat/hello-world", "123"},
+			env:  "rr-missing.yml",
+			exit: 1,
+		},
+		{
+			name: "when GH_RR_CONFIG is empty",
+			args: []string{"--repo", "octocat/hello-world", "123"},
+			env:  "",
+			exit: 0,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			home := writeConfigFileInTempDir(t, "repositories: {octocat/hello-world: [octocat]}")
+
+			err := os.WriteFile(filepath.Join(home, "rr-work.yml"), []byte("repositories: {octocat/hello-world: [octopus]}"), 0600)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			t.Setenv("HOME", home)
+			t.Setenv("XDG_CONFIG_HOME", home)
+			t.Setenv("GH_RR_CONFIG", "")
+
+			if tt.env != "" {
+				t.Setenv("GH_RR_CONFIG", filepath.Join(home, tt.env))
+			}
+
+			args := slices.Clone(tt.args)
+
+			for i, arg := range args {
+				if arg == "{{home}}" {
+					args[i] = home
+				}
+			}
+
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+
+			got := run(args, stdout, stderr, expectCallToGh(t, "octocat/hello-world", "123"))
+
+			if got != tt.exit {
+				t.Errorf("run() = %v, want %v", got, tt.exit)
+			}
+
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stdout))
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stderr))
+		})
+	}
+}
+
+func Test_run_WithJSONConfig(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		args []string
+		yaml string
+		json string
+		exit int
+	}{
+		{
+			name: "when the config is json",
+			args: []string{"--repo", "octocat/hello-world", "123"},
+			json: `{"repositories": {"octocat/hello-world": {"default": ["octocat", {"handle": "octodog", "name": "Octo Dog"}]}}}`,
+			exit: 0,
+		},
+		{
+			name: "when there is both a yaml and a json config",
+			args: []string{"--repo", "octocat/hello-world", "123"},
+			yaml: "repositories: {octocat/hello-world: [octopus]}",
+			json: `{"repositories": {"octocat/hello-world": ["octocat"]}}`,
+			exit: 0,
+		},
+		{
+			name: "when the json config is using yaml syntax",
+			args: []string{"--repo", "octocat/hello-world", "123"},
+			json: "repositories: {octocat/hello-world: [octopus]}",
+			exit: 1,
+		},
+		{
+			name: "when the json config is invalid",
+			args: []string{"--repo", "octocat/hello-world", "123"},
+			json: `{"repositories": {"octocat/hello-world": [{"name": "Octo Dog"}]}}`,
+			exit: 1,
+		},
+		{
+			name: "when editing a json config",
+			args: []string{"offboard", "octocat"},
+			json: `{"repositories": {"octocat/hello-world": ["octocat"]}}`,
+			exit: 1,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			configDir := writeConfigFileInTempDir(t, tt.yaml)
+
+			if err := os.WriteFile(filepath.Join(configDir, "gh-rr.json"), []byte(tt.json), 0600); err != nil {
+				t.Fatal(err)
+			}
+
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+
+			got := run(
+				append([]string{"--config-dir", configDir}, tt.args...),
+				stdout,
+				stderr,
+				expectCallToGh(t, "octocat/hello-world", "123"),
+			)
+
+			if got != tt.exit {
+				t.Errorf("run() = %v, want %v", got, tt.exit)
+			}
+
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stdout))
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stderr))
+		})
+	}
+}
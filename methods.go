@@ -0,0 +1,155 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// requestMethod is how the members of a group are attached to pull requests
+type requestMethod string
+
+const (
+	methodReviewer requestMethod = "reviewer"
+	methodAssignee requestMethod = "assignee"
+	methodMention  requestMethod = "mention-comment"
+)
+
+func (m *requestMethod) UnmarshalYAML(value *yaml.Node) error {
+	var method string
+
+	if err := value.Decode(&method); err != nil {
+		return err
+	}
+
+	switch requestMethod(method) {
+	case methodReviewer, methodAssignee, methodMention:
+		*m = requestMethod(method)
+	default:
+		return fmt.Errorf("line %d: method must be one of reviewer, assignee, or mention-comment, not `%s`", value.Line, method)
+	}
+
+	return nil
+}
+
+// withMethod returns the reviewers marked as being attached using the method
+func withMethod(reviewers []reviewer, method requestMethod) []reviewer {
+	marked := make([]reviewer, 0, len(reviewers))
+
+	for _, r := range reviewers {
+		r.method = method
+		marked = append(marked, r)
+	}
+
+	return marked
+}
+
+// describeMethod returns a note about how the reviewer was attached, if it was
+// not by requesting a review from them
+func describeMethod(r reviewer) string {
+	switch r.method {
+	case methodAssignee:
+		return "assigned"
+	case methodMention:
+		return "mentioned"
+	case methodReviewer:
+	}
+
+	return ""
+}
+
+// isMentioned checks if the reviewer is attached by mentioning them in a comment
+func isMentioned(r reviewer) bool {
+	return r.method == methodMention
+}
+
+// buildMentionComment returns the comment used to mention the reviewers that
+// are attached by being mentioned, if there are any
+func buildMentionComment(reviewers []reviewer) string {
+	var mentions []string
+
+	for _, r := range reviewers {
+		if isMentioned(r) {
+			mentions = append(mentions, "@"+r.Handle)
+		}
+	}
+
+	if len(mentions) == 0 {
+		return ""
+	}
+
+	return strings.Join(mentions, ", ") + " your attention has been requested on this pull request"
+}
+
+// attachReviewers requests reviews from, assigns, and mentions the reviewers
+// on the pull request based on the method of their group, returning its url;
+// if gh fails part way through, the error notes who had already been attached
+func attachReviewers(ghExec ghExecutor, repo, target string, reviewers []reviewer, urgentLabel string) (string, error) {
+	var url string
+	var attached []string
+
+	onlyMentions := len(reviewers) > 0 && !slices.ContainsFunc(reviewers, func(r reviewer) bool {
+		return !isMentioned(r)
+	})
+
+	// the pull request does not need editing if everyone is only being mentioned
+	if !onlyMentions || urgentLabel != "" {
+		editable := slices.DeleteFunc(slices.Clone(reviewers), isMentioned)
+
+		for i, chunk := range chunkReviewers(editable) {
+			args := buildAddReviewersArgs(repo, target, chunk)
+
+			// the label only needs to be added once
+			if i == 0 {
+				args = buildUrgentArgs(args, urgentLabel)
+			}
+
+			out, errMsg := ghExec(args...)
+
+			if errMsg != "" {
+				return "", newAttachError(errMsg, attached)
+			}
+
+			for _, r := range chunk {
+				attached = append(attached, r.Handle)
+			}
+
+			if url == "" {
+				url = out
+			}
+		}
+	}
+
+	comment := buildMentionComment(reviewers)
+
+	if comment == "" {
+		return url, nil
+	}
+
+	out, errMsg := ghExec("pr", "comment", target, "--repo", repo, "--body", comment)
+
+	if errMsg != "" {
+		return "", newAttachError(errMsg, attached)
+	}
+
+	if url == "" {
+		url, _, _ = strings.Cut(out, "#")
+	}
+
+	return url, nil
+}
+
+// newAttachError returns an error for gh failing to attach reviewers, noting
+// those that had already been attached by earlier calls to gh, if any
+func newAttachError(errMsg string, attached []string) error {
+	errMsg = strings.TrimSpace(errMsg)
+
+	if len(attached) == 0 {
+		return errors.New(errMsg)
+	}
+
+	return fmt.Errorf("%s (though %s had already been attached)", errMsg, strings.Join(attached, ", "))
+}
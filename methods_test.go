@@ -0,0 +1,160 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+// fakeMethodsGh acts as gh for editing and commenting on pull requests
+func fakeMethodsGh(t *testing.T, calls *[][]string) ghExecutor {
+	t.Helper()
+
+	return func(args ...string) (string, string) {
+		t.Helper()
+
+		*calls = append(*calls, args)
+
+		switch {
+		case len(args) > 2 && args[0] == "pr" && args[1] == "edit":
+			return fmt.Sprintf("https://github.com/octocat/hello-world/pull/%s", args[2]), ""
+		case len(args) > 2 && args[0] == "pr" && args[1] == "comment":
+			if args[2] == "13" {
+				return "", "GraphQL: Could not resolve to a PullRequest with the number of 13."
+			}
+
+			return fmt.Sprintf("https://github.com/octocat/hello-world/pull/%s#issuecomment-1", args[2]), ""
+		}
+
+		t.Errorf("unexpected call to gh: %v", args)
+
+		return "", ""
+	}
+}
+
+func Test_run_WithRequestMethods(t *testing.T) {
+	t.Parallel()
+
+	config := `
+		repositories:
+			octocat/hello-world:
+				default: [octocat, octopus]
+				qa:
+					reviewers: [octokitten]
+					method: assignee
+				leads:
+					reviewers: [octodog]
+					method: mention-comment
+				explicit:
+					reviewers: [octobear]
+					method: reviewer
+	`
+
+	tests := []struct {
+		name   string
+		args   []string
+		config string
+		exit   int
+	}{
+		{
+			name:   "when the group is assigned",
+			args:   []string{"--from", "qa", "123"},
+			config: config,
+			exit:   0,
+		},
+		{
+			name:   "when the group is mentioned",
+			args:   []string{"--from", "leads", "123"},
+			config: config,
+			exit:   0,
+		},
+		{
+			name:   "when the group is mentioned on an urgent request",
+			args:   []string{"--from", "leads", "--urgent", "123"},
+			config: config,
+			exit:   0,
+		},
+		{
+			name:   "when the group explicitly requests reviews",
+			args:   []string{"--from", "explicit", "123"},
+			config: config,
+			exit:   0,
+		},
+		{
+			name: "when the always group uses a different method",
+			args: []string{"123"},
+			config: `
+				repositories:
+					octocat/hello-world:
+						default: [octocat, octopus]
+						always:
+							reviewers: [octokitten, octodog]
+							method: assignee
+			`,
+			exit: 0,
+		},
+		{
+			name: "when mixing every method",
+			args: []string{"--dry-run", "123"},
+			config: `
+				repositories:
+					octocat/hello-world:
+						default:
+							reviewers: [octocat]
+							method: mention-comment
+						always:
+							reviewers: [octokitten]
+							method: assignee
+			`,
+			exit: 0,
+		},
+		{
+			name:   "when the mention cannot be commented",
+			args:   []string{"--from", "leads", "13"},
+			config: config,
+			exit:   1,
+		},
+		{
+			name: "when the method is not supported",
+			args: []string{"123"},
+			config: `
+				repositories:
+					octocat/hello-world:
+						default:
+							reviewers: [octocat]
+							method: carrier-pigeon
+			`,
+			exit: 1,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			configDir := writeConfigFileInTempDir(t, dedent(t, tt.config))
+
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+
+			var calls [][]string
+
+			got := run(
+				append([]string{"--config-dir", configDir, "--repo", "octocat/hello-world"}, tt.args...),
+				stdout,
+				stderr,
+				fakeMethodsGh(t, &calls),
+			)
+
+			if got != tt.exit {
+				t.Errorf("run() = %v, want %v", got, tt.exit)
+			}
+
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stdout))
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stderr))
+			snaps.MatchJSON(t, calls)
+		})
+	}
+}
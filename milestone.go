@@ -0,0 +1,85 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"slices"
+)
+
+type milestoneSweepOptions struct {
+	repo      string
+	milestone string
+
+	// request requests reviews on the given pull request, exactly as if gh rr
+	// had been run with it, so that each is routed based on the config
+	request func(number string) int
+}
+
+// sweepFlags are the flags that should not be forwarded when requesting reviews
+// on each pull request in a milestone or search, either because they would start
+// another sweep or search or because they have already been applied to how gh
+// is called
+var sweepFlags = []string{"--milestone-sweep", "--search", "--repo", "--record", "--replay", "--gh-path"}
+
+// sweepArgs returns the arguments to request reviews on each pull request in a
+// milestone with, based on the flags that the sweep was started with
+func sweepArgs(flags []string, repo string) []string {
+	args := []string{"--repo", repo}
+
+	for i := 0; i < len(flags); i++ {
+		// all the flags being skipped take a value, which is the next argument
+		if slices.Contains(sweepFlags, flags[i]) {
+			i++
+
+			continue
+		}
+
+		args = append(args, flags[i])
+	}
+
+	return args
+}
+
+// sweepMilestone requests reviews on every open pull request in the milestone,
+// with each being routed independently so that the likes of author rules apply
+func sweepMilestone(stdout, stderr io.Writer, ghExec ghExecutor, opts milestoneSweepOptions) int {
+	prs, err := searchPullRequests(ghExec, opts.repo, fmt.Sprintf("milestone:%q", opts.milestone))
+
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+
+		return 1
+	}
+
+	if len(prs) == 0 {
+		fmt.Fprintf(stdout, "there are no open pull requests in the %s milestone\n", opts.milestone)
+
+		return 0
+	}
+
+	var failed []string
+
+	for i, pr := range prs {
+		if i > 0 {
+			fmt.Fprintln(stdout)
+		}
+
+		fmt.Fprintf(stdout, "%s:\n", pr.URL)
+
+		if opts.request(fmt.Sprint(pr.Number)) != 0 {
+			failed = append(failed, pr.URL)
+		}
+	}
+
+	if len(failed) > 0 {
+		fmt.Fprintf(stderr, "\ncould not request reviews on %d of the %d pull requests in the %s milestone:\n", len(failed), len(prs), opts.milestone)
+
+		for _, url := range failed {
+			fmt.Fprintf(stderr, "  - %s\n", url)
+		}
+
+		return 1
+	}
+
+	return 0
+}
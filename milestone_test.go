@@ -0,0 +1,184 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+// fakeMilestoneGh acts as gh for a repository where searching returns the given
+// pull requests, which are authored by a different person based on their number,
+// and requesting reviews on pull request #13 always fails
+func fakeMilestoneGh(t *testing.T, prs string, calls *[][]string) ghExecutor {
+	t.Helper()
+
+	authors := map[string]string{
+		"1":  "octocat",
+		"2":  "app/dependabot",
+		"3":  "app/renovate",
+		"13": "octokitten",
+	}
+
+	return func(args ...string) (string, string) {
+		t.Helper()
+
+		*calls = append(*calls, args)
+
+		switch {
+		case len(args) > 1 && args[0] == "pr" && args[1] == "list":
+			return prs, ""
+		case len(args) > 2 && args[0] == "pr" && args[1] == "view":
+			return fmt.Sprintf(`{"author":{"login":%q}}`, authors[args[2]]), ""
+		case len(args) > 2 && args[0] == "pr" && args[1] == "edit":
+			if args[2] == "13" {
+				return "", "GraphQL: Could not resolve to a PullRequest with the number of 13."
+			}
+
+			return fmt.Sprintf("https://github.com/octocat/hello-world/pull/%s", args[2]), ""
+		}
+
+		t.Errorf("unexpected call to gh: %v", args)
+
+		return "", ""
+	}
+}
+
+func Test_run_MilestoneSweep(t *testing.T) {
+	t.Parallel()
+
+	config := `
+		authors:
+			- match: ['dependabot[bot]']
+				group: deps
+			- match: ['renovate[bot]']
+				skip: true
+		repositories:
+			octocat/hello-world:
+				default: [octocat, octopus]
+				deps: [octodog]
+	`
+
+	prs := `[
+		{"number":1,"url":"https://github.com/octocat/hello-world/pull/1","reviewRequests":[]},
+		{"number":2,"url":"https://github.com/octocat/hello-world/pull/2","reviewRequests":[]},
+		{"number":3,"url":"https://github.com/octocat/hello-world/pull/3","reviewRequests":[]}
+	]`
+
+	tests := []struct {
+		name string
+		args []string
+		prs  string
+		exit int
+	}{
+		{
+			name: "when routing each pull request",
+			args: []string{"--milestone-sweep", "v1.4"},
+			prs:  prs,
+			exit: 0,
+		},
+		{
+			name: "when doing a dry-run",
+			args: []string{"--milestone-sweep", "v1.4", "--dry-run"},
+			prs:  prs,
+			exit: 0,
+		},
+		{
+			name: "when a group is given",
+			args: []string{"--milestone-sweep", "v1.4", "--from", "deps"},
+			prs:  prs,
+			exit: 0,
+		},
+		{
+			name: "when requesting reviews on some pull requests fails",
+			args: []string{"--milestone-sweep", "v1.4"},
+			prs:  `[{"number":13,"url":"https://github.com/octocat/hello-world/pull/13","reviewRequests":[]},` + prs[1:],
+			exit: 1,
+		},
+		{
+			name: "when there are no pull requests in the milestone",
+			args: []string{"--milestone-sweep", "v1.4"},
+			prs:  "[]",
+			exit: 0,
+		},
+		{
+			name: "when given a pull request",
+			args: []string{"--milestone-sweep", "v1.4", "1"},
+			prs:  prs,
+			exit: 1,
+		},
+		{
+			name: "when searching",
+			args: []string{"--milestone-sweep", "v1.4", "--search", "label:bug"},
+			prs:  prs,
+			exit: 1,
+		},
+		{
+			name: "when using a command",
+			args: []string{"ready", "--milestone-sweep", "v1.4"},
+			prs:  prs,
+			exit: 1,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			configDir := writeConfigFileInTempDir(t, dedent(t, config))
+
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+
+			var calls [][]string
+
+			got := run(
+				append([]string{"--config-dir", configDir, "--repo", "octocat/hello-world"}, tt.args...),
+				stdout,
+				stderr,
+				fakeMilestoneGh(t, tt.prs, &calls),
+			)
+
+			if got != tt.exit {
+				t.Errorf("run() = %v, want %v", got, tt.exit)
+			}
+
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stdout))
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stderr))
+			snaps.MatchJSON(t, calls)
+		})
+	}
+}
+
+// this test cannot be run in parallel as it changes the working directory
+func Test_run_MilestoneSweep_WithSharedConfig(t *testing.T) {
+	t.Setenv("GH_REPO", "octocat/hello-world")
+
+	chdirToCheckoutWithSharedConfig(t, dedent(t, `
+		repositories:
+			octocat/hello-world: [octocat, octopus]
+	`))
+
+	configDir := writeConfigFileInTempDir(t, "")
+
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+
+	var calls [][]string
+
+	got := run(
+		[]string{"--config-dir", configDir, "--milestone-sweep", "v1.4"},
+		stdout,
+		stderr,
+		fakeMilestoneGh(t, `[{"number":1,"url":"https://github.com/octocat/hello-world/pull/1","reviewRequests":[]}]`, &calls),
+	)
+
+	if got != 0 {
+		t.Errorf("run() = %v, want %v", got, 0)
+	}
+
+	snaps.MatchSnapshot(t, normalizeStdStream(t, stdout))
+	snaps.MatchSnapshot(t, normalizeStdStream(t, stderr))
+	snaps.MatchJSON(t, calls)
+}
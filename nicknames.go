@@ -0,0 +1,42 @@
+package main
+
+import (
+	"strings"
+)
+
+// resolveReviewerAliases returns the repositories with any reviewers whose
+// handle is an alias being replaced by the handle that it is an alias for
+func resolveReviewerAliases(repos repositories, aliases map[string]string) repositories {
+	if len(aliases) == 0 {
+		return repos
+	}
+
+	lookup := make(map[string]string, len(aliases))
+
+	for alias, handle := range aliases {
+		lookup[strings.ToLower(alias)] = handle
+	}
+
+	resolved := make(repositories, len(repos))
+
+	for repo, groups := range repos {
+		resolved[repo] = make(map[string]group, len(groups))
+
+		for name, g := range groups {
+			reviewers := make([]reviewer, 0, len(g.Reviewers))
+
+			for _, r := range g.Reviewers {
+				if handle, ok := lookup[strings.ToLower(r.Handle)]; ok {
+					r.Handle = handle
+				}
+
+				reviewers = append(reviewers, r)
+			}
+
+			g.Reviewers = reviewers
+			resolved[repo][name] = g
+		}
+	}
+
+	return resolved
+}
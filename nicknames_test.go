@@ -0,0 +1,110 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+func Test_run_WithReviewerAliases(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		args   []string
+		config string
+		exit   int
+	}{
+		{
+			name: "when reviewers use aliases",
+			args: []string{"123"},
+			config: `
+				aliases:
+					sam: samuel-the-3rd-dev
+					Pri: priyak
+				repositories:
+					octocat/hello-world:
+						- Sam
+						- handle: pri
+						  name: Priya K
+						- octocat
+			`,
+			exit: 0,
+		},
+		{
+			name: "when global groups use aliases",
+			args: []string{"-g", "--from", "security", "123"},
+			config: `
+				aliases:
+					sam: samuel-the-3rd-dev
+				repositories:
+					'*':
+						security: [sam, octokitten]
+			`,
+			exit: 0,
+		},
+		{
+			name: "when owner groups use aliases",
+			args: []string{"123"},
+			config: `
+				aliases:
+					sam: samuel-the-3rd-dev
+				owners:
+					octocat:
+						default: [sam]
+			`,
+			exit: 0,
+		},
+		{
+			name: "when a referenced group uses aliases",
+			args: []string{"--from", "everyone", "123"},
+			config: `
+				aliases:
+					sam: samuel-the-3rd-dev
+				repositories:
+					octocat/hello-world:
+						default: [sam]
+						everyone: ['@group:default', samuel-the-3rd-dev, octocat]
+			`,
+			exit: 0,
+		},
+		{
+			name: "when listing groups",
+			args: []string{"groups"},
+			config: `
+				aliases:
+					sam: samuel-the-3rd-dev
+				repositories:
+					octocat/hello-world:
+						- sam
+			`,
+			exit: 0,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			configDir := writeConfigFileInTempDir(t, dedent(t, tt.config))
+
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+
+			got := run(
+				append([]string{"--config-dir", configDir, "--repo", "octocat/hello-world", "--dry-run"}, tt.args...),
+				stdout,
+				stderr,
+				expectNoCallToGh(t),
+			)
+
+			if got != tt.exit {
+				t.Errorf("run() = %v, want %v", got, tt.exit)
+			}
+
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stdout))
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stderr))
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// the maximum amount of time to wait on a notification being delivered
+const notificationTimeout = 10 * time.Second
+
+type notifications struct {
+	Teams []teamsNotifier `yaml:"teams"`
+}
+
+func (n *notifications) UnmarshalYAML(value *yaml.Node) error {
+	// allow "none" to be used to explicitly disable notifications
+	if value.Kind == yaml.ScalarNode {
+		if value.Value != "none" {
+			return fmt.Errorf("line %d: notifications must be either none or a mapping of notifiers, not `%s`", value.Line, value.Value)
+		}
+
+		*n = notifications{}
+
+		return nil
+	}
+
+	type rawNotifications notifications
+
+	return value.Decode((*rawNotifications)(n))
+}
+
+// notificationsFor returns the notifications that should be sent for requests
+// to the given group, which can override the top-level notifications
+func notificationsFor(conf config, repo, group string) notifications {
+	if g := conf.Repositories[strings.ToLower(repo)][group]; g.Notify != nil {
+		return *g.Notify
+	}
+
+	return conf.Notifications
+}
+
+// teamsNotifier sends a message to a Microsoft Teams channel using an incoming
+// webhook whenever reviews are requested from a matching repository and group
+type teamsNotifier struct {
+	Webhook      string   `yaml:"webhook"`
+	Repositories []string `yaml:"repositories"`
+	Groups       []string `yaml:"groups"`
+}
+
+// reviewRequest describes reviews that have been requested on a pull request
+type reviewRequest struct {
+	Repository string
+	Group      string
+	URL        string
+	Reviewers  []reviewer
+	Urgent     bool
+}
+
+// matches checks if the notifier should be used for the given repository and
+// group, with an empty list of repositories or groups matching everything
+func (n teamsNotifier) matches(repo, group string) bool {
+	if len(n.Repositories) > 0 && !matchesAny(n.Repositories, repo) {
+		return false
+	}
+
+	if len(n.Groups) > 0 && !matchesAny(n.Groups, group) {
+		return false
+	}
+
+	return true
+}
+
+func (n teamsNotifier) notify(req reviewRequest) error {
+	reviewers := make([]string, 0, len(req.Reviewers))
+
+	for _, reviewer := range req.Reviewers {
+		reviewers = append(reviewers, reviewer.String())
+	}
+
+	text := fmt.Sprintf(
+		"Reviews were requested on [%s](%s) from the %s group: %s",
+		req.URL,
+		req.URL,
+		req.Group,
+		strings.Join(reviewers, ", "),
+	)
+
+	if req.Urgent {
+		text = "**Urgent:** " + text
+	}
+
+	body, err := json.Marshal(map[string]string{"text": text})
+
+	if err != nil {
+		return err
+	}
+
+	return postJSON(n.Webhook, body)
+}
+
+// postJSON sends the given body to the url, returning an error if the request
+// could not be made or was not successful
+func postJSON(url string, body []byte) error {
+	ctx, cancel := context.WithTimeout(context.Background(), notificationTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+
+		return fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+
+	return nil
+}
+
+// sendNotifications notifies every configured target that matches the request,
+// reporting but otherwise ignoring any failures since the reviews have already
+// been requested by this point
+func sendNotifications(stderr io.Writer, notifs notifications, req reviewRequest) {
+	for _, notifier := range notifs.Teams {
+		if !notifier.matches(req.Repository, req.Group) {
+			continue
+		}
+
+		if err := notifier.notify(req); err != nil {
+			fmt.Fprintf(stderr, "could not send notification to Microsoft Teams: %v\n", err)
+		}
+	}
+}
@@ -0,0 +1,226 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+// notificationRecorder is a fake webhook that records the bodies of the
+// notifications that it receives, responding with the given status code
+type notificationRecorder struct {
+	mu     sync.Mutex
+	bodies []string
+	status int
+}
+
+func newNotificationServer(t *testing.T, status int) (*httptest.Server, *notificationRecorder) {
+	t.Helper()
+
+	recorder := &notificationRecorder{status: status}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		recorder.mu.Lock()
+		recorder.bodies = append(recorder.bodies, string(body))
+		recorder.mu.Unlock()
+
+		w.WriteHeader(recorder.status)
+		_, _ = w.Write([]byte("webhook says hello"))
+	}))
+
+	t.Cleanup(server.Close)
+
+	return server, recorder
+}
+
+func Test_run_WithTeamsNotifications(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		args   []string
+		config string
+		status int
+		exit   int
+	}{
+		{
+			name: "when the notifier matches everything",
+			args: []string{"123"},
+			config: `
+				notifications:
+					teams:
+						- webhook: {{webhook}}
+				repositories:
+					octocat/hello-world:
+						- handle: octodog
+							name: Octo Dog
+						- octopus
+			`,
+			status: http.StatusOK,
+			exit:   0,
+		},
+		{
+			name: "when the notifier matches the repository and group",
+			args: []string{"--from", "security", "123"},
+			config: `
+				notifications:
+					teams:
+						- webhook: {{webhook}}
+							repositories: [octocat/*]
+							groups: [security]
+				repositories:
+					octocat/hello-world:
+						security: [octodog]
+			`,
+			status: http.StatusOK,
+			exit:   0,
+		},
+		{
+			name: "when the notifier does not match the group",
+			args: []string{"123"},
+			config: `
+				notifications:
+					teams:
+						- webhook: {{webhook}}
+							groups: [security]
+				repositories:
+					octocat/hello-world:
+						- octodog
+			`,
+			status: http.StatusOK,
+			exit:   0,
+		},
+		{
+			name: "when the notifier does not match the repository",
+			args: []string{"123"},
+			config: `
+				notifications:
+					teams:
+						- webhook: {{webhook}}
+							repositories: [octocat/hello-sunshine]
+				repositories:
+					octocat/hello-world:
+						- octodog
+			`,
+			status: http.StatusOK,
+			exit:   0,
+		},
+		{
+			name: "when doing a dry-run",
+			args: []string{"--dry-run", "123"},
+			config: `
+				notifications:
+					teams:
+						- webhook: {{webhook}}
+				repositories:
+					octocat/hello-world:
+						- octodog
+			`,
+			status: http.StatusOK,
+			exit:   0,
+		},
+		{
+			name: "when the group has its own notifications",
+			args: []string{"--from", "security", "123"},
+			config: `
+				notifications:
+					teams:
+						- webhook: {{webhook}}/default
+				repositories:
+					octocat/hello-world:
+						default: [octopus]
+						security:
+							reviewers: [octodog]
+							notify:
+								teams:
+									- webhook: {{webhook}}/security
+			`,
+			status: http.StatusOK,
+			exit:   0,
+		},
+		{
+			name: "when the group has disabled notifications",
+			args: []string{"123"},
+			config: `
+				notifications:
+					teams:
+						- webhook: {{webhook}}
+				repositories:
+					octocat/hello-world:
+						default:
+							reviewers: [octodog]
+							notify: none
+			`,
+			status: http.StatusOK,
+			exit:   0,
+		},
+		{
+			name: "when the group notifications are invalid",
+			args: []string{"123"},
+			config: `
+				repositories:
+					octocat/hello-world:
+						default:
+							reviewers: [octodog]
+							notify: slack
+			`,
+			status: http.StatusOK,
+			exit:   1,
+		},
+		{
+			name: "when the webhook fails",
+			args: []string{"123"},
+			config: `
+				notifications:
+					teams:
+						- webhook: {{webhook}}
+				repositories:
+					octocat/hello-world:
+						- octodog
+			`,
+			status: http.StatusBadRequest,
+			exit:   0,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			server, recorder := newNotificationServer(t, tt.status)
+
+			configDir := writeConfigFileInTempDir(t, strings.ReplaceAll(
+				dedent(t, tt.config),
+				"{{webhook}}",
+				server.URL,
+			))
+
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+
+			got := run(
+				append([]string{"--config-dir", configDir, "--repo", "octocat/hello-world"}, tt.args...),
+				stdout,
+				stderr,
+				expectCallToGh(t, "octocat/hello-world", "123"),
+			)
+
+			if got != tt.exit {
+				t.Errorf("run() = %v, want %v", got, tt.exit)
+			}
+
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stdout))
+			snaps.MatchSnapshot(t, strings.ReplaceAll(normalizeStdStream(t, stderr), server.URL, "<webhook>"))
+			snaps.MatchSnapshot(t, fmt.Sprintf("%q", recorder.bodies))
+		})
+	}
+}
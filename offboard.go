@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// offboard removes the given login from every group in the config file
+func offboard(stdout, stderr io.Writer, file string, args []string, isDryRun bool) int {
+	if len(args) != 1 {
+		fmt.Fprintln(stderr, "please provide the login of the person to remove")
+
+		return 1
+	}
+
+	login := strings.TrimPrefix(args[0], "@")
+
+	doc, original, err := loadConfigDocument(file)
+
+	if err != nil {
+		printConfigEditError(stderr, file, err)
+
+		return 1
+	}
+
+	var removedFrom []groupLocation
+
+	walkGroups(doc, func(loc groupLocation, _ *yaml.Node, reviewers *yaml.Node) {
+		kept := reviewers.Content[:0]
+
+		for _, node := range reviewers.Content {
+			if strings.EqualFold(reviewerNodeHandle(node), login) {
+				continue
+			}
+
+			kept = append(kept, node)
+		}
+
+		if len(kept) != len(reviewers.Content) {
+			removedFrom = append(removedFrom, loc)
+		}
+
+		reviewers.Content = kept
+	})
+
+	if len(removedFrom) == 0 {
+		fmt.Fprintf(stdout, "%s is not in any groups\n", login)
+
+		return 0
+	}
+
+	if err := saveConfigDocument(stdout, file, doc, original, isDryRun); err != nil {
+		fmt.Fprintln(stderr, err)
+
+		return 1
+	}
+
+	if isDryRun {
+		fmt.Fprintf(stdout, "would have removed %s from:\n", login)
+	} else {
+		fmt.Fprintf(stdout, "removed %s from:\n", login)
+	}
+
+	for _, loc := range removedFrom {
+		fmt.Fprintf(stdout, "  - %s\n", loc)
+	}
+
+	return 0
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+func Test_run_Offboard(t *testing.T) {
+	t.Parallel()
+
+	config := `
+		# the reviewers for each of our repositories
+		repositories:
+			'*':
+				security: [octopus, priyak]
+			octocat/hello-world:
+				# priya is the only one who knows this codebase
+				- priyak
+				- octocat
+			octocat/payments-api:
+				default:
+					description: payment people
+					reviewers:
+						- handle: PriyaK
+							name: Priya K
+						- octodog # octodog is on leave until june
+				infra: [octodog]
+		profiles:
+			work:
+				repositories:
+					my-org/my-awesome-app:
+						- octocat
+						- priyak
+	`
+
+	tests := []struct {
+		name   string
+		args   []string
+		config string
+		exit   int
+	}{
+		{
+			name:   "when removing someone from every group",
+			args:   []string{"offboard", "priyak"},
+			config: config,
+			exit:   0,
+		},
+		{
+			name:   "when doing a dry-run",
+			args:   []string{"offboard", "--dry-run", "@PriyaK"},
+			config: config,
+			exit:   0,
+		},
+		{
+			name:   "when the login is not in any groups",
+			args:   []string{"offboard", "octokitten"},
+			config: config,
+			exit:   0,
+		},
+		{
+			name: "when they are in the groups of an owner",
+			args: []string{"offboard", "octodog"},
+			config: `
+				owners:
+					octocat:
+						default: [octodog, priyak]
+						infra:
+							reviewers: [octodog]
+				repositories:
+					octocat/hello-world: [octodog]
+			`,
+			exit: 0,
+		},
+		{
+			name: "when they are in the groups of a host",
+			args: []string{"offboard", "octodog"},
+			config: `
+				repositories:
+					octocat/hello-world: [octodog]
+				hosts:
+					ghe.example.com:
+						owners:
+							octocat: [octodog, priyak]
+						repositories:
+							octocat/hello-world:
+								infra: [octodog]
+			`,
+			exit: 0,
+		},
+		{
+			name:   "when no login is given",
+			args:   []string{"offboard"},
+			config: config,
+			exit:   1,
+		},
+		{
+			name:   "when the config does not exist",
+			args:   []string{"offboard", "priyak"},
+			config: "",
+			exit:   1,
+		},
+		{
+			name:   "when the config is encrypted",
+			args:   []string{"offboard", "priyak"},
+			config: sopsEncryptedConfig,
+			exit:   1,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			configDir := writeConfigFileInTempDir(t, dedent(t, tt.config))
+
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+
+			got := run(
+				append([]string{"--config-dir", configDir}, tt.args...),
+				stdout,
+				stderr,
+				expectNoCallToGh(t),
+			)
+
+			if got != tt.exit {
+				t.Errorf("run() = %v, want %v", got, tt.exit)
+			}
+
+			content, _ := os.ReadFile(filepath.Join(configDir, "gh-rr.yml"))
+
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stdout))
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stderr))
+			snaps.MatchSnapshot(t, string(content))
+		})
+	}
+}
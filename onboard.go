@@ -0,0 +1,118 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"path"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+type onboardOptions struct {
+	file     string
+	args     []string
+	groups   []string
+	repos    []string
+	isDryRun bool
+}
+
+// matches checks if the group at the given location is one that should have
+// the login added to it
+func (opts onboardOptions) matches(loc groupLocation) bool {
+	if !slices.Contains(opts.groups, loc.group) {
+		return false
+	}
+
+	if len(opts.repos) == 0 {
+		return true
+	}
+
+	repo := loc.repo
+
+	// owner groups apply to every repository of the owner
+	if loc.isOwner {
+		repo += "/*"
+	}
+
+	return slices.ContainsFunc(opts.repos, func(pattern string) bool {
+		matched, _ := path.Match(strings.ToLower(pattern), repo)
+
+		return matched
+	})
+}
+
+// onboard adds the given login to every matching group in the config file
+func onboard(stdout, stderr io.Writer, opts onboardOptions) int {
+	if len(opts.args) != 1 {
+		fmt.Fprintln(stderr, "please provide the login of the person to add")
+
+		return 1
+	}
+
+	if len(opts.groups) == 0 {
+		fmt.Fprintln(stderr, "please provide the groups to add them to with --groups")
+
+		return 1
+	}
+
+	login := strings.TrimPrefix(opts.args[0], "@")
+
+	doc, original, err := loadConfigDocument(opts.file)
+
+	if err != nil {
+		printConfigEditError(stderr, opts.file, err)
+
+		return 1
+	}
+
+	var matched, addedTo []groupLocation
+
+	walkGroups(doc, func(loc groupLocation, _ *yaml.Node, reviewers *yaml.Node) {
+		if !opts.matches(loc) {
+			return
+		}
+
+		matched = append(matched, loc)
+
+		for _, node := range reviewers.Content {
+			if strings.EqualFold(reviewerNodeHandle(node), login) {
+				return
+			}
+		}
+
+		reviewers.Content = append(reviewers.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: login})
+		addedTo = append(addedTo, loc)
+	})
+
+	if len(matched) == 0 {
+		fmt.Fprintln(stderr, "there are no groups that match")
+
+		return 1
+	}
+
+	if len(addedTo) == 0 {
+		fmt.Fprintf(stdout, "%s is already in all of the matching groups\n", login)
+
+		return 0
+	}
+
+	if err := saveConfigDocument(stdout, opts.file, doc, original, opts.isDryRun); err != nil {
+		fmt.Fprintln(stderr, err)
+
+		return 1
+	}
+
+	if opts.isDryRun {
+		fmt.Fprintf(stdout, "would have added %s to:\n", login)
+	} else {
+		fmt.Fprintf(stdout, "added %s to:\n", login)
+	}
+
+	for _, loc := range addedTo {
+		fmt.Fprintf(stdout, "  - %s\n", loc)
+	}
+
+	return 0
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+func Test_run_Onboard(t *testing.T) {
+	t.Parallel()
+
+	config := `
+		repositories:
+			'*':
+				oncall: [octopus]
+			my-org/backend-api:
+				backend:
+					description: backend people
+					reviewers:
+						- octocat # the lead
+				oncall: [octopus]
+			my-org/web-app:
+				- octodog
+			octocat/hello-world:
+				backend: [octocat]
+		profiles:
+			work:
+				repositories:
+					my-org/billing:
+						backend:
+							- octodog
+	`
+
+	tests := []struct {
+		name   string
+		args   []string
+		config string
+		exit   int
+	}{
+		{
+			name:   "when adding someone to groups in matching repositories",
+			args:   []string{"onboard", "priyak", "--groups", "backend,oncall", "--repos", "my-org/*"},
+			config: config,
+			exit:   0,
+		},
+		{
+			name:   "when adding someone to groups in every repository",
+			args:   []string{"onboard", "priyak", "--groups", "backend", "--groups", "oncall"},
+			config: config,
+			exit:   0,
+		},
+		{
+			name:   "when doing a dry-run",
+			args:   []string{"onboard", "--dry-run", "@priyak", "--groups", "backend"},
+			config: config,
+			exit:   0,
+		},
+		{
+			name:   "when they are already in every matching group",
+			args:   []string{"onboard", "OctoCat", "--groups", "backend", "--repos", "octocat/*"},
+			config: config,
+			exit:   0,
+		},
+		{
+			name: "when adding someone to the groups of an owner",
+			args: []string{"onboard", "priyak", "--groups", "backend", "--repos", "my-org/*"},
+			config: `
+				owners:
+					my-org:
+						backend: [octocat]
+					octocat:
+						backend: [octocat]
+				repositories:
+					my-org/web-app:
+						backend: [octodog]
+			`,
+			exit: 0,
+		},
+		{
+			name: "when only adding someone to the groups of a single repository",
+			args: []string{"onboard", "priyak", "--groups", "backend", "--repos", "my-org/web-app"},
+			config: `
+				owners:
+					my-org:
+						backend: [octocat]
+				repositories:
+					my-org/web-app:
+						backend: [octodog]
+			`,
+			exit: 0,
+		},
+		{
+			name: "when adding someone to the groups of a host",
+			args: []string{"onboard", "priyak", "--groups", "backend", "--repos", "my-org/*"},
+			config: `
+				hosts:
+					ghe.example.com:
+						repositories:
+							my-org/web-app:
+								backend: [octodog]
+							octocat/hello-world:
+								backend: [octocat]
+			`,
+			exit: 0,
+		},
+		{
+			name:   "when no groups match",
+			args:   []string{"onboard", "priyak", "--groups", "frontend"},
+			config: config,
+			exit:   1,
+		},
+		{
+			name:   "when no groups are given",
+			args:   []string{"onboard", "priyak"},
+			config: config,
+			exit:   1,
+		},
+		{
+			name:   "when no login is given",
+			args:   []string{"onboard", "--groups", "backend"},
+			config: config,
+			exit:   1,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			configDir := writeConfigFileInTempDir(t, dedent(t, tt.config))
+
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+
+			got := run(
+				append([]string{"--config-dir", configDir}, tt.args...),
+				stdout,
+				stderr,
+				expectNoCallToGh(t),
+			)
+
+			if got != tt.exit {
+				t.Errorf("run() = %v, want %v", got, tt.exit)
+			}
+
+			content, _ := os.ReadFile(filepath.Join(configDir, "gh-rr.yml"))
+
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stdout))
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stderr))
+			snaps.MatchSnapshot(t, string(content))
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+)
+
+// configPaths returns the paths that configuration files are looked for at,
+// in order of precedence, with a config given with --config taking the place
+// of the personal config
+func configPaths(personal []string, configFile string) []string {
+	var paths []string
+
+	if configFile != "" {
+		paths = append(paths, configFile)
+	}
+
+	if shared := findLocalSharedConfig(); shared != "" {
+		paths = append(paths, shared)
+	}
+
+	if configFile != "" {
+		return paths
+	}
+
+	return append(paths, personal...)
+}
+
+// openDirectory opens the directory using the default file manager
+func openDirectory(dir string) error {
+	var cmd *exec.Cmd
+
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", dir)
+	case "windows":
+		cmd = exec.Command("explorer", dir)
+	default:
+		cmd = exec.Command("xdg-open", dir)
+	}
+
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("could not open %s: %w", dir, err)
+	}
+
+	return cmd.Process.Release()
+}
+
+// printConfigPaths outputs where configuration files are looked for, noting
+// which of the paths do not exist, and optionally opens the directory of the
+// one that takes precedence
+func printConfigPaths(stdout, stderr io.Writer, personal []string, configFile string, open bool) int {
+	var found string
+
+	for _, p := range configPaths(personal, configFile) {
+		if _, err := os.Stat(p); err != nil {
+			fmt.Fprintf(stdout, "%s (does not exist)\n", p)
+
+			continue
+		}
+
+		if found == "" {
+			found = p
+		}
+
+		fmt.Fprintln(stdout, p)
+	}
+
+	if !open {
+		return 0
+	}
+
+	if found == "" {
+		fmt.Fprintln(stderr, "there is no configuration file to open the directory of")
+
+		return 1
+	}
+
+	if err := openDirectory(filepath.Dir(found)); err != nil {
+		fmt.Fprintln(stderr, err)
+
+		return 1
+	}
+
+	return 0
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+// normalizeRepositoryDirectory replaces references to the checkout that the
+// tests are being run within with "<repo>", after the std stream normalizers
+func normalizeRepositoryDirectory(t *testing.T, str string) string {
+	t.Helper()
+
+	shared := findLocalSharedConfig()
+
+	if shared == "" {
+		return str
+	}
+
+	root := normalizeStdStream(t, bytes.NewBufferString(filepath.Dir(filepath.Dir(shared))))
+
+	return strings.ReplaceAll(str, root, "<repo>")
+}
+
+func Test_run_OpenConfig(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		args     []string
+		noConfig bool
+		exit     int
+	}{
+		{
+			name: "when the config file exists",
+			args: []string{"open-config"},
+			exit: 0,
+		},
+		{
+			name:     "when the config file does not exist",
+			args:     []string{"open-config"},
+			noConfig: true,
+			exit:     0,
+		},
+		{
+			name:     "when opening a config file that does not exist",
+			args:     []string{"open-config", "--open"},
+			noConfig: true,
+			exit:     1,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			config := dedent(t, `
+				repositories:
+					octocat/hello-world:
+						- octocat
+			`)
+
+			if tt.noConfig {
+				config = ""
+			}
+
+			configDir := writeConfigFileInTempDir(t, config)
+
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+
+			got := run(
+				append([]string{"--config-dir", configDir}, tt.args...),
+				stdout,
+				stderr,
+				expectNoCallToGh(t),
+			)
+
+			if got != tt.exit {
+				t.Errorf("run() = %v, want %v", got, tt.exit)
+			}
+
+			snaps.MatchSnapshot(t, normalizeRepositoryDirectory(t, normalizeStdStream(t, stdout)))
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stderr))
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// resolveOptedOut returns the logins of everyone who has opted out of reviews,
+// mapped to the team they opted out via (if any)
+func resolveOptedOut(cache *teamMemberCache, handles []string) (map[string]string, error) {
+	optedOut := make(map[string]string)
+
+	for _, handle := range handles {
+		handle = strings.TrimPrefix(handle, "@")
+
+		members, isTeam, err := cache.membersOf(handle)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if !isTeam {
+			optedOut[strings.ToLower(handle)] = ""
+
+			continue
+		}
+
+		for _, member := range members {
+			if _, ok := optedOut[strings.ToLower(member)]; !ok {
+				optedOut[strings.ToLower(member)] = handle
+			}
+		}
+	}
+
+	return optedOut, nil
+}
+
+// excludeOptedOut returns the reviewers without anyone who has opted out of
+// reviews, explaining who was skipped and why if requested
+func excludeOptedOut(stderr io.Writer, reviewers []reviewer, optedOut map[string]string, explain bool) []reviewer {
+	kept := make([]reviewer, 0, len(reviewers))
+
+	for _, r := range reviewers {
+		team, ok := optedOut[strings.ToLower(r.Handle)]
+
+		if !ok {
+			kept = append(kept, r)
+
+			continue
+		}
+
+		if !explain {
+			continue
+		}
+
+		if team == "" {
+			fmt.Fprintf(stderr, "skipping %s as they have opted out of reviews\n", r.Handle)
+		} else {
+			fmt.Fprintf(stderr, "skipping %s as they have opted out of reviews by joining %s\n", r.Handle, team)
+		}
+	}
+
+	return kept
+}
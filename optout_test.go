@@ -0,0 +1,108 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+func Test_run_OptedOut(t *testing.T) {
+	t.Parallel()
+
+	responses := map[string]string{
+		"orgs/octo-org/teams/on-leave/members?role=all": `[{"login":"OctoPus"}]`,
+	}
+
+	tests := []struct {
+		name   string
+		args   []string
+		config string
+		exit   int
+	}{
+		{
+			name: "when reviewers have opted out",
+			args: []string{"123"},
+			config: `
+				settings:
+					opted_out: [octodog, octo-org/on-leave]
+			`,
+			exit: 0,
+		},
+		{
+			name: "when explaining why reviewers were skipped",
+			args: []string{"--explain", "123"},
+			config: `
+				settings:
+					opted_out: ['@octodog', octo-org/on-leave]
+			`,
+			exit: 0,
+		},
+		{
+			name: "when everyone in the group has opted out",
+			args: []string{"--explain", "--from", "small", "123"},
+			config: `
+				settings:
+					opted_out: [octodog]
+			`,
+			exit: 0,
+		},
+		{
+			name: "when explaining a group that has a description",
+			args: []string{"--explain", "--from", "described", "123"},
+			config: `
+				settings:
+					opted_out: [octodog]
+			`,
+			exit: 0,
+		},
+		{
+			name: "when the opt out team cannot be found",
+			args: []string{"123"},
+			config: `
+				settings:
+					opted_out: [octo-org/missing]
+			`,
+			exit: 1,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			configDir := writeConfigFileInTempDir(t, dedent(t, tt.config+`
+				repositories:
+					octocat/hello-world:
+						default: [octocat, octodog, octopus]
+						small:
+							reviewers: [octodog]
+							fallback: [fallback]
+						fallback: [octodog, octokitten]
+						described:
+							description: people who know the codebase
+							reviewers: [octocat, octodog]
+			`))
+
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+
+			var calls [][]string
+
+			got := run(
+				append([]string{"--config-dir", configDir, "--repo", "octocat/hello-world"}, tt.args...),
+				stdout,
+				stderr,
+				fakeTeamsGh(t, responses, &calls),
+			)
+
+			if got != tt.exit {
+				t.Errorf("run() = %v, want %v", got, tt.exit)
+			}
+
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stdout))
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stderr))
+			snaps.MatchJSON(t, calls)
+		})
+	}
+}
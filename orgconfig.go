@@ -0,0 +1,69 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// parseExtendsTarget returns the repository and path of the config that is
+// being extended, which is given as OWNER/REPO[/PATH] with the path defaulting
+// to a gh-rr.yml at the root of the repository
+func parseExtendsTarget(target string) (string, string, error) {
+	parts := strings.SplitN(target, "/", 3)
+
+	if len(parts) < 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("extends should be in the format of <owner>/<repository>[/<path>], not `%s`", target)
+	}
+
+	path := "gh-rr.yml"
+
+	if len(parts) == 3 && parts[2] != "" {
+		path = parts[2]
+	}
+
+	return parts[0] + "/" + parts[1], path, nil
+}
+
+// fetchExtendedConfig uses gh to get the config that is being extended from the
+// repository that it is stored in
+func fetchExtendedConfig(ghExec ghExecutor, target string) (config, error) {
+	conf := config{Repositories: repositories{}}
+	repo, path, err := parseExtendsTarget(target)
+
+	if err != nil {
+		return conf, err
+	}
+
+	out, errMsg := ghExec("api", fmt.Sprintf("repos/%s/contents/%s", repo, path), "-H", "Accept: application/vnd.github.raw")
+
+	if errMsg != "" {
+		return conf, fmt.Errorf("could not get the config being extended from %s: %s", target, strings.TrimSpace(errMsg))
+	}
+
+	if err := yaml.Unmarshal([]byte(out), &conf); err != nil {
+		return conf, describeYAMLError(target, []byte(out), err)
+	}
+
+	return conf, nil
+}
+
+// resolveExtendedConfig returns the config layered on top of the config that it
+// extends, if it extends one, along with the config being extended; the
+// extended config cannot itself extend others
+func resolveExtendedConfig(ghExec ghExecutor, conf config) (config, config, error) {
+	if conf.Extends == "" {
+		return conf, config{}, nil
+	}
+
+	base, err := fetchExtendedConfig(ghExec, conf.Extends)
+
+	if err != nil {
+		return conf, base, err
+	}
+
+	base.Extends = ""
+
+	return mergeConfigs(base, conf), base, nil
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+// fakeOrgConfigGh acts as gh for getting the contents of files in repositories,
+// along with requesting reviews
+func fakeOrgConfigGh(t *testing.T, files map[string]string, calls *[][]string) ghExecutor {
+	t.Helper()
+
+	return func(args ...string) (string, string) {
+		t.Helper()
+
+		*calls = append(*calls, args)
+
+		if len(args) > 1 && args[0] == "api" {
+			if content, ok := files[args[1]]; ok {
+				return content, ""
+			}
+
+			return "", "gh: Not Found (HTTP 404)"
+		}
+
+		if len(args) > 2 && args[0] == "pr" && args[1] == "edit" {
+			return fmt.Sprintf("https://github.com/octocat/hello-world/pull/%s", args[2]), ""
+		}
+
+		t.Errorf("unexpected call to gh: %v", args)
+
+		return "", ""
+	}
+}
+
+func Test_run_WithOrgConfig(t *testing.T) {
+	t.Parallel()
+
+	files := map[string]string{
+		"repos/octo-org/review-config/contents/gh-rr.yml": dedent(t, `
+			settings:
+				order: alphabetical
+			repositories:
+				'*':
+					security: [octokitten]
+				octocat/hello-world:
+					default: [octopus, octocat]
+					docs: [octobear]
+		`),
+		"repos/octo-org/review-config/contents/teams/platform.yml": dedent(t, `
+			repositories:
+				octocat/hello-world:
+					- octodog
+		`),
+		"repos/octo-org/broken-config/contents/gh-rr.yml": "repositories: [",
+	}
+
+	tests := []struct {
+		name   string
+		args   []string
+		config string
+		exit   int
+	}{
+		{
+			name: "when using groups from the extended config",
+			args: []string{"--from", "docs", "123"},
+			config: `
+				extends: octo-org/review-config
+			`,
+			exit: 0,
+		},
+		{
+			name: "when overriding groups of the extended config",
+			args: []string{"123"},
+			config: `
+				extends: octo-org/review-config
+				repositories:
+					octocat/hello-world:
+						- octodog
+			`,
+			exit: 0,
+		},
+		{
+			name: "when explaining the config",
+			args: []string{"explain-config"},
+			config: `
+				extends: octo-org/review-config
+				repositories:
+					octocat/hello-world:
+						docs: [octodog]
+			`,
+			exit: 0,
+		},
+		{
+			name: "when using global groups from the extended config",
+			args: []string{"-g", "--from", "security", "123"},
+			config: `
+				extends: octo-org/review-config
+			`,
+			exit: 0,
+		},
+		{
+			name: "when extending a config at a specific path",
+			args: []string{"123"},
+			config: `
+				extends: octo-org/review-config/teams/platform.yml
+			`,
+			exit: 0,
+		},
+		{
+			name: "when the extended config does not exist",
+			args: []string{"123"},
+			config: `
+				extends: octo-org/missing-config
+			`,
+			exit: 1,
+		},
+		{
+			name: "when the extended config is invalid",
+			args: []string{"123"},
+			config: `
+				extends: octo-org/broken-config
+			`,
+			exit: 1,
+		},
+		{
+			name: "when the extended config is not a repository",
+			args: []string{"123"},
+			config: `
+				extends: octo-org
+			`,
+			exit: 1,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			configDir := writeConfigFileInTempDir(t, dedent(t, tt.config))
+
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+
+			var calls [][]string
+
+			got := run(
+				append([]string{"--config-dir", configDir, "--repo", "octocat/hello-world"}, tt.args...),
+				stdout,
+				stderr,
+				fakeOrgConfigGh(t, files, &calls),
+			)
+
+			if got != tt.exit {
+				t.Errorf("run() = %v, want %v", got, tt.exit)
+			}
+
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stdout))
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stderr))
+			snaps.MatchJSON(t, calls)
+		})
+	}
+}
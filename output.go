@@ -0,0 +1,51 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+)
+
+// outputData is what is available to templates used to customize the output
+// after requesting reviews
+type outputData struct {
+	Repository  string
+	PullRequest string
+	URL         string
+	Group       string
+	Reviewers   []reviewer
+	DryRun      bool
+}
+
+// parseOutputTemplate parses the template used to customize the output after
+// requesting reviews, which is done before any reviews are requested so that
+// a broken template does not leave things half done
+func parseOutputTemplate(tmpl string) (*template.Template, error) {
+	t, err := template.New("output").Parse(tmpl)
+
+	if err != nil {
+		return nil, fmt.Errorf("could not parse output template: %w", err)
+	}
+
+	return t, nil
+}
+
+// printOutput renders the output template, ensuring it ends with a newline
+func printOutput(w io.Writer, t *template.Template, data outputData) error {
+	var sb strings.Builder
+
+	if err := t.Execute(&sb, data); err != nil {
+		return fmt.Errorf("could not render output template: %w", err)
+	}
+
+	out := sb.String()
+
+	if !strings.HasSuffix(out, "\n") {
+		out += "\n"
+	}
+
+	fmt.Fprint(w, out)
+
+	return nil
+}
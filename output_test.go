@@ -0,0 +1,89 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+func Test_run_OutputTemplate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		args   []string
+		config string
+		exit   int
+	}{
+		{
+			name: "when the template is configured in the settings",
+			args: []string{"123"},
+			config: `
+				settings:
+					output_template: "{{.URL}} ({{.Group}}): {{range .Reviewers}}@{{.Handle}} {{end}}"
+			`,
+			exit: 0,
+		},
+		{
+			name: "when the template is passed as a flag",
+			args: []string{"--template", "{{len .Reviewers}} reviewers requested on {{.URL}}", "123"},
+			config: `
+				settings:
+					output_template: "{{.URL}}"
+			`,
+			exit: 0,
+		},
+		{
+			name: "when doing a dry-run",
+			args: []string{"--dry-run", "--template", "{{if .DryRun}}would request{{end}} {{.Reviewers}} on {{.Repository}}#{{.PullRequest}}", "123"},
+			exit: 0,
+		},
+		{
+			name: "when the template cannot be parsed",
+			args: []string{"--template", "{{.URL", "123"},
+			exit: 1,
+		},
+		{
+			name: "when the template cannot be rendered",
+			args: []string{"--template", "{{.Missing}}", "123"},
+			exit: 1,
+		},
+		{
+			name: "when searching",
+			args: []string{"--template", "{{.URL}}", "--search", "label:needs-review"},
+			exit: 1,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			configDir := writeConfigFileInTempDir(t, dedent(t, tt.config+`
+				repositories:
+					octocat/hello-world:
+						- octocat
+						- handle: octopus
+						  name: Octo Pus
+			`))
+
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+
+			got := run(
+				append([]string{"--config-dir", configDir, "--repo", "octocat/hello-world"}, tt.args...),
+				stdout,
+				stderr,
+				expectCallToGh(t, "octocat/hello-world", "123"),
+			)
+
+			if got != tt.exit {
+				t.Errorf("run() = %v, want %v", got, tt.exit)
+			}
+
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stdout))
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stderr))
+		})
+	}
+}
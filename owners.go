@@ -0,0 +1,49 @@
+package main
+
+import (
+	"maps"
+	"strings"
+)
+
+// ownerOf returns the lowercase owner of the given repository, which can be in
+// the HOST/OWNER/REPO format
+func ownerOf(repo string) string {
+	if strings.Count(repo, "/") == 2 {
+		_, repo, _ = strings.Cut(repo, "/")
+	}
+
+	owner, _, _ := strings.Cut(repo, "/")
+
+	return strings.ToLower(owner)
+}
+
+// resolveOwnerGroups returns the repositories with the groups of the owner of
+// the given repository being included in its groups, with the groups of the
+// repository itself taking precedence over those of the owner
+func resolveOwnerGroups(conf config, repo string) repositories {
+	ownerGroups, ok := conf.Owners[ownerOf(repo)]
+
+	if !ok {
+		return conf.Repositories
+	}
+
+	key := strings.ToLower(repo)
+	groups := make(map[string]group, len(ownerGroups)+len(conf.Repositories[key]))
+
+	maps.Copy(groups, ownerGroups)
+
+	// the default group of the repository takes precedence over that of the owner
+	if hasDefaultGroup(conf.Repositories[key]) {
+		for name, g := range groups {
+			g.isDefault = false
+			groups[name] = g
+		}
+	}
+
+	maps.Copy(groups, conf.Repositories[key])
+
+	resolved := maps.Clone(conf.Repositories)
+	resolved[key] = groups
+
+	return resolved
+}
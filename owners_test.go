@@ -0,0 +1,116 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+func Test_run_WithOwners(t *testing.T) {
+	t.Parallel()
+
+	config := `
+		owners:
+			OctoCat:
+				default: [octocat]
+				security: [octokitten]
+		repositories:
+			octocat/hello-world:
+				security: [octopus]
+				docs: [octobear]
+			octocat/*:
+				infra: [octodog]
+	`
+
+	tests := []struct {
+		name   string
+		args   []string
+		config string
+		exit   int
+	}{
+		{
+			name:   "when the repository only has groups from its owner",
+			args:   []string{"--repo", "octocat/spoon-knife", "--from", "security", "123"},
+			config: config,
+			exit:   0,
+		},
+		{
+			name:   "when the repository overrides a group of its owner",
+			args:   []string{"--repo", "octocat/hello-world", "--from", "security", "123"},
+			config: config,
+			exit:   0,
+		},
+		{
+			name:   "when the repository extends the groups of its owner",
+			args:   []string{"--repo", "octocat/hello-world", "--from", "docs", "123"},
+			config: config,
+			exit:   0,
+		},
+		{
+			name:   "when using the default group of the owner",
+			args:   []string{"--repo", "octocat/hello-world", "123"},
+			config: config,
+			exit:   0,
+		},
+		{
+			name:   "when the repository is on another host",
+			args:   []string{"--repo", "github.example.com/octocat/hello-world", "123"},
+			config: config,
+			exit:   0,
+		},
+		{
+			name:   "when the owner does not have any groups",
+			args:   []string{"--repo", "octodog/hello-world", "123"},
+			config: config,
+			exit:   1,
+		},
+		{
+			name:   "when listing groups",
+			args:   []string{"--repo", "octocat/spoon-knife", "groups"},
+			config: config,
+			exit:   0,
+		},
+		{
+			name:   "when explaining the config",
+			args:   []string{"explain-config", "octocat/hello-world"},
+			config: config,
+			exit:   0,
+		},
+		{
+			name: "when using groups from teams",
+			args: []string{"--repo", "octocat/hello-world", "--from", "backend", "123"},
+			config: `
+				owners:
+					octocat:
+						groups_from_teams: [backend]
+			`,
+			exit: 0,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			configDir := writeConfigFileInTempDir(t, dedent(t, tt.config))
+
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+
+			got := run(
+				append([]string{"--config-dir", configDir}, tt.args...),
+				stdout,
+				stderr,
+				expectCallToGh(t, "octocat/hello-world", "123"),
+			)
+
+			if got != tt.exit {
+				t.Errorf("run() = %v, want %v", got, tt.exit)
+			}
+
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stdout))
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stderr))
+		})
+	}
+}
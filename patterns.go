@@ -0,0 +1,73 @@
+package main
+
+import (
+	"maps"
+	"path"
+	"strings"
+)
+
+// isRepositoryPattern checks if the repository is a pattern for matching other
+// repositories, rather than being a specific repository or the global groups
+func isRepositoryPattern(repo string) bool {
+	return repo != "*" && strings.Contains(repo, "*")
+}
+
+// matchRepositoryPattern returns the most specific pattern that matches the
+// given repository, being the one with the fewest wildcards and then the most
+// characters, preferring the first alphabetically if that is still ambiguous
+func matchRepositoryPattern(repos repositories, repo string) (string, bool) {
+	best := ""
+	repo = strings.ToLower(repo)
+
+	for pattern := range repos {
+		if !isRepositoryPattern(pattern) {
+			continue
+		}
+
+		if ok, _ := path.Match(pattern, repo); !ok {
+			continue
+		}
+
+		if best == "" || moreSpecificPattern(pattern, best) {
+			best = pattern
+		}
+	}
+
+	return best, best != ""
+}
+
+// moreSpecificPattern checks if the first pattern is more specific than the
+// second pattern
+func moreSpecificPattern(a, b string) bool {
+	if wa, wb := strings.Count(a, "*"), strings.Count(b, "*"); wa != wb {
+		return wa < wb
+	}
+
+	if len(a) != len(b) {
+		return len(a) > len(b)
+	}
+
+	return a < b
+}
+
+// resolveRepositoryPattern returns the repositories with the groups of the most
+// specific pattern that matches the given repository being used as its groups,
+// unless the repository has been explicitly configured
+func resolveRepositoryPattern(repos repositories, repo string) repositories {
+	key := strings.ToLower(repo)
+
+	if _, ok := repos[key]; ok {
+		return repos
+	}
+
+	pattern, ok := matchRepositoryPattern(repos, key)
+
+	if !ok {
+		return repos
+	}
+
+	resolved := maps.Clone(repos)
+	resolved[key] = repos[pattern]
+
+	return resolved
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+func Test_run_WithRepositoryPatterns(t *testing.T) {
+	t.Parallel()
+
+	config := `
+		repositories:
+			'*':
+				security: [octokitten]
+			octocat/*:
+				- octocat
+			octocat/hello-*:
+				- octodog
+			'*/infra-*':
+				- octopus
+			octocat/hello-world:
+				- octobear
+	`
+
+	tests := []struct {
+		name   string
+		args   []string
+		config string
+		exit   int
+	}{
+		{
+			name:   "when the repository matches an owner pattern",
+			args:   []string{"--repo", "octocat/spoon-knife", "123"},
+			config: config,
+			exit:   0,
+		},
+		{
+			name:   "when the repository matches a more specific pattern",
+			args:   []string{"--repo", "OctoCat/Hello-Sunshine", "123"},
+			config: config,
+			exit:   0,
+		},
+		{
+			name:   "when the repository matches an owner wildcard pattern",
+			args:   []string{"--repo", "octodog/infra-tools", "123"},
+			config: config,
+			exit:   0,
+		},
+		{
+			name:   "when the repository is configured explicitly",
+			args:   []string{"--repo", "octocat/hello-world", "123"},
+			config: config,
+			exit:   0,
+		},
+		{
+			name:   "when the repository does not match any patterns",
+			args:   []string{"--repo", "octodog/hello-world", "123"},
+			config: config,
+			exit:   1,
+		},
+		{
+			name:   "when using the global groups",
+			args:   []string{"--repo", "octocat/spoon-knife", "-gf", "security", "123"},
+			config: config,
+			exit:   0,
+		},
+		{
+			name:   "when listing groups",
+			args:   []string{"--repo", "octocat/hello-sunshine", "groups"},
+			config: config,
+			exit:   0,
+		},
+		{
+			name:   "when explaining the config",
+			args:   []string{"explain-config", "octocat/hello-sunshine"},
+			config: config,
+			exit:   0,
+		},
+		{
+			name: "when using groups from teams in an owner pattern",
+			args: []string{"--repo", "octocat/spoon-knife", "-f", "backend", "123"},
+			config: `
+				repositories:
+					octocat/*:
+						groups_from_teams: [backend]
+			`,
+			exit: 0,
+		},
+		{
+			name: "when using groups from teams without an org in an owner wildcard pattern",
+			args: []string{"--repo", "octocat/infra-tools", "123"},
+			config: `
+				repositories:
+					'*/infra-*':
+						groups_from_teams: [backend]
+			`,
+			exit: 1,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			configDir := writeConfigFileInTempDir(t, dedent(t, tt.config))
+
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+
+			got := run(
+				append([]string{"--config-dir", configDir}, tt.args...),
+				stdout,
+				stderr,
+				expectCallToGh(t, "octocat/hello-world", "123"),
+			)
+
+			if got != tt.exit {
+				t.Errorf("run() = %v, want %v", got, tt.exit)
+			}
+
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stdout))
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stderr))
+		})
+	}
+}
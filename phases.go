@@ -0,0 +1,127 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// findForRepository returns the value for the given repository from a map
+// keyed by repository, falling back to the global value
+func findForRepository(m map[string][]string, repo string) []string {
+	for _, name := range []string{repo, "*"} {
+		for r, v := range m {
+			if strings.EqualFold(r, name) {
+				return v
+			}
+		}
+	}
+
+	return nil
+}
+
+// approvers returns the logins of the people whose latest review of the pull
+// request is an approval
+func (pr pullRequest) approvers() map[string]bool {
+	approved := make(map[string]bool)
+
+	for _, review := range pr.Reviews {
+		login := strings.ToLower(review.Author.Login)
+
+		switch review.State {
+		case "APPROVED":
+			approved[login] = true
+		// comments do not change whether someone has approved
+		case "CHANGES_REQUESTED", "DISMISSED":
+			approved[login] = false
+		}
+	}
+
+	return approved
+}
+
+type advanceOptions struct {
+	repo     string
+	target   string
+	isDryRun bool
+}
+
+// advance requests reviews from the group of the first phase of review which
+// has not been approved yet, if it has not already been requested
+func advance(stdout, stderr io.Writer, ghExec ghExecutor, conf config, opts advanceOptions) int {
+	phases := findForRepository(conf.Phases, opts.repo)
+
+	if len(phases) == 0 {
+		fmt.Fprintf(stderr, "no phases of review are configured for %s\n", opts.repo)
+
+		return 1
+	}
+
+	pr, err := fetchPullRequest(ghExec, opts.repo, opts.target, "url", "reviews", "reviewRequests")
+
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+
+		return 1
+	}
+
+	approved := pr.approvers()
+
+	for i, phase := range phases {
+		reviewers, err := determineReviewers(conf, strings.ToLower(opts.repo), phase)
+
+		if err != nil {
+			printReviewersError(stderr, err, opts.repo, phase)
+
+			return 1
+		}
+
+		reviewers, err = expandDynamicReviewers(ghExec, reviewers)
+
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+
+			return 1
+		}
+
+		isApproved := false
+		isRequested := false
+
+		for _, reviewer := range reviewers {
+			isApproved = isApproved || approved[strings.ToLower(reviewer.Handle)]
+			isRequested = isRequested || pr.hasRequestedReviewFrom(reviewer.Handle)
+		}
+
+		if isApproved {
+			continue
+		}
+
+		if isRequested {
+			fmt.Fprintf(stdout, "phase %d of review is waiting on an approval from the %s group\n", i+1, phase)
+
+			return 0
+		}
+
+		if opts.isDryRun {
+			fmt.Fprintf(stdout, "would have requested reviews on %s from the %s group for phase %d of review:\n", pr.URL, phase, i+1)
+		} else {
+			if _, err := attachReviewers(ghExec, opts.repo, pr.URL, reviewers, ""); err != nil {
+				fmt.Fprintf(stderr, "could not add reviewers: %v\n", err)
+
+				return 1
+			}
+
+			fmt.Fprintf(stdout, "requested reviews on %s from the %s group for phase %d of review:\n", pr.URL, phase, i+1)
+		}
+
+		for _, reviewer := range reviewers {
+			fmt.Fprintf(stdout, "  - %s\n", reviewer)
+		}
+
+		return 0
+	}
+
+	fmt.Fprintln(stdout, "every phase of review has been approved")
+
+	return 0
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+// fakePhasesGh acts as gh for a pull request with the given reviews and review requests
+func fakePhasesGh(t *testing.T, details string, calls *[][]string) ghExecutor {
+	t.Helper()
+
+	return func(args ...string) (string, string) {
+		t.Helper()
+
+		*calls = append(*calls, args)
+
+		switch {
+		case len(args) > 1 && args[0] == "pr" && args[1] == "view":
+			return details, ""
+		case len(args) > 1 && args[0] == "pr" && args[1] == "edit":
+			return "https://github.com/octocat/hello-world/pull/123", ""
+		}
+
+		t.Errorf("unexpected call to gh: %v", args)
+
+		return "", ""
+	}
+}
+
+func Test_run_Advance(t *testing.T) {
+	t.Parallel()
+
+	config := `
+		phases:
+			octocat/Hello-World: [peers, leads]
+		repositories:
+			octocat/hello-world:
+				peers: [octocat, octopus]
+				leads: [octodog]
+	`
+
+	tests := []struct {
+		name    string
+		args    []string
+		config  string
+		details string
+		exit    int
+	}{
+		{
+			name:    "when no reviews have been requested",
+			args:    []string{"advance", "123"},
+			config:  config,
+			details: `{"url":"https://github.com/octocat/hello-world/pull/123","reviews":[],"reviewRequests":[]}`,
+			exit:    0,
+		},
+		{
+			name:    "when the first phase is waiting on an approval",
+			args:    []string{"advance", "123"},
+			config:  config,
+			details: `{"url":"https://github.com/octocat/hello-world/pull/123","reviews":[{"author":{"login":"octopus"},"state":"COMMENTED"}],"reviewRequests":[{"login":"octocat"}]}`,
+			exit:    0,
+		},
+		{
+			name:    "when the first phase has been approved",
+			args:    []string{"advance", "123"},
+			config:  config,
+			details: `{"url":"https://github.com/octocat/hello-world/pull/123","reviews":[{"author":{"login":"OctoPus"},"state":"APPROVED"},{"author":{"login":"octopus"},"state":"COMMENTED"}],"reviewRequests":[{"login":"octocat"}]}`,
+			exit:    0,
+		},
+		{
+			name:    "when an approval has been replaced by a request for changes",
+			args:    []string{"advance", "123"},
+			config:  config,
+			details: `{"url":"https://github.com/octocat/hello-world/pull/123","reviews":[{"author":{"login":"octopus"},"state":"APPROVED"},{"author":{"login":"octopus"},"state":"CHANGES_REQUESTED"}],"reviewRequests":[]}`,
+			exit:    0,
+		},
+		{
+			name:    "when every phase has been approved",
+			args:    []string{"advance", "123"},
+			config:  config,
+			details: `{"url":"https://github.com/octocat/hello-world/pull/123","reviews":[{"author":{"login":"octocat"},"state":"APPROVED"},{"author":{"login":"octodog"},"state":"APPROVED"}],"reviewRequests":[]}`,
+			exit:    0,
+		},
+		{
+			name:    "when doing a dry-run",
+			args:    []string{"advance", "--dry-run", "123"},
+			config:  config,
+			details: `{"url":"https://github.com/octocat/hello-world/pull/123","reviews":[{"author":{"login":"octocat"},"state":"APPROVED"}],"reviewRequests":[]}`,
+			exit:    0,
+		},
+		{
+			name: "when a phase group does not exist",
+			args: []string{"advance", "123"},
+			config: `
+				phases:
+					octocat/hello-world: [peers, leads]
+				repositories:
+					octocat/hello-world:
+						peers: [octocat, octopus]
+			`,
+			details: `{"url":"https://github.com/octocat/hello-world/pull/123","reviews":[{"author":{"login":"octocat"},"state":"APPROVED"}],"reviewRequests":[]}`,
+			exit:    1,
+		},
+		{
+			name: "when there are no phases",
+			args: []string{"advance", "123"},
+			config: `
+				repositories:
+					octocat/hello-world:
+						- octocat
+			`,
+			exit: 1,
+		},
+		{
+			name:   "when given multiple pull requests",
+			args:   []string{"advance", "1", "2"},
+			config: config,
+			exit:   1,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			configDir := writeConfigFileInTempDir(t, dedent(t, tt.config))
+
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+
+			var calls [][]string
+
+			got := run(
+				append([]string{"--config-dir", configDir, "--repo", "octocat/hello-world"}, tt.args...),
+				stdout,
+				stderr,
+				fakePhasesGh(t, tt.details, &calls),
+			)
+
+			if got != tt.exit {
+				t.Errorf("run() = %v, want %v", got, tt.exit)
+			}
+
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stdout))
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stderr))
+			snaps.MatchJSON(t, calls)
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// plan describes the changes that would be made to a pull request, in a form
+// that can be inspected by other tools before they are actually made
+type plan struct {
+	Repository  string   `json:"repository"`
+	PullRequest string   `json:"pullRequest"`
+	Group       string   `json:"group"`
+	Add         []string `json:"add"`
+	Assign      []string `json:"assign"`
+	Remove      []string `json:"remove"`
+	Labels      []string `json:"labels"`
+	Comments    []string `json:"comments"`
+}
+
+// newPlan returns the plan for attaching the reviewers to the pull request the
+// same way as attachReviewers, based on the method of each of them
+func newPlan(repo, target, group string, reviewers []reviewer, urgentLabel string) plan {
+	p := plan{
+		Repository:  repo,
+		PullRequest: target,
+		Group:       group,
+		Add:         []string{},
+		Assign:      []string{},
+		Remove:      []string{},
+		Labels:      []string{},
+		Comments:    []string{},
+	}
+
+	for _, reviewer := range reviewers {
+		switch reviewer.method {
+		case methodAssignee:
+			p.Assign = append(p.Assign, reviewer.Handle)
+		case methodMention:
+			// these are mentioned in a comment instead
+		case methodReviewer, "":
+			p.Add = append(p.Add, reviewer.Handle)
+		}
+	}
+
+	if urgentLabel != "" {
+		p.Labels = append(p.Labels, urgentLabel)
+	}
+
+	if comment := buildMentionComment(reviewers); comment != "" {
+		p.Comments = append(p.Comments, comment)
+	}
+
+	return p
+}
+
+// printPlan outputs the plan as indented json
+func printPlan(w io.Writer, p plan) error {
+	out, err := json.MarshalIndent(p, "", "  ")
+
+	if err != nil {
+		return fmt.Errorf("could not marshal plan: %w", err)
+	}
+
+	fmt.Fprintln(w, string(out))
+
+	return nil
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/cli/go-gh/v2/pkg/term"
+)
+
+// promptsDisabled checks if prompts have been disabled by the environment,
+// either explicitly for gh or by running in CI
+func promptsDisabled() bool {
+	if os.Getenv("GH_PROMPT_DISABLED") != "" {
+		return true
+	}
+
+	ci, _ := strconv.ParseBool(os.Getenv("CI"))
+
+	return ci
+}
+
+// canPrompt reports if it is possible to ask questions, which every prompt
+// should check so that it can fall back to a default or error when running
+// in scripts and CI
+func canPrompt() bool {
+	return !promptsDisabled() && term.IsTerminal(os.Stdin) && term.IsTerminal(os.Stdout)
+}
+
+// prompter asks questions on stdout, reading the answers from stdin
+type prompter struct {
+	stdout  io.Writer
+	scanner *bufio.Scanner
+}
+
+// ask asks the question, returning the answer or the default if there is no
+// answer; the default is included with the question if there is one
+func (p prompter) ask(question, def string) string {
+	if def != "" {
+		question = fmt.Sprintf("%s (%s)", question, def)
+	}
+
+	fmt.Fprintf(p.stdout, "%s: ", question)
+
+	if !p.scanner.Scan() {
+		fmt.Fprintln(p.stdout)
+
+		return def
+	}
+
+	if answer := strings.TrimSpace(p.scanner.Text()); answer != "" {
+		return answer
+	}
+
+	return def
+}
+
+// confirm asks a yes or no question, defaulting to yes
+func (p prompter) confirm(question string) bool {
+	answer := strings.ToLower(p.ask(question, "Y/n"))
+
+	return answer == "y/n" || strings.HasPrefix(answer, "y")
+}
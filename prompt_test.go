@@ -0,0 +1,55 @@
+package main
+
+import (
+	"testing"
+)
+
+func Test_promptsDisabled(t *testing.T) {
+	tests := []struct {
+		name     string
+		disabled string
+		ci       string
+		want     bool
+	}{
+		{
+			name:     "when nothing is set",
+			disabled: "",
+			ci:       "",
+			want:     false,
+		},
+		{
+			name:     "when prompts have been disabled for gh",
+			disabled: "1",
+			ci:       "",
+			want:     true,
+		},
+		{
+			name:     "when running in ci",
+			disabled: "",
+			ci:       "true",
+			want:     true,
+		},
+		{
+			name:     "when ci has been turned off",
+			disabled: "",
+			ci:       "false",
+			want:     false,
+		},
+		{
+			name:     "when ci is not a boolean",
+			disabled: "",
+			ci:       "woodpecker",
+			want:     false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("GH_PROMPT_DISABLED", tt.disabled)
+			t.Setenv("CI", tt.ci)
+
+			if got := promptsDisabled(); got != tt.want {
+				t.Errorf("promptsDisabled() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
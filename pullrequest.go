@@ -0,0 +1,215 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// pullRequest holds the details of a pull request as returned by `gh pr view --json`,
+// though only the fields that were explicitly requested will be populated
+type pullRequest struct {
+	URL    string `json:"url"`
+	Title  string `json:"title"`
+	Body   string `json:"body"`
+	Author struct {
+		Login string `json:"login"`
+	} `json:"author"`
+	Additions               int `json:"additions"`
+	Deletions               int `json:"deletions"`
+	ClosingIssuesReferences []struct {
+		Number int `json:"number"`
+	} `json:"closingIssuesReferences"`
+	Files []struct {
+		Path string `json:"path"`
+	} `json:"files"`
+	Reviews []struct {
+		Author struct {
+			Login string `json:"login"`
+		} `json:"author"`
+		State string `json:"state"`
+	} `json:"reviews"`
+	ReviewRequests []struct {
+		Login string `json:"login"`
+		Slug  string `json:"slug"`
+		Name  string `json:"name"`
+	} `json:"reviewRequests"`
+}
+
+// fetchPullRequest uses gh to get the given fields of the target pull request
+func fetchPullRequest(ghExec ghExecutor, repo, target string, fields ...string) (pullRequest, error) {
+	var pr pullRequest
+
+	args := []string{"pr", "view"}
+
+	if target != "" {
+		args = append(args, target)
+	}
+
+	out, errMsg := ghExec(append(args, "--repo", repo, "--json", strings.Join(fields, ","))...)
+
+	if errMsg != "" {
+		return pr, fmt.Errorf("could not get details of the pull request: %s", strings.TrimSpace(errMsg))
+	}
+
+	if err := json.Unmarshal([]byte(out), &pr); err != nil {
+		return pr, fmt.Errorf("could not get details of the pull request: %w", err)
+	}
+
+	return pr, nil
+}
+
+// hasRequestedReviewFrom checks if a review has already been requested from
+// the given user or team on the pull request
+func (pr pullRequest) hasRequestedReviewFrom(handle string) bool {
+	for _, request := range pr.ReviewRequests {
+		for _, name := range []string{request.Login, request.Slug, request.Name} {
+			if name != "" && strings.EqualFold(name, handle) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// hasRequestedReviewFromAll checks if a review has already been requested
+// from every one of the given reviewers
+func hasRequestedReviewFromAll(pr pullRequest, reviewers []reviewer) bool {
+	for _, reviewer := range reviewers {
+		if !pr.hasRequestedReviewFrom(reviewer.Handle) {
+			return false
+		}
+	}
+
+	return true
+}
+
+var pullRequestReferenceRe = regexp.MustCompile(`^((?:[^/#\s]+/)?[^/#\s]+/[^/#\s]+)#(\d+)$`)
+
+// parsePullRequestReference parses a reference to a pull request in the format
+// of [HOST/]OWNER/REPO#NUMBER, returning the repository and number separately
+func parsePullRequestReference(reference string) (string, string, bool) {
+	matches := pullRequestReferenceRe.FindStringSubmatch(reference)
+
+	if matches == nil {
+		return "", "", false
+	}
+
+	return matches[1], matches[2], true
+}
+
+// branchPullRequest holds the details of a pull request as returned by
+// `gh pr list --json`, for finding the pull request of a branch
+type branchPullRequest struct {
+	Number              int    `json:"number"`
+	URL                 string `json:"url"`
+	BaseRefName         string `json:"baseRefName"`
+	HeadRefName         string `json:"headRefName"`
+	HeadRepositoryOwner struct {
+		Login string `json:"login"`
+	} `json:"headRepositoryOwner"`
+}
+
+// currentBranch returns the name of the branch that is currently checked out
+func currentBranch() (string, error) {
+	out, err := exec.Command("git", "branch", "--show-current").Output()
+
+	if err != nil {
+		return "", fmt.Errorf("could not determine the current branch: %w", err)
+	}
+
+	return strings.TrimSpace(string(out)), nil
+}
+
+// findBranchPullRequest returns the number of the open pull request for the
+// given branch (or the current branch if there is no target) that matches the
+// base and head, which is in the format of [OWNER:]BRANCH
+func findBranchPullRequest(ghExec ghExecutor, repo, target, base, head string) (string, error) {
+	if _, err := strconv.Atoi(target); err == nil || strings.HasPrefix(target, "http") {
+		return "", errors.New("--base and --head can only be used when targeting a branch")
+	}
+
+	owner, branch, hasOwner := strings.Cut(head, ":")
+
+	if !hasOwner {
+		owner, branch = "", head
+	}
+
+	if branch != "" && target != "" && branch != target {
+		return "", fmt.Errorf("--head is for the %s branch, not %s", branch, target)
+	}
+
+	if branch == "" {
+		branch = target
+	}
+
+	if branch == "" {
+		current, err := currentBranch()
+
+		if err != nil {
+			return "", err
+		}
+
+		branch = current
+	}
+
+	args := []string{"pr", "list", "--repo", repo, "--head", branch, "--state", "open"}
+
+	if base != "" {
+		args = append(args, "--base", base)
+	}
+
+	out, errMsg := ghExec(append(args, "--json", "number,url,baseRefName,headRefName,headRepositoryOwner")...)
+
+	if errMsg != "" {
+		return "", fmt.Errorf("could not find the pull request of %s: %s", branch, strings.TrimSpace(errMsg))
+	}
+
+	var prs []branchPullRequest
+
+	if err := json.Unmarshal([]byte(out), &prs); err != nil {
+		return "", fmt.Errorf("could not find the pull request of %s: %w", branch, err)
+	}
+
+	matches := make([]branchPullRequest, 0, len(prs))
+
+	for _, pr := range prs {
+		if owner == "" || strings.EqualFold(pr.HeadRepositoryOwner.Login, owner) {
+			matches = append(matches, pr)
+		}
+	}
+
+	description := branch
+
+	if owner != "" {
+		description = owner + ":" + branch
+	}
+
+	if base != "" {
+		description += " into " + base
+	}
+
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("there are no open pull requests for %s", description)
+	case 1:
+		return strconv.Itoa(matches[0].Number), nil
+	}
+
+	lines := make([]string, 0, len(matches))
+
+	for _, pr := range matches {
+		lines = append(lines, fmt.Sprintf("  - %s (%s:%s into %s)", pr.URL, pr.HeadRepositoryOwner.Login, pr.HeadRefName, pr.BaseRefName))
+	}
+
+	return "", fmt.Errorf(
+		"there are multiple open pull requests for %s, so please use --base or --head to pick one:\n%s",
+		description,
+		strings.Join(lines, "\n"),
+	)
+}
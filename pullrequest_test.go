@@ -0,0 +1,218 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+// fakeBranchGh acts as gh for a repository with open pull requests for the
+// "feature" branch from two forks and against two bases
+func fakeBranchGh(t *testing.T, calls *[][]string) ghExecutor {
+	t.Helper()
+
+	prs := []branchPullRequest{
+		{Number: 1, URL: "https://github.com/octocat/hello-world/pull/1", BaseRefName: "main", HeadRefName: "feature"},
+		{Number: 2, URL: "https://github.com/octocat/hello-world/pull/2", BaseRefName: "release", HeadRefName: "feature"},
+		{Number: 3, URL: "https://github.com/octocat/hello-world/pull/3", BaseRefName: "main", HeadRefName: "feature"},
+	}
+
+	prs[0].HeadRepositoryOwner.Login = "octocat"
+	prs[1].HeadRepositoryOwner.Login = "octocat"
+	prs[2].HeadRepositoryOwner.Login = "octodog"
+
+	return func(args ...string) (string, string) {
+		t.Helper()
+
+		*calls = append(*calls, args)
+
+		switch {
+		case len(args) > 1 && args[0] == "pr" && args[1] == "list":
+			matches := make([]branchPullRequest, 0, len(prs))
+
+			head := args[slices.Index(args, "--head")+1]
+			base := ""
+
+			if i := slices.Index(args, "--base"); i != -1 {
+				base = args[i+1]
+			}
+
+			for _, pr := range prs {
+				if pr.HeadRefName == head && (base == "" || pr.BaseRefName == base) {
+					matches = append(matches, pr)
+				}
+			}
+
+			out, err := json.Marshal(matches)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			return string(out), ""
+		case len(args) > 2 && args[0] == "pr" && args[1] == "edit":
+			return fmt.Sprintf("https://github.com/octocat/hello-world/pull/%s", args[2]), ""
+		}
+
+		t.Errorf("unexpected call to gh: %v", args)
+
+		return "", ""
+	}
+}
+
+func Test_run_WithBaseAndHead(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		args []string
+		exit int
+	}{
+		{
+			name: "when the base picks one pull request",
+			args: []string{"--base", "release", "feature"},
+			exit: 0,
+		},
+		{
+			name: "when the head picks one pull request",
+			args: []string{"--head", "octodog:feature"},
+			exit: 0,
+		},
+		{
+			name: "when the base and head pick one pull request",
+			args: []string{"--base", "main", "--head", "OctoCat:feature", "feature"},
+			exit: 0,
+		},
+		{
+			name: "when multiple pull requests still match",
+			args: []string{"--base", "main", "feature"},
+			exit: 1,
+		},
+		{
+			name: "when no pull requests match",
+			args: []string{"--head", "octopus:feature"},
+			exit: 1,
+		},
+		{
+			name: "when the head is for a different branch than the target",
+			args: []string{"--head", "octocat:feature", "bugfix"},
+			exit: 1,
+		},
+		{
+			name: "when targeting a pull request by number",
+			args: []string{"--base", "main", "123"},
+			exit: 1,
+		},
+		{
+			name: "when targeting a pull request by url",
+			args: []string{"--base", "main", "https://github.com/octocat/hello-world/pull/1"},
+			exit: 1,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			configDir := writeConfigFileInTempDir(t, dedent(t, `
+				repositories:
+					octocat/hello-world:
+						- octopus
+			`))
+
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+
+			var calls [][]string
+
+			got := run(
+				append([]string{"--config-dir", configDir, "--repo", "octocat/hello-world"}, tt.args...),
+				stdout,
+				stderr,
+				fakeBranchGh(t, &calls),
+			)
+
+			if got != tt.exit {
+				t.Errorf("run() = %v, want %v", got, tt.exit)
+			}
+
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stdout))
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stderr))
+			snaps.MatchJSON(t, calls)
+		})
+	}
+}
+
+func Test_run_WithPullRequestReference(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		args []string
+		exit int
+	}{
+		{
+			name: "when given a reference to a pull request",
+			args: []string{"octocat/hello-world#42"},
+			exit: 0,
+		},
+		{
+			name: "when given a reference along with a repository",
+			args: []string{"--repo", "octocat/spoon-knife", "octocat/hello-world#42"},
+			exit: 0,
+		},
+		{
+			name: "when given a reference to a pull request on another host",
+			args: []string{"github.example.com/octocat/hello-world#42"},
+			exit: 0,
+		},
+		{
+			name: "when given a reference to a repository that is not configured",
+			args: []string{"--repo", "octocat/hello-world", "octodog/hello-world#42"},
+			exit: 1,
+		},
+		{
+			name: "when given a branch that looks like a reference",
+			args: []string{"--repo", "octocat/hello-world", "issue#42"},
+			exit: 0,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			configDir := writeConfigFileInTempDir(t, dedent(t, `
+				repositories:
+					octocat/hello-world:
+						- octopus
+					github.example.com/octocat/hello-world:
+						- octodog
+			`))
+
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+
+			var calls [][]string
+
+			got := run(
+				append([]string{"--config-dir", configDir}, tt.args...),
+				stdout,
+				stderr,
+				fakeBranchGh(t, &calls),
+			)
+
+			if got != tt.exit {
+				t.Errorf("run() = %v, want %v", got, tt.exit)
+			}
+
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stdout))
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stderr))
+			snaps.MatchJSON(t, calls)
+		})
+	}
+}
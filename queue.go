@@ -0,0 +1,382 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// queuedRequest is a request for reviews that is waiting to be made
+type queuedRequest struct {
+	Host        string           `json:"host,omitempty"`
+	Repository  string           `json:"repository"`
+	PullRequest string           `json:"pullRequest"`
+	Group       string           `json:"group"`
+	Reviewers   []queuedReviewer `json:"reviewers"`
+	QueuedAt    time.Time        `json:"queuedAt"`
+}
+
+// queuedReviewer is someone that a queued request is for, along with how they
+// should be attached to the pull request and the timezone they work in
+type queuedReviewer struct {
+	Handle   string        `json:"handle"`
+	Method   requestMethod `json:"method,omitempty"`
+	Timezone string        `json:"tz,omitempty"`
+}
+
+// isWorking checks if it is within the given working hours for the reviewer,
+// in their timezone if it is known or otherwise the local one
+func (r queuedReviewer) isWorking(hours workingHours, now time.Time) bool {
+	if r.Timezone != "" {
+		if loc, err := time.LoadLocation(r.Timezone); err == nil {
+			now = now.In(loc)
+		}
+	}
+
+	return hours.contains(now)
+}
+
+func (r *queuedReviewer) UnmarshalJSON(data []byte) error {
+	// requests queued before methods were recorded only have the handle
+	if err := json.Unmarshal(data, &r.Handle); err == nil {
+		return nil
+	}
+
+	type rawQueuedReviewer queuedReviewer
+
+	return json.Unmarshal(data, (*rawQueuedReviewer)(r))
+}
+
+// loadQueue reads the queued requests from the given file, which is treated
+// as being empty if it does not exist
+func loadQueue(file string) ([]queuedRequest, error) {
+	out, err := os.ReadFile(file)
+
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("could not read queue: %w", err)
+	}
+
+	var queue []queuedRequest
+
+	if err := json.Unmarshal(out, &queue); err != nil {
+		return nil, fmt.Errorf("could not parse %s: %w", file, err)
+	}
+
+	return queue, nil
+}
+
+// saveQueue writes the queued requests to the given file, removing it if
+// there is nothing left in the queue
+func saveQueue(file string, queue []queuedRequest) error {
+	if len(queue) == 0 {
+		if err := os.Remove(file); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("could not save queue: %w", err)
+		}
+
+		return nil
+	}
+
+	out, err := json.MarshalIndent(queue, "", "  ")
+
+	if err != nil {
+		return fmt.Errorf("could not save queue: %w", err)
+	}
+
+	if err := os.WriteFile(file, append(out, '\n'), 0600); err != nil {
+		return fmt.Errorf("could not save queue: %w", err)
+	}
+
+	return nil
+}
+
+type queueOptions struct {
+	file      string
+	host      string
+	repo      string
+	target    string
+	group     string
+	reviewers []reviewer
+	isDryRun  bool
+}
+
+// queueRequest records a request for reviews to be made later by flushQueue
+func queueRequest(stdout, stderr io.Writer, ghExec ghExecutor, opts queueOptions) int {
+	// the pull request is resolved now, as the queue might be flushed elsewhere
+	pr, err := fetchPullRequest(ghExec, opts.repo, opts.target, "url")
+
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+
+		return 1
+	}
+
+	if opts.isDryRun {
+		fmt.Fprintf(stdout, "would have queued a request for reviews on %s from:\n", pr.URL)
+	} else {
+		queue, err := loadQueue(opts.file)
+
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+
+			return 1
+		}
+
+		queued := make([]queuedReviewer, 0, len(opts.reviewers))
+
+		for _, reviewer := range opts.reviewers {
+			queued = append(queued, queuedReviewer{Handle: reviewer.Handle, Method: reviewer.method, Timezone: reviewer.Timezone})
+		}
+
+		queue = append(queue, queuedRequest{
+			Host:        opts.host,
+			Repository:  opts.repo,
+			PullRequest: pr.URL,
+			Group:       opts.group,
+			Reviewers:   queued,
+			QueuedAt:    time.Now().UTC(),
+		})
+
+		if err := saveQueue(opts.file, queue); err != nil {
+			fmt.Fprintln(stderr, err)
+
+			return 1
+		}
+
+		fmt.Fprintf(stdout, "queued a request for reviews on %s from:\n", pr.URL)
+	}
+
+	printQueuedReviewers(stdout, opts.reviewers)
+
+	return 0
+}
+
+type flushOptions struct {
+	file     string
+	hours    workingHours
+	now      time.Time
+	isDryRun bool
+}
+
+// splitQueueByWorkingHours splits the queued requests into those with reviewers
+// that are currently working, and those with reviewers that are not; requests
+// with both kinds of reviewers are split in two
+func splitQueueByWorkingHours(queue []queuedRequest, hours workingHours, now time.Time) ([]queuedRequest, []queuedRequest) {
+	var due, waiting []queuedRequest
+
+	for _, request := range queue {
+		var working, resting []queuedReviewer
+
+		for _, reviewer := range request.Reviewers {
+			if reviewer.isWorking(hours, now) {
+				working = append(working, reviewer)
+			} else {
+				resting = append(resting, reviewer)
+			}
+		}
+
+		if len(working) > 0 {
+			r := request
+			r.Reviewers = working
+			due = append(due, r)
+		}
+
+		if len(resting) > 0 {
+			r := request
+			r.Reviewers = resting
+			waiting = append(waiting, r)
+		}
+	}
+
+	return due, waiting
+}
+
+// flushQueue makes each of the queued requests for reviews, from whichever of
+// their reviewers are currently within working hours
+func flushQueue(stdout, stderr io.Writer, ghExec ghExecutor, opts flushOptions) int {
+	queue, err := loadQueue(opts.file)
+
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+
+		return 1
+	}
+
+	if len(queue) == 0 {
+		fmt.Fprintln(stdout, "there are no queued review requests")
+
+		return 0
+	}
+
+	due, waiting := splitQueueByWorkingHours(queue, opts.hours, opts.now)
+
+	if len(due) == 0 {
+		fmt.Fprintf(stdout, "not requesting reviews on %d queued pull requests as it is outside of working hours\n", len(queue))
+
+		return 0
+	}
+
+	if len(waiting) > 0 {
+		fmt.Fprintf(stdout, "not yet requesting reviews on %d queued pull requests from reviewers outside of their working hours\n", len(waiting))
+	}
+
+	hosts, byHost := groupQueueByHost(due)
+
+	// requests from reviewers that are not working are kept for a later flush
+	remaining := waiting
+	failed := false
+
+	summaries := make(map[string]string, len(hosts))
+
+	for _, host := range hosts {
+		requests := byHost[host]
+
+		if !opts.isDryRun {
+			// each host is authenticated with separately, so a problem with one
+			// does not stop the requests on the others from being made
+			if err := checkHostAuth(ghExec, host); err != nil {
+				fmt.Fprintf(stderr, "could not authenticate with %s, so not requesting reviews on %d queued pull requests: %v\n", host, len(requests), err)
+
+				remaining = append(remaining, requests...)
+				summaries[host] = "could not authenticate"
+				failed = true
+
+				continue
+			}
+		}
+
+		made := 0
+
+		for _, request := range requests {
+			if !flushRequest(stdout, stderr, ghExec, host, request, opts.isDryRun) {
+				// keep the request around so that it can be tried again later
+				remaining = append(remaining, request)
+				failed = true
+
+				continue
+			}
+
+			made++
+		}
+
+		if opts.isDryRun {
+			summaries[host] = fmt.Sprintf("would have requested reviews on %d queued pull requests", made)
+		} else {
+			summaries[host] = fmt.Sprintf("requested reviews on %d of %d queued pull requests", made, len(requests))
+		}
+	}
+
+	if len(hosts) > 1 {
+		fmt.Fprintln(stdout, "\nsummary:")
+
+		for _, host := range hosts {
+			fmt.Fprintf(stdout, "  %s: %s\n", host, summaries[host])
+		}
+	}
+
+	if opts.isDryRun {
+		return 0
+	}
+
+	if err := saveQueue(opts.file, remaining); err != nil {
+		fmt.Fprintln(stderr, err)
+
+		return 1
+	}
+
+	if failed {
+		return 1
+	}
+
+	return 0
+}
+
+// groupQueueByHost groups the queued requests by the host that their
+// repository is on, returning the hosts in the order they were first queued
+func groupQueueByHost(queue []queuedRequest) ([]string, map[string][]queuedRequest) {
+	var hosts []string
+
+	byHost := make(map[string][]queuedRequest)
+
+	for _, request := range queue {
+		host := request.Host
+
+		// requests queued before hosts were recorded are on the default host
+		if host == "" {
+			host = defaultHost
+		}
+
+		if _, ok := byHost[host]; !ok {
+			hosts = append(hosts, host)
+		}
+
+		byHost[host] = append(byHost[host], request)
+	}
+
+	return hosts, byHost
+}
+
+// checkHostAuth checks that gh is able to authenticate with the given host
+func checkHostAuth(ghExec ghExecutor, host string) error {
+	if _, errMsg := ghExec("api", "--hostname", host, "user", "--jq", ".login"); errMsg != "" {
+		return errors.New(strings.TrimSpace(errMsg))
+	}
+
+	return nil
+}
+
+// qualifyRepository returns the repository in the HOST/OWNER/REPO format when
+// it is not on the default host, so that gh knows which host to use for it
+func qualifyRepository(host, repo string) string {
+	if host == defaultHost || strings.Count(repo, "/") == 2 {
+		return repo
+	}
+
+	return host + "/" + repo
+}
+
+// flushRequest makes the queued request for reviews, returning if it was made
+func flushRequest(stdout, stderr io.Writer, ghExec ghExecutor, host string, request queuedRequest, isDryRun bool) bool {
+	reviewers := make([]reviewer, 0, len(request.Reviewers))
+
+	for _, queued := range request.Reviewers {
+		reviewers = append(reviewers, reviewer{Handle: queued.Handle, method: queued.Method})
+	}
+
+	if isDryRun {
+		fmt.Fprintf(stdout, "would have requested reviews on %s from:\n", request.PullRequest)
+	} else {
+		repo := qualifyRepository(host, request.Repository)
+
+		if _, err := attachReviewers(ghExec, repo, request.PullRequest, reviewers, ""); err != nil {
+			fmt.Fprintf(stderr, "could not request reviews on %s: %v\n", request.PullRequest, err)
+
+			return false
+		}
+
+		fmt.Fprintf(stdout, "requested reviews on %s from:\n", request.PullRequest)
+	}
+
+	printQueuedReviewers(stdout, reviewers)
+
+	return true
+}
+
+// printQueuedReviewers lists the reviewers of a queued request, noting how they
+// are attached if it is not by requesting a review from them
+func printQueuedReviewers(stdout io.Writer, reviewers []reviewer) {
+	for _, reviewer := range reviewers {
+		if note := describeMethod(reviewer); note != "" {
+			fmt.Fprintf(stdout, "  - %s (%s)\n", reviewer, note)
+		} else {
+			fmt.Fprintf(stdout, "  - %s\n", reviewer)
+		}
+	}
+}
@@ -0,0 +1,420 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+// fakeQueueGh acts as gh for a repository where requesting reviews on pull
+// request #13 always fails, and that cannot authenticate with ghe.invalid
+func fakeQueueGh(t *testing.T, calls *[][]string) ghExecutor {
+	t.Helper()
+
+	return func(args ...string) (string, string) {
+		t.Helper()
+
+		*calls = append(*calls, args)
+
+		switch {
+		case len(args) > 2 && args[0] == "api" && args[1] == "--hostname":
+			if args[2] == "ghe.invalid" {
+				return "", "HTTP 401: Bad credentials (https://ghe.invalid/api/v3/user)"
+			}
+
+			return "octocat", ""
+		case len(args) > 1 && args[0] == "pr" && args[1] == "view":
+			if len(args) > 2 && args[2] == "404" {
+				return "", "GraphQL: Could not resolve to a PullRequest with the number of 404."
+			}
+
+			return `{"url":"https://github.com/octocat/hello-world/pull/123"}`, ""
+		case len(args) > 2 && args[0] == "pr" && args[1] == "edit":
+			if strings.HasSuffix(args[2], "/13") {
+				return "", "GraphQL: Could not resolve to a PullRequest with the number of 13."
+			}
+
+			return args[2], ""
+		}
+
+		t.Errorf("unexpected call to gh: %v", args)
+
+		return "", ""
+	}
+}
+
+// readQueueFile returns the requests in the queue within the given directory,
+// without when they were queued so that they can be snapshotted
+func readQueueFile(t *testing.T, configDir string) []queuedRequest {
+	t.Helper()
+
+	queue, err := loadQueue(filepath.Join(configDir, "gh-rr-queue.json"))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := range queue {
+		queue[i].QueuedAt = time.Time{}
+	}
+
+	return queue
+}
+
+func Test_run_Queue(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		args  []string
+		queue string
+		exit  int
+	}{
+		{
+			name: "when queuing a request",
+			args: []string{"queue", "123"},
+			exit: 0,
+		},
+		{
+			name: "when queuing a request for the current branch",
+			args: []string{"queue", "--from", "infra"},
+			exit: 0,
+		},
+		{
+			name:  "when there are already queued requests",
+			args:  []string{"queue", "123"},
+			queue: `[{"repository":"octocat/hello-world","pullRequest":"https://github.com/octocat/hello-world/pull/1","group":"infra","reviewers":["octodog"]}]`,
+			exit:  0,
+		},
+		{
+			name: "when queuing a request for a group that is assigned",
+			args: []string{"queue", "--from", "triage", "123"},
+			exit: 0,
+		},
+		{
+			name: "when doing a dry-run",
+			args: []string{"queue", "--dry-run", "123"},
+			exit: 0,
+		},
+		{
+			name: "when the pull request cannot be found",
+			args: []string{"queue", "404"},
+			exit: 1,
+		},
+		{
+			name: "when given multiple pull requests",
+			args: []string{"queue", "1", "2"},
+			exit: 1,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			configDir := writeConfigFileInTempDir(t, dedent(t, `
+				repositories:
+					octocat/hello-world:
+						default: [octocat, octopus]
+						infra: [octodog]
+						triage:
+							reviewers: [octokitten]
+							method: assignee
+			`))
+
+			if tt.queue != "" {
+				if err := os.WriteFile(filepath.Join(configDir, "gh-rr-queue.json"), []byte(tt.queue), 0600); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+
+			var calls [][]string
+
+			got := run(
+				append([]string{"--config-dir", configDir, "--repo", "octocat/hello-world"}, tt.args...),
+				stdout,
+				stderr,
+				fakeQueueGh(t, &calls),
+			)
+
+			if got != tt.exit {
+				t.Errorf("run() = %v, want %v", got, tt.exit)
+			}
+
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stdout))
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stderr))
+			snaps.MatchJSON(t, calls)
+			snaps.MatchJSON(t, readQueueFile(t, configDir))
+		})
+	}
+}
+
+func Test_run_Flush(t *testing.T) {
+	t.Parallel()
+
+	queue := []queuedRequest{
+		{
+			Repository:  "octocat/hello-world",
+			PullRequest: "https://github.com/octocat/hello-world/pull/1",
+			Group:       "default",
+			Reviewers:   []queuedReviewer{{Handle: "octocat"}, {Handle: "octopus"}},
+		},
+		{
+			Repository:  "octocat/spoon-knife",
+			PullRequest: "https://github.com/octocat/spoon-knife/pull/2",
+			Group:       "infra",
+			Reviewers:   []queuedReviewer{{Handle: "octodog"}},
+		},
+	}
+
+	always := `
+		settings:
+			working_hours:
+				days: [monday, tuesday, wednesday, thursday, friday, saturday, sunday]
+				start: '00:00'
+				end: '24:00'
+	`
+
+	tests := []struct {
+		name   string
+		args   []string
+		config string
+		queue  []queuedRequest
+		exit   int
+	}{
+		{
+			name:   "when within working hours",
+			args:   []string{"flush"},
+			config: always,
+			queue:  queue,
+			exit:   0,
+		},
+		{
+			name: "when outside of working hours",
+			args: []string{"flush"},
+			config: `
+				settings:
+					working_hours:
+						start: '09:00'
+						end: '09:00'
+			`,
+			queue: queue,
+			exit:  0,
+		},
+		{
+			name:   "when doing a dry-run",
+			args:   []string{"flush", "--dry-run"},
+			config: always,
+			queue:  queue,
+			exit:   0,
+		},
+		{
+			name:   "when there is nothing queued",
+			args:   []string{"flush"},
+			config: always,
+			exit:   0,
+		},
+		{
+			name:   "when some requests cannot be made",
+			args:   []string{"flush"},
+			config: always,
+			queue: append(queue, queuedRequest{
+				Repository:  "octocat/hello-world",
+				PullRequest: "https://github.com/octocat/hello-world/pull/13",
+				Group:       "default",
+				Reviewers:   []queuedReviewer{{Handle: "octocat"}},
+			}),
+			exit: 1,
+		},
+		{
+			name:   "when a request is for a group that is assigned",
+			args:   []string{"flush"},
+			config: always,
+			queue: []queuedRequest{{
+				Repository:  "octocat/hello-world",
+				PullRequest: "https://github.com/octocat/hello-world/pull/3",
+				Group:       "triage",
+				Reviewers:   []queuedReviewer{{Handle: "octokitten", Method: methodAssignee}, {Handle: "octocat"}},
+			}},
+			exit: 0,
+		},
+		{
+			name:   "when requests are queued on multiple hosts",
+			args:   []string{"flush"},
+			config: always,
+			queue: append(queue, queuedRequest{
+				Host:        "ghe.example.com",
+				Repository:  "octo-org/billing",
+				PullRequest: "https://ghe.example.com/octo-org/billing/pull/3",
+				Group:       "default",
+				Reviewers:   []queuedReviewer{{Handle: "octokitten"}},
+			}),
+			exit: 0,
+		},
+		{
+			name:   "when doing a dry-run with requests queued on multiple hosts",
+			args:   []string{"flush", "--dry-run"},
+			config: always,
+			queue: append(queue, queuedRequest{
+				Host:        "ghe.example.com",
+				Repository:  "octo-org/billing",
+				PullRequest: "https://ghe.example.com/octo-org/billing/pull/3",
+				Group:       "default",
+				Reviewers:   []queuedReviewer{{Handle: "octokitten"}},
+			}),
+			exit: 0,
+		},
+		{
+			name:   "when a host cannot be authenticated with",
+			args:   []string{"flush"},
+			config: always,
+			queue: append(queue, queuedRequest{
+				Host:        "ghe.invalid",
+				Repository:  "octo-org/billing",
+				PullRequest: "https://ghe.invalid/octo-org/billing/pull/3",
+				Group:       "default",
+				Reviewers:   []queuedReviewer{{Handle: "octokitten"}},
+			}),
+			exit: 1,
+		},
+		{
+			name: "when a working day is invalid",
+			args: []string{"flush"},
+			config: `
+				settings:
+					working_hours:
+						days: [monday, funday]
+			`,
+			queue: queue,
+			exit:  1,
+		},
+		{
+			name: "when a working time is invalid",
+			args: []string{"flush"},
+			config: `
+				settings:
+					working_hours:
+						start: '9am'
+			`,
+			queue: queue,
+			exit:  1,
+		},
+		{
+			name: "when working hours end before they start",
+			args: []string{"flush"},
+			config: `
+				settings:
+					working_hours:
+						start: '17:00'
+						end: '09:00'
+			`,
+			queue: queue,
+			exit:  1,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			configDir := writeConfigFileInTempDir(t, dedent(t, tt.config+`
+				repositories:
+					octocat/hello-world:
+						- octocat
+			`))
+
+			if tt.queue != nil {
+				out, err := json.Marshal(tt.queue)
+
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if err := os.WriteFile(filepath.Join(configDir, "gh-rr-queue.json"), out, 0600); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+
+			var calls [][]string
+
+			got := run(
+				append([]string{"--config-dir", configDir}, tt.args...),
+				stdout,
+				stderr,
+				fakeQueueGh(t, &calls),
+			)
+
+			if got != tt.exit {
+				t.Errorf("run() = %v, want %v", got, tt.exit)
+			}
+
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stdout))
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stderr))
+			snaps.MatchJSON(t, calls)
+			snaps.MatchJSON(t, readQueueFile(t, configDir))
+		})
+	}
+}
+
+func Test_flushQueue_ReviewerTimezones(t *testing.T) {
+	t.Parallel()
+
+	configDir := writeConfigFileInTempDir(t, "")
+	file := filepath.Join(configDir, "gh-rr-queue.json")
+
+	err := saveQueue(file, []queuedRequest{
+		{
+			Repository:  "octocat/hello-world",
+			PullRequest: "https://github.com/octocat/hello-world/pull/1",
+			Group:       "default",
+			Reviewers: []queuedReviewer{
+				{Handle: "octocat", Timezone: "Europe/London"},
+				{Handle: "octopus", Timezone: "Pacific/Auckland"},
+				{Handle: "octodog"},
+			},
+		},
+		{
+			Repository:  "octocat/spoon-knife",
+			PullRequest: "https://github.com/octocat/spoon-knife/pull/2",
+			Group:       "infra",
+			Reviewers:   []queuedReviewer{{Handle: "octokitten", Timezone: "Pacific/Auckland"}},
+		},
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+
+	var calls [][]string
+
+	// 10am on a monday in london, which is 11pm in auckland
+	got := flushQueue(stdout, stderr, fakeQueueGh(t, &calls), flushOptions{
+		file:  file,
+		hours: defaultWorkingHours,
+		now:   time.Date(2024, time.January, 8, 10, 0, 0, 0, time.UTC),
+	})
+
+	if got != 0 {
+		t.Errorf("flushQueue() = %v, want %v", got, 0)
+	}
+
+	snaps.MatchSnapshot(t, normalizeStdStream(t, stdout))
+	snaps.MatchSnapshot(t, normalizeStdStream(t, stderr))
+	snaps.MatchJSON(t, calls)
+	snaps.MatchJSON(t, readQueueFile(t, configDir))
+}
@@ -0,0 +1,24 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// markReadyForReview marks the target pull request as ready for review, which
+// is not treated as an error if it already is
+func markReadyForReview(ghExec ghExecutor, repo, target string) error {
+	args := []string{"pr", "ready"}
+
+	if target != "" {
+		args = append(args, target)
+	}
+
+	_, errMsg := ghExec(append(args, "--repo", repo)...)
+
+	if errMsg != "" && !strings.Contains(errMsg, `is already "ready for review"`) {
+		return fmt.Errorf("could not mark the pull request as ready for review: %s", strings.TrimSpace(errMsg))
+	}
+
+	return nil
+}
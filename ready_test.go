@@ -0,0 +1,118 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+// fakeReadyGh acts as gh for a repository where marking pull request #13 as
+// ready fails, and #2 is already ready for review
+func fakeReadyGh(t *testing.T, calls *[][]string) ghExecutor {
+	t.Helper()
+
+	return func(args ...string) (string, string) {
+		t.Helper()
+
+		*calls = append(*calls, args)
+
+		switch {
+		case len(args) > 2 && args[0] == "pr" && args[1] == "ready":
+			switch args[2] {
+			case "13":
+				return "", "GraphQL: Could not resolve to a PullRequest with the number of 13."
+			case "2":
+				return "", "! Pull request octocat/hello-world#2 is already \"ready for review\"\n"
+			}
+
+			return "", ""
+		case len(args) > 2 && args[0] == "pr" && args[1] == "edit":
+			return fmt.Sprintf("https://github.com/octocat/hello-world/pull/%s", args[2]), ""
+		}
+
+		t.Errorf("unexpected call to gh: %v", args)
+
+		return "", ""
+	}
+}
+
+func Test_run_Ready(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		args []string
+		exit int
+	}{
+		{
+			name: "when marking a draft as ready",
+			args: []string{"ready", "1"},
+			exit: 0,
+		},
+		{
+			name: "when the pull request is already ready",
+			args: []string{"ready", "2"},
+			exit: 0,
+		},
+		{
+			name: "when requesting from another group",
+			args: []string{"ready", "--from", "infra", "1"},
+			exit: 0,
+		},
+		{
+			name: "when doing a dry-run",
+			args: []string{"ready", "--dry-run", "1"},
+			exit: 0,
+		},
+		{
+			name: "when the pull request cannot be marked as ready",
+			args: []string{"ready", "13"},
+			exit: 1,
+		},
+		{
+			name: "when searching",
+			args: []string{"ready", "--search", "draft:true"},
+			exit: 1,
+		},
+		{
+			name: "when using the json format",
+			args: []string{"ready", "--dry-run", "--format", "json", "1"},
+			exit: 1,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			configDir := writeConfigFileInTempDir(t, dedent(t, `
+				repositories:
+					octocat/hello-world:
+						default: [octocat, octopus]
+						infra: [octodog]
+			`))
+
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+
+			var calls [][]string
+
+			got := run(
+				append([]string{"--config-dir", configDir, "--repo", "octocat/hello-world"}, tt.args...),
+				stdout,
+				stderr,
+				fakeReadyGh(t, &calls),
+			)
+
+			if got != tt.exit {
+				t.Errorf("run() = %v, want %v", got, tt.exit)
+			}
+
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stdout))
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stderr))
+			snaps.MatchJSON(t, calls)
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+)
+
+// recordedCall is a call to gh along with what it output
+type recordedCall struct {
+	Args   []string `json:"args"`
+	Stdout string   `json:"stdout"`
+	Stderr string   `json:"stderr"`
+}
+
+// newRecordingGhExecutor returns a ghExecutor that saves every call made with
+// the given executor to the file, so that they can be replayed later
+func newRecordingGhExecutor(stderr io.Writer, file string, ghExec ghExecutor) ghExecutor {
+	var calls []recordedCall
+
+	return func(args ...string) (string, string) {
+		stdout, errMsg := ghExec(args...)
+
+		calls = append(calls, recordedCall{Args: args, Stdout: stdout, Stderr: errMsg})
+
+		// the recording is saved after every call so that nothing is lost if
+		// gh-rr exits early
+		out, err := json.MarshalIndent(calls, "", "  ")
+
+		if err == nil {
+			err = os.WriteFile(file, append(out, '\n'), 0600)
+		}
+
+		if err != nil {
+			fmt.Fprintf(stderr, "could not record call to gh: %v\n", err)
+		}
+
+		return stdout, errMsg
+	}
+}
+
+// newReplayingGhExecutor returns a ghExecutor that responds to calls using the
+// recording in the given file instead of running gh; calls with the same args
+// are replayed in the order they were recorded, with the last one repeating
+func newReplayingGhExecutor(file string) (ghExecutor, error) {
+	out, err := os.ReadFile(file)
+
+	if err != nil {
+		return nil, fmt.Errorf("could not read recording: %w", err)
+	}
+
+	var calls []recordedCall
+
+	if err := json.Unmarshal(out, &calls); err != nil {
+		return nil, fmt.Errorf("could not parse recording %s: %w", file, err)
+	}
+
+	replayed := make([]bool, len(calls))
+
+	return func(args ...string) (string, string) {
+		last := -1
+
+		for i, call := range calls {
+			if !slices.Equal(call.Args, args) {
+				continue
+			}
+
+			if !replayed[i] {
+				replayed[i] = true
+
+				return call.Stdout, call.Stderr
+			}
+
+			last = i
+		}
+
+		if last == -1 {
+			return "", fmt.Sprintf("there is no recorded response for `gh %s`", strings.Join(args, " "))
+		}
+
+		return calls[last].Stdout, calls[last].Stderr
+	}, nil
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+func Test_run_RecordAndReplay(t *testing.T) {
+	t.Parallel()
+
+	configDir := writeConfigFileInTempDir(t, dedent(t, `
+		settings:
+			on_noop: warn
+		repositories:
+			octocat/hello-world:
+				- octocat
+				- octopus
+	`))
+
+	recording := filepath.Join(t.TempDir(), "recording.json")
+
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+
+	var calls [][]string
+
+	got := run(
+		[]string{"--config-dir", configDir, "--repo", "octocat/hello-world", "--record", recording, "123"},
+		stdout,
+		stderr,
+		fakeReviewRequestsGh(t, `[{"login":"octocat"}]`, &calls),
+	)
+
+	if got != 0 {
+		t.Errorf("run() = %v, want %v", got, 0)
+	}
+
+	out, err := os.ReadFile(recording)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	snaps.MatchSnapshot(t, string(out))
+
+	stdout.Reset()
+	stderr.Reset()
+
+	got = run(
+		[]string{"--config-dir", configDir, "--repo", "octocat/hello-world", "--replay", recording, "123"},
+		stdout,
+		stderr,
+		func(args ...string) (string, string) {
+			t.Errorf("unexpected call to gh: %v", args)
+
+			return "", ""
+		},
+	)
+
+	if got != 0 {
+		t.Errorf("run() = %v, want %v", got, 0)
+	}
+
+	snaps.MatchSnapshot(t, normalizeStdStream(t, stdout))
+	snaps.MatchSnapshot(t, normalizeStdStream(t, stderr))
+}
+
+func Test_run_Replay(t *testing.T) {
+	t.Parallel()
+
+	recording := `[
+		{"args":["pr","view","123","--repo","octocat/hello-world","--json","reviewRequests"],"stdout":"{\"reviewRequests\":[]}","stderr":""},
+		{"args":["pr","edit","123","--repo","octocat/hello-world","--add-reviewer","octocat"],"stdout":"https://github.com/octocat/hello-world/pull/123","stderr":""}
+	]`
+
+	tests := []struct {
+		name      string
+		args      []string
+		recording string
+		exit      int
+	}{
+		{
+			name:      "when every call has been recorded",
+			args:      []string{"--from", "one", "123"},
+			recording: recording,
+			exit:      0,
+		},
+		{
+			name:      "when a call has not been recorded",
+			args:      []string{"--from", "two", "123"},
+			recording: recording,
+			exit:      1,
+		},
+		{
+			name:      "when the recording is invalid",
+			args:      []string{"123"},
+			recording: "{",
+			exit:      1,
+		},
+		{
+			name:      "when also recording",
+			args:      []string{"--record", "recording.json", "123"},
+			recording: recording,
+			exit:      1,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			configDir := writeConfigFileInTempDir(t, dedent(t, `
+				settings:
+					on_noop: warn
+				repositories:
+					octocat/hello-world:
+						one: [octocat]
+						two: [octocat, octopus]
+			`))
+
+			file := filepath.Join(configDir, "recording.json")
+
+			if err := os.WriteFile(file, []byte(tt.recording), 0600); err != nil {
+				t.Fatal(err)
+			}
+
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+
+			got := run(
+				append([]string{"--config-dir", configDir, "--repo", "octocat/hello-world", "--replay", file}, tt.args...),
+				stdout,
+				stderr,
+				func(args ...string) (string, string) {
+					t.Errorf("unexpected call to gh: %v", args)
+
+					return "", ""
+				},
+			)
+
+			if got != tt.exit {
+				t.Errorf("run() = %v, want %v", got, tt.exit)
+			}
+
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stdout))
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stderr))
+		})
+	}
+}
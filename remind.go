@@ -0,0 +1,436 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const defaultReminderTemplate = "{{.Reviewers}} friendly reminder that your review was requested on this pull request {{.Days}} days ago"
+
+type remindOptions struct {
+	repo       string
+	group      string
+	reviewers  []reviewer
+	days       int
+	reRequest  bool
+	template   string
+	escalation escalationTarget
+	snoozed    map[string]string
+	isDryRun   bool
+}
+
+// escalation is the group that review requests to a group should be escalated
+// to once they have gone unanswered for a number of days
+type escalation struct {
+	After int    `yaml:"after"`
+	To    string `yaml:"to"`
+}
+
+func (e *escalation) UnmarshalYAML(value *yaml.Node) error {
+	type rawEscalation escalation
+
+	if err := value.Decode((*rawEscalation)(e)); err != nil {
+		return err
+	}
+
+	if e.After < 1 {
+		return fmt.Errorf("line %d: escalation must be after at least one day, not `%d`", value.Line, e.After)
+	}
+
+	if e.To == "" {
+		return fmt.Errorf("line %d: escalation must have a group to escalate to", value.Line)
+	}
+
+	return nil
+}
+
+// escalationTarget is an escalation along with the reviewers of its group
+type escalationTarget struct {
+	escalation
+	reviewers []reviewer
+}
+
+// resolveEscalation returns the escalation of the given group (if any) along
+// with the reviewers that requests should be escalated to
+func resolveEscalation(ghExec ghExecutor, conf config, repo, group string) (escalationTarget, error) {
+	repo = strings.ToLower(repo)
+	esc := conf.Repositories[repo][group].Escalation
+
+	if esc.To == "" {
+		return escalationTarget{}, nil
+	}
+
+	reviewers, err := determineReviewers(conf, repo, esc.To)
+
+	if err != nil {
+		return escalationTarget{}, fmt.Errorf("the %s group escalates to the %s group, which does not exist", group, esc.To)
+	}
+
+	reviewers, err = expandDynamicReviewers(ghExec, reviewers)
+
+	if err != nil {
+		return escalationTarget{}, err
+	}
+
+	return escalationTarget{esc, reviewers}, nil
+}
+
+// openPullRequest is an open pull request as returned by `gh pr list --json`
+type openPullRequest struct {
+	pullRequest
+	Number int `json:"number"`
+}
+
+// staleRequest is a pull request with review requests that have gone unanswered
+type staleRequest struct {
+	pr        openPullRequest
+	reviewers []reviewer
+
+	// since is when the oldest of the unanswered reviews was requested
+	since time.Time
+
+	// requestedAt is when a review was last requested from each of the reviewers
+	requestedAt map[string]time.Time
+}
+
+// overdue returns the request with only the reviewers whose reviews are
+// overdue, along with if there are any such reviewers
+func (r staleRequest) overdue(isOverdue func(requestedAt time.Time) bool) (staleRequest, bool) {
+	request := staleRequest{pr: r.pr, requestedAt: r.requestedAt}
+
+	for _, reviewer := range r.reviewers {
+		requestedAt, ok := r.requestedAt[strings.ToLower(reviewer.Handle)]
+
+		if !ok || !isOverdue(requestedAt) {
+			continue
+		}
+
+		request.reviewers = append(request.reviewers, reviewer)
+
+		if request.since.IsZero() || requestedAt.Before(request.since) {
+			request.since = requestedAt
+		}
+	}
+
+	return request, len(request.reviewers) > 0
+}
+
+// daysOverdue returns a check for if a review was requested at least the given
+// number of days ago
+func daysOverdue(now time.Time, days int) func(requestedAt time.Time) bool {
+	return func(requestedAt time.Time) bool {
+		return int(now.Sub(requestedAt).Hours()/24) >= days
+	}
+}
+
+// daysWaited returns the number of whole days since the oldest of the
+// unanswered reviews was requested
+func (r staleRequest) daysWaited(now time.Time) int {
+	return int(now.Sub(r.since).Hours() / 24)
+}
+
+// fetchReviewRequestTimes returns when a review was last requested from each
+// user and team on the given pull request, based on its timeline
+func fetchReviewRequestTimes(ghExec ghExecutor, repo string, number int) (map[string]time.Time, error) {
+	out, errMsg := ghExec("api", fmt.Sprintf("repos/%s/issues/%d/timeline", repo, number), "--paginate")
+
+	if errMsg != "" {
+		return nil, fmt.Errorf("could not get the timeline of #%d: %s", number, strings.TrimSpace(errMsg))
+	}
+
+	events, err := decodePages[struct {
+		Event             string    `json:"event"`
+		CreatedAt         time.Time `json:"created_at"`
+		RequestedReviewer struct {
+			Login string `json:"login"`
+		} `json:"requested_reviewer"`
+		RequestedTeam struct {
+			Slug string `json:"slug"`
+		} `json:"requested_team"`
+	}](out)
+
+	if err != nil {
+		return nil, fmt.Errorf("could not parse the timeline of #%d: %w", number, err)
+	}
+
+	times := make(map[string]time.Time)
+
+	for _, event := range events {
+		if event.Event != "review_requested" {
+			continue
+		}
+
+		handle := strings.ToLower(event.RequestedReviewer.Login)
+
+		// teams are only given by their slug, as they belong to the owner of
+		// the repository
+		if event.RequestedTeam.Slug != "" {
+			handle = ownerOf(repo) + "/" + strings.ToLower(event.RequestedTeam.Slug)
+		}
+
+		if event.CreatedAt.After(times[handle]) {
+			times[handle] = event.CreatedAt
+		}
+	}
+
+	return times, nil
+}
+
+// findStaleRequests returns the open pull requests in the repository that have
+// had a review requested from a member of the group which is overdue
+func findStaleRequests(ghExec ghExecutor, repo string, reviewers []reviewer, isOverdue func(requestedAt time.Time) bool) ([]staleRequest, error) {
+	out, errMsg := ghExec("pr", "list", "--repo", repo, "--state", "open", "--json", "number,url,reviewRequests")
+
+	if errMsg != "" {
+		return nil, fmt.Errorf("could not list pull requests: %s", strings.TrimSpace(errMsg))
+	}
+
+	var prs []openPullRequest
+
+	if err := json.Unmarshal([]byte(out), &prs); err != nil {
+		return nil, fmt.Errorf("could not parse pull requests: %w", err)
+	}
+
+	var stale []staleRequest
+
+	for _, pr := range prs {
+		var pending []reviewer
+
+		for _, reviewer := range reviewers {
+			if pr.hasRequestedReviewFrom(reviewer.Handle) {
+				pending = append(pending, reviewer)
+			}
+		}
+
+		if len(pending) == 0 {
+			continue
+		}
+
+		times, err := fetchReviewRequestTimes(ghExec, repo, pr.Number)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if request, ok := (staleRequest{pr: pr, reviewers: pending, requestedAt: times}).overdue(isOverdue); ok {
+			stale = append(stale, request)
+		}
+	}
+
+	return stale, nil
+}
+
+// buildReminder renders the comment used to remind reviewers about a request
+func buildReminder(tmpl string, group string, request staleRequest, now time.Time) (string, error) {
+	if tmpl == "" {
+		tmpl = defaultReminderTemplate
+	}
+
+	t, err := template.New("reminder").Parse(tmpl)
+
+	if err != nil {
+		return "", fmt.Errorf("could not parse reminder template: %w", err)
+	}
+
+	mentions := make([]string, 0, len(request.reviewers))
+
+	for _, reviewer := range request.reviewers {
+		mentions = append(mentions, "@"+reviewer.Handle)
+	}
+
+	var sb strings.Builder
+
+	err = t.Execute(&sb, struct {
+		Reviewers string
+		Group     string
+		Days      int
+		URL       string
+	}{
+		Reviewers: strings.Join(mentions, ", "),
+		Group:     group,
+		Days:      request.daysWaited(now),
+		URL:       request.pr.URL,
+	})
+
+	if err != nil {
+		return "", fmt.Errorf("could not render reminder template: %w", err)
+	}
+
+	return sb.String(), nil
+}
+
+// reRequestReviews removes and then re-adds the given reviewers, so that they
+// are notified about the pull request again
+func reRequestReviews(ghExec ghExecutor, repo string, request staleRequest) error {
+	number := fmt.Sprint(request.pr.Number)
+
+	for _, chunk := range chunkReviewers(request.reviewers) {
+		handles := make([]string, 0, len(chunk))
+
+		for _, reviewer := range chunk {
+			handles = append(handles, reviewer.Handle)
+		}
+
+		if _, errMsg := ghExec("pr", "edit", number, "--repo", repo, "--remove-reviewer", strings.Join(handles, ",")); errMsg != "" {
+			return fmt.Errorf("could not re-request reviews on %s: %s", request.pr.URL, strings.TrimSpace(errMsg))
+		}
+	}
+
+	if _, err := attachReviewers(ghExec, repo, number, request.reviewers, ""); err != nil {
+		return fmt.Errorf("could not re-request reviews on %s: %w", request.pr.URL, err)
+	}
+
+	return nil
+}
+
+// remind posts a comment on each pull request that has review requests to
+// members of the group which have gone unanswered for too long
+func remind(stdout, stderr io.Writer, ghExec ghExecutor, opts remindOptions) int {
+	now := time.Now()
+	isReminderOverdue := daysOverdue(now, opts.days)
+	isEscalationOverdue := daysOverdue(now, opts.escalation.After)
+
+	// the pull requests are only scanned once, for both reminding and escalating
+	stale, err := findStaleRequests(ghExec, opts.repo, opts.reviewers, func(requestedAt time.Time) bool {
+		return isReminderOverdue(requestedAt) || (opts.escalation.To != "" && isEscalationOverdue(requestedAt))
+	})
+
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+
+		return 1
+	}
+
+	var reminders []staleRequest
+
+	for _, request := range stale {
+		if request, ok := request.overdue(isReminderOverdue); ok {
+			reminders = append(reminders, request)
+		}
+	}
+
+	if len(reminders) == 0 {
+		fmt.Fprintf(stdout, "there are no review requests to the %s group that have been waiting for %d or more days\n", opts.group, opts.days)
+	}
+
+	for _, request := range reminders {
+		if isSnoozed(opts.snoozed, opts.repo, request.pr.Number, now) {
+			fmt.Fprintf(stdout, "not reminding reviewers on %s as it has been snoozed until %s\n", request.pr.URL, opts.snoozed[snoozeKey(opts.repo, request.pr.Number)])
+
+			continue
+		}
+
+		comment, err := buildReminder(opts.template, opts.group, request, now)
+
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+
+			return 1
+		}
+
+		if opts.isDryRun {
+			fmt.Fprintf(stdout, "would have reminded reviewers on %s with:\n  %s\n", request.pr.URL, comment)
+
+			continue
+		}
+
+		if _, errMsg := ghExec("pr", "comment", fmt.Sprint(request.pr.Number), "--repo", opts.repo, "--body", comment); errMsg != "" {
+			fmt.Fprintf(stderr, "could not comment on %s: %s\n", request.pr.URL, strings.TrimSpace(errMsg))
+
+			return 1
+		}
+
+		if opts.reRequest {
+			if err := reRequestReviews(ghExec, opts.repo, request); err != nil {
+				fmt.Fprintln(stderr, err)
+
+				return 1
+			}
+		}
+
+		fmt.Fprintf(stdout, "reminded reviewers on %s:\n", request.pr.URL)
+
+		for _, reviewer := range request.reviewers {
+			fmt.Fprintf(stdout, "  - %s\n", reviewer)
+		}
+	}
+
+	if opts.escalation.To == "" {
+		return 0
+	}
+
+	return escalate(stdout, stderr, ghExec, opts, stale, now)
+}
+
+// buildEscalation renders the comment used to explain why a request is being
+// escalated to another group
+func buildEscalation(group string, esc escalationTarget, request staleRequest, now time.Time) string {
+	mentions := make([]string, 0, len(esc.reviewers))
+
+	for _, reviewer := range esc.reviewers {
+		mentions = append(mentions, "@"+reviewer.Handle)
+	}
+
+	return fmt.Sprintf(
+		"%s this has been waiting on a review from the %s group for %d days, so is being escalated to the %s group",
+		strings.Join(mentions, ", "),
+		group,
+		request.daysWaited(now),
+		esc.To,
+	)
+}
+
+// escalate requests reviews from the escalation group of the group on each of
+// the stale pull requests with review requests to the group that have gone
+// unanswered for long enough, commenting to explain why
+func escalate(stdout, stderr io.Writer, ghExec ghExecutor, opts remindOptions, stale []staleRequest, now time.Time) int {
+	for _, request := range stale {
+		request, ok := request.overdue(daysOverdue(now, opts.escalation.After))
+
+		if !ok {
+			continue
+		}
+
+		// requests that have already been escalated do not need escalating again,
+		// and snoozed requests will have already been reported as being snoozed
+		if hasRequestedReviewFromAll(request.pr.pullRequest, opts.escalation.reviewers) || isSnoozed(opts.snoozed, opts.repo, request.pr.Number, now) {
+			continue
+		}
+
+		comment := buildEscalation(opts.group, opts.escalation, request, now)
+
+		if opts.isDryRun {
+			fmt.Fprintf(stdout, "would have escalated review requests on %s to the %s group with:\n  %s\n", request.pr.URL, opts.escalation.To, comment)
+
+			continue
+		}
+
+		number := fmt.Sprint(request.pr.Number)
+
+		if _, err := attachReviewers(ghExec, opts.repo, number, opts.escalation.reviewers, ""); err != nil {
+			fmt.Fprintf(stderr, "could not escalate review requests on %s: %v\n", request.pr.URL, err)
+
+			return 1
+		}
+
+		if _, errMsg := ghExec("pr", "comment", number, "--repo", opts.repo, "--body", comment); errMsg != "" {
+			fmt.Fprintf(stderr, "could not comment on %s: %s\n", request.pr.URL, strings.TrimSpace(errMsg))
+
+			return 1
+		}
+
+		fmt.Fprintf(stdout, "escalated review requests on %s to the %s group:\n", request.pr.URL, opts.escalation.To)
+
+		for _, reviewer := range opts.escalation.reviewers {
+			fmt.Fprintf(stdout, "  - %s\n", reviewer)
+		}
+	}
+
+	return 0
+}
@@ -0,0 +1,391 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+// requestedDaysAgo returns a timeline event for a review that was requested
+// from the given login the given number of days ago
+func requestedDaysAgo(login string, days int) string {
+	createdAt := time.Now().Add(-time.Duration(days)*24*time.Hour - time.Hour)
+
+	return fmt.Sprintf(
+		`{"event":"review_requested","created_at":%q,"requested_reviewer":{"login":%q}}`,
+		createdAt.UTC().Format(time.RFC3339),
+		login,
+	)
+}
+
+// teamRequestedDaysAgo returns a timeline event for a review that was requested
+// from the given team the given number of days ago
+func teamRequestedDaysAgo(slug string, days int) string {
+	createdAt := time.Now().Add(-time.Duration(days)*24*time.Hour - time.Hour)
+
+	return fmt.Sprintf(
+		`{"event":"review_requested","created_at":%q,"requested_team":{"slug":%q}}`,
+		createdAt.UTC().Format(time.RFC3339),
+		slug,
+	)
+}
+
+// fakeRemindGh acts as gh for a repository with the given open pull requests
+// and pages of timeline events for each of them
+func fakeRemindGh(t *testing.T, prs string, timelines map[string]string, calls *[][]string) ghExecutor {
+	t.Helper()
+
+	return func(args ...string) (string, string) {
+		t.Helper()
+
+		*calls = append(*calls, args)
+
+		switch {
+		case len(args) > 1 && args[0] == "pr" && args[1] == "list":
+			return prs, ""
+		case len(args) > 1 && args[0] == "api":
+			number := strings.TrimSuffix(strings.TrimPrefix(args[1], "repos/octocat/hello-world/issues/"), "/timeline")
+
+			return timelines[number], ""
+		case len(args) > 2 && args[0] == "pr" && args[1] == "comment":
+			if args[2] == "13" {
+				return "", "GraphQL: Could not resolve to a PullRequest with the number of 13."
+			}
+
+			return fmt.Sprintf("https://github.com/octocat/hello-world/pull/%s#issuecomment-1", args[2]), ""
+		case len(args) > 2 && args[0] == "pr" && args[1] == "edit":
+			return fmt.Sprintf("https://github.com/octocat/hello-world/pull/%s", args[2]), ""
+		}
+
+		t.Errorf("unexpected call to gh: %v", args)
+
+		return "", ""
+	}
+}
+
+func Test_run_Remind(t *testing.T) {
+	t.Parallel()
+
+	prs := `[
+		{"number":1,"url":"https://github.com/octocat/hello-world/pull/1","reviewRequests":[{"login":"octocat"},{"login":"octopus"}]},
+		{"number":2,"url":"https://github.com/octocat/hello-world/pull/2","reviewRequests":[{"login":"octodog"}]},
+		{"number":3,"url":"https://github.com/octocat/hello-world/pull/3","reviewRequests":[{"login":"octocat"}]},
+		{"number":4,"url":"https://github.com/octocat/hello-world/pull/4","reviewRequests":[]}
+	]`
+
+	timelines := map[string]string{
+		"1": "[" + requestedDaysAgo("octocat", 5) + "," + requestedDaysAgo("octopus", 3) + "]",
+		"3": "[" + requestedDaysAgo("octocat", 4) + `,{"event":"commented"}]` +
+			"[" + requestedDaysAgo("octocat", 1) + "]",
+	}
+
+	tests := []struct {
+		name   string
+		args   []string
+		config string
+		prs    string
+		exit   int
+	}{
+		{
+			name: "when there are stale review requests",
+			args: []string{"remind"},
+			prs:  prs,
+			exit: 0,
+		},
+		{
+			name: "when using a longer threshold",
+			args: []string{"remind", "--days", "4"},
+			prs:  prs,
+			exit: 0,
+		},
+		{
+			name: "when re-requesting reviews",
+			args: []string{"remind", "--days", "4", "--re-request"},
+			prs:  prs,
+			exit: 0,
+		},
+		{
+			name: "when using a custom template",
+			args: []string{"remind"},
+			config: `
+				settings:
+					reminder_template: 'ping {{.Reviewers}} ({{.Group}} group, {{.Days}} days)'
+			`,
+			prs:  prs,
+			exit: 0,
+		},
+		{
+			name: "when the template is invalid",
+			args: []string{"remind"},
+			config: `
+				settings:
+					reminder_template: 'ping {{.Reviewers'
+			`,
+			prs:  prs,
+			exit: 1,
+		},
+		{
+			name: "when doing a dry-run",
+			args: []string{"remind", "--dry-run"},
+			prs:  prs,
+			exit: 0,
+		},
+		{
+			name: "when there are no stale review requests",
+			args: []string{"remind", "--days", "10"},
+			prs:  prs,
+			exit: 0,
+		},
+		{
+			name: "when a comment cannot be posted",
+			args: []string{"remind"},
+			prs:  `[{"number":13,"url":"https://github.com/octocat/hello-world/pull/13","reviewRequests":[{"login":"octocat"}]}]`,
+			exit: 1,
+		},
+		{
+			name: "when pull requests are given",
+			args: []string{"remind", "1"},
+			prs:  prs,
+			exit: 1,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			configDir := writeConfigFileInTempDir(t, dedent(t, tt.config+`
+				repositories:
+					octocat/hello-world:
+						- octocat
+						- octopus
+			`))
+
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+
+			var calls [][]string
+
+			timelines := timelines
+
+			if tt.prs != prs {
+				timelines = map[string]string{"13": "[" + requestedDaysAgo("octocat", 3) + "]"}
+			}
+
+			got := run(
+				append([]string{"--config-dir", configDir, "--repo", "octocat/hello-world"}, tt.args...),
+				stdout,
+				stderr,
+				fakeRemindGh(t, tt.prs, timelines, &calls),
+			)
+
+			if got != tt.exit {
+				t.Errorf("run() = %v, want %v", got, tt.exit)
+			}
+
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stdout))
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stderr))
+			snaps.MatchJSON(t, calls)
+		})
+	}
+}
+
+func Test_run_Remind_Escalation(t *testing.T) {
+	t.Parallel()
+
+	prs := `[
+		{"number":1,"url":"https://github.com/octocat/hello-world/pull/1","reviewRequests":[{"login":"octocat"},{"login":"octopus"}]},
+		{"number":2,"url":"https://github.com/octocat/hello-world/pull/2","reviewRequests":[{"login":"octopus"}]},
+		{"number":3,"url":"https://github.com/octocat/hello-world/pull/3","reviewRequests":[{"login":"octocat"},{"login":"octodog"}]}
+	]`
+
+	timelines := map[string]string{
+		"1": "[" + requestedDaysAgo("octocat", 5) + "," + requestedDaysAgo("octopus", 3) + "]",
+		"2": "[" + requestedDaysAgo("octopus", 2) + "]",
+		"3": "[" + requestedDaysAgo("octocat", 6) + "," + requestedDaysAgo("octodog", 1) + "]",
+	}
+
+	tests := []struct {
+		name   string
+		args   []string
+		config string
+		exit   int
+	}{
+		{
+			name: "when there are requests to escalate",
+			args: []string{"remind"},
+			config: `
+				repositories:
+					octocat/hello-world:
+						default:
+							reviewers: [octocat, octopus]
+							escalation:
+								after: 4
+								to: leads
+						leads: [octodog]
+			`,
+			exit: 0,
+		},
+		{
+			name: "when doing a dry-run",
+			args: []string{"remind", "--dry-run"},
+			config: `
+				repositories:
+					octocat/hello-world:
+						default:
+							reviewers: [octocat, octopus]
+							escalation:
+								after: 4
+								to: leads
+						leads: [octodog]
+			`,
+			exit: 0,
+		},
+		{
+			name: "when there are no requests to escalate",
+			args: []string{"remind", "--days", "10"},
+			config: `
+				repositories:
+					octocat/hello-world:
+						default:
+							reviewers: [octocat, octopus]
+							escalation:
+								after: 10
+								to: leads
+						leads: [octodog]
+			`,
+			exit: 0,
+		},
+		{
+			name: "when escalating sooner than reminding",
+			args: []string{"remind", "--days", "10"},
+			config: `
+				repositories:
+					octocat/hello-world:
+						default:
+							reviewers: [octocat, octopus]
+							escalation:
+								after: 4
+								to: leads
+						leads: [octodog]
+			`,
+			exit: 0,
+		},
+		{
+			name: "when the escalation group does not exist",
+			args: []string{"remind"},
+			config: `
+				repositories:
+					octocat/hello-world:
+						default:
+							reviewers: [octocat, octopus]
+							escalation:
+								after: 4
+								to: leads
+			`,
+			exit: 1,
+		},
+		{
+			name: "when the escalation is not after any days",
+			args: []string{"remind"},
+			config: `
+				repositories:
+					octocat/hello-world:
+						default:
+							reviewers: [octocat, octopus]
+							escalation:
+								after: 0
+								to: leads
+						leads: [octodog]
+			`,
+			exit: 1,
+		},
+		{
+			name: "when the escalation does not have a group",
+			args: []string{"remind"},
+			config: `
+				repositories:
+					octocat/hello-world:
+						default:
+							reviewers: [octocat, octopus]
+							escalation:
+								after: 4
+						leads: [octodog]
+			`,
+			exit: 1,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			configDir := writeConfigFileInTempDir(t, dedent(t, tt.config))
+
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+
+			var calls [][]string
+
+			got := run(
+				append([]string{"--config-dir", configDir, "--repo", "octocat/hello-world"}, tt.args...),
+				stdout,
+				stderr,
+				fakeRemindGh(t, prs, timelines, &calls),
+			)
+
+			if got != tt.exit {
+				t.Errorf("run() = %v, want %v", got, tt.exit)
+			}
+
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stdout))
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stderr))
+			snaps.MatchJSON(t, calls)
+		})
+	}
+}
+
+func Test_run_Remind_Teams(t *testing.T) {
+	t.Parallel()
+
+	prs := `[
+		{"number":1,"url":"https://github.com/octocat/hello-world/pull/1","reviewRequests":[{"slug":"octocat/security"},{"login":"octopus"}]},
+		{"number":2,"url":"https://github.com/octocat/hello-world/pull/2","reviewRequests":[{"slug":"octocat/security"}]}
+	]`
+
+	timelines := map[string]string{
+		"1": "[" + teamRequestedDaysAgo("security", 5) + "," + requestedDaysAgo("octopus", 1) + "]",
+		"2": "[" + teamRequestedDaysAgo("security", 1) + "]",
+	}
+
+	configDir := writeConfigFileInTempDir(t, dedent(t, `
+		repositories:
+			octocat/hello-world:
+				- octocat/security
+				- octopus
+	`))
+
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+
+	var calls [][]string
+
+	got := run(
+		[]string{"--config-dir", configDir, "--repo", "octocat/hello-world", "remind"},
+		stdout,
+		stderr,
+		fakeRemindGh(t, prs, timelines, &calls),
+	)
+
+	if got != 0 {
+		t.Errorf("run() = %v, want %v", got, 0)
+	}
+
+	snaps.MatchSnapshot(t, normalizeStdStream(t, stdout))
+	snaps.MatchSnapshot(t, normalizeStdStream(t, stderr))
+	snaps.MatchJSON(t, calls)
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// remoteConfigTimeout is how long a remote config has to respond before giving
+// up on it
+const remoteConfigTimeout = 10 * time.Second
+
+// isRemoteConfig checks if the config is a url that should be downloaded,
+// rather than a path to a local file
+func isRemoteConfig(file string) bool {
+	return strings.HasPrefix(file, "https://") || strings.HasPrefix(file, "http://")
+}
+
+// remoteConfigCachePath returns the path that the config at the given url is
+// downloaded to, keeping the extension so that json configs are still parsed
+// as json
+func remoteConfigCachePath(configDir, rawURL string) string {
+	sum := sha256.Sum256([]byte(rawURL))
+	ext := ".yml"
+
+	if u, err := url.Parse(rawURL); err == nil && isJSONConfig(u.Path) {
+		ext = path.Ext(u.Path)
+	}
+
+	return filepath.Join(configDir, ".gh-rr-cache", hex.EncodeToString(sum[:8])+ext)
+}
+
+// downloadRemoteConfig writes the config at the given url to the given file
+func downloadRemoteConfig(rawURL, file string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), remoteConfigTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+
+	if err != nil {
+		return err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("%s", resp.Status)
+	}
+
+	out, err := io.ReadAll(resp.Body)
+
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(file), 0700); err != nil {
+		return err
+	}
+
+	return os.WriteFile(file, out, 0600)
+}
+
+// fetchRemoteConfig downloads the config at the given url, returning the path
+// of the local copy; the copy is reused if it was downloaded within the ttl,
+// or if the config cannot currently be downloaded
+func fetchRemoteConfig(stderr io.Writer, configDir, rawURL string, ttl time.Duration) (string, error) {
+	file := remoteConfigCachePath(configDir, rawURL)
+	info, statErr := os.Stat(file)
+
+	if statErr == nil && ttl > 0 && time.Since(info.ModTime()) < ttl {
+		return file, nil
+	}
+
+	if err := downloadRemoteConfig(rawURL, file); err != nil {
+		if statErr == nil {
+			fmt.Fprintf(stderr, "warning: could not download %s, so using the last downloaded copy: %v\n", rawURL, err)
+
+			return file, nil
+		}
+
+		return "", fmt.Errorf("could not download %s: %w", rawURL, err)
+	}
+
+	return file, nil
+}
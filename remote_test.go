@@ -0,0 +1,152 @@
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+// newRemoteConfigServer serves the given configs by path, responding with an
+// error for any other path
+func newRemoteConfigServer(t *testing.T, configs map[string]string) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		config, ok := configs[r.URL.Path]
+
+		if !ok {
+			w.WriteHeader(http.StatusInternalServerError)
+
+			return
+		}
+
+		_, _ = w.Write([]byte(config))
+	}))
+
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func Test_run_WithRemoteConfig(t *testing.T) {
+	t.Parallel()
+
+	configs := map[string]string{
+		"/gh-rr.yml": dedent(t, `
+			repositories:
+				octocat/hello-world:
+					- octocat
+					- octopus
+		`),
+		"/gh-rr.json": `{"repositories": {"octocat/hello-world": ["octodog"]}}`,
+	}
+
+	tests := []struct {
+		name   string
+		args   []string
+		path   string
+		cached string
+		exit   int
+	}{
+		{
+			name:   "when the config can be downloaded",
+			args:   []string{"123"},
+			path:   "/gh-rr.yml",
+			cached: "",
+			exit:   0,
+		},
+		{
+			name:   "when the config is json",
+			args:   []string{"123"},
+			path:   "/gh-rr.json",
+			cached: "",
+			exit:   0,
+		},
+		{
+			name:   "when the config has been downloaded within the ttl",
+			args:   []string{"--config-ttl", "1h", "123"},
+			path:   "/gh-rr.yml",
+			cached: "repositories: {octocat/hello-world: [octokitten]}",
+			exit:   0,
+		},
+		{
+			name:   "when the config has been downloaded outside of the ttl",
+			args:   []string{"--config-ttl", "1m", "123"},
+			path:   "/gh-rr.yml",
+			cached: "repositories: {octocat/hello-world: [octokitten]}",
+			exit:   0,
+		},
+		{
+			name:   "when the config cannot be downloaded but has been before",
+			args:   []string{"123"},
+			path:   "/missing.yml",
+			cached: "repositories: {octocat/hello-world: [octokitten]}",
+			exit:   0,
+		},
+		{
+			name:   "when the config cannot be downloaded",
+			args:   []string{"123"},
+			path:   "/missing.yml",
+			cached: "",
+			exit:   1,
+		},
+		{
+			name:   "when editing the config",
+			args:   []string{"offboard", "octocat"},
+			path:   "/gh-rr.yml",
+			cached: "",
+			exit:   1,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			server := newRemoteConfigServer(t, configs)
+			configDir := writeConfigFileInTempDir(t, "")
+			url := server.URL + tt.path
+
+			if tt.cached != "" {
+				file := remoteConfigCachePath(configDir, url)
+				writtenAt := time.Now().Add(-10 * time.Minute)
+
+				if err := os.MkdirAll(filepath.Dir(file), 0700); err != nil {
+					t.Fatalf("could not create cache directory: %v", err)
+				}
+
+				if err := os.WriteFile(file, []byte(tt.cached), 0600); err != nil {
+					t.Fatalf("could not write cached config: %v", err)
+				}
+
+				if err := os.Chtimes(file, writtenAt, writtenAt); err != nil {
+					t.Fatalf("could not change time of cached config: %v", err)
+				}
+			}
+
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+
+			got := run(
+				append([]string{"--config-dir", configDir, "--config", url, "--repo", "octocat/hello-world", "--dry-run"}, tt.args...),
+				stdout,
+				stderr,
+				expectNoCallToGh(t),
+			)
+
+			if got != tt.exit {
+				t.Errorf("run() = %v, want %v", got, tt.exit)
+			}
+
+			snaps.MatchSnapshot(t, strings.ReplaceAll(normalizeStdStream(t, stdout), server.URL, "<server>"))
+			snaps.MatchSnapshot(t, strings.ReplaceAll(normalizeStdStream(t, stderr), server.URL, "<server>"))
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/cli/go-gh/v2/pkg/repository"
+)
+
+// gitOutput runs git in the given directory (or the current one if empty),
+// returning its trimmed output
+func gitOutput(dir string, args ...string) (string, error) {
+	if dir != "" {
+		args = append([]string{"-C", dir}, args...)
+	}
+
+	out, err := exec.Command("git", args...).Output()
+
+	return string(bytes.TrimSpace(out)), err
+}
+
+// resolvedDefaultRepository returns the repository that has been picked as the
+// default with `gh repo set-default` for the git repository in the given
+// directory, which gh records as the "gh-resolved" config of the remote
+func resolvedDefaultRepository(dir string) (repository.Repository, bool) {
+	out, err := gitOutput(dir, "config", "--get-regexp", `^remote\..*\.gh-resolved$`)
+
+	if err != nil || out == "" {
+		return repository.Repository{}, false
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(out))
+
+	for scanner.Scan() {
+		key, resolved, _ := strings.Cut(scanner.Text(), " ")
+		remote := strings.TrimSuffix(strings.TrimPrefix(key, "remote."), ".gh-resolved")
+
+		remoteURL, err := gitOutput(dir, "remote", "get-url", remote)
+
+		if err != nil {
+			continue
+		}
+
+		repo, err := repository.Parse(remoteURL)
+
+		if err != nil {
+			continue
+		}
+
+		// the default can also be a repository that is not one of the remotes,
+		// in which case it is on the same host as the remote it is recorded on
+		if resolved != "base" {
+			owner, name, found := strings.Cut(resolved, "/")
+
+			if !found {
+				continue
+			}
+
+			repo.Owner = owner
+			repo.Name = name
+		}
+
+		return repo, true
+	}
+
+	return repository.Repository{}, false
+}
+
+// currentRepository returns the repository that gh-rr is being run within,
+// preferring the default that has been picked with `gh repo set-default` over
+// the first remote, the same as gh does
+func currentRepository() (repository.Repository, error) {
+	if os.Getenv("GH_REPO") == "" {
+		if repo, ok := resolvedDefaultRepository(""); ok {
+			return repo, nil
+		}
+	}
+
+	return repository.Current()
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+// initGitRepository creates a git repository with the given remotes, running
+// the given git commands within it afterwards
+func initGitRepository(t *testing.T, remotes map[string]string, commands ...[]string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+
+	setup := [][]string{{"init", "--quiet"}}
+
+	for name, url := range remotes {
+		setup = append(setup, []string{"remote", "add", name, url})
+	}
+
+	setup = append(setup, []string{"-c", "user.name=octocat", "-c", "user.email=octocat@example.com", "commit", "--quiet", "--allow-empty", "-m", "init"})
+	commands = append(setup, commands...)
+
+	for _, args := range commands {
+		if out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput(); err != nil {
+			t.Fatalf("could not run git %v: %v\n%s", args, err, out)
+		}
+	}
+
+	return dir
+}
+
+func Test_resolvedDefaultRepository(t *testing.T) {
+	t.Parallel()
+
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	remotes := map[string]string{
+		"origin":   "git@github.com:octokitten/hello-world.git",
+		"upstream": "https://github.example.com/octocat/hello-world.git",
+	}
+
+	tests := []struct {
+		name     string
+		commands [][]string
+		subdir   string
+		want     string
+		wantOk   bool
+	}{
+		{
+			name:     "when no default has been set",
+			commands: nil,
+			want:     "",
+			wantOk:   false,
+		},
+		{
+			name:     "when a remote is the default",
+			commands: [][]string{{"config", "remote.upstream.gh-resolved", "base"}},
+			want:     "github.example.com/octocat/hello-world",
+			wantOk:   true,
+		},
+		{
+			name:     "when another repository is the default",
+			commands: [][]string{{"config", "remote.origin.gh-resolved", "octo-org/hello-world"}},
+			want:     "github.com/octo-org/hello-world",
+			wantOk:   true,
+		},
+		{
+			name:     "when within a subdirectory",
+			commands: [][]string{{"config", "remote.upstream.gh-resolved", "base"}},
+			subdir:   filepath.Join("a", "deeply", "nested", "directory"),
+			want:     "github.example.com/octocat/hello-world",
+			wantOk:   true,
+		},
+		{
+			name: "when within a linked worktree",
+			commands: [][]string{
+				{"config", "remote.upstream.gh-resolved", "base"},
+				{"worktree", "add", "--quiet", "-b", "feature", "worktree"},
+			},
+			subdir: "worktree",
+			want:   "github.example.com/octocat/hello-world",
+			wantOk: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			dir := filepath.Join(initGitRepository(t, remotes, tt.commands...), tt.subdir)
+
+			if err := os.MkdirAll(dir, 0700); err != nil {
+				t.Fatalf("could not create %s: %v", dir, err)
+			}
+
+			repo, ok := resolvedDefaultRepository(dir)
+			got := ""
+
+			if ok {
+				got = repo.Host + "/" + repo.Owner + "/" + repo.Name
+			}
+
+			if got != tt.want || ok != tt.wantOk {
+				t.Errorf("resolvedDefaultRepository() = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
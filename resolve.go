@@ -0,0 +1,104 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+type resolveOptions struct {
+	repo            string
+	group           string
+	reviewers       []reviewer
+	alwaysRequested map[string]bool
+	format          string
+}
+
+// resolvedReviewer is a reviewer that reviews would be requested from, along
+// with how they would be requested
+type resolvedReviewer struct {
+	Handle          string        `json:"handle"`
+	Name            string        `json:"name,omitempty"`
+	Chat            string        `json:"chat,omitempty"`
+	Timezone        string        `json:"tz,omitempty"`
+	Method          requestMethod `json:"method"`
+	AlwaysRequested bool          `json:"alwaysRequested"`
+}
+
+// resolution is who reviews would be requested from for a group, for use by
+// other tools that want to route reviews the same way
+type resolution struct {
+	Repository string             `json:"repository"`
+	Group      string             `json:"group"`
+	Reviewers  []resolvedReviewer `json:"reviewers"`
+}
+
+func newResolution(opts resolveOptions) resolution {
+	res := resolution{
+		Repository: opts.repo,
+		Group:      opts.group,
+		Reviewers:  make([]resolvedReviewer, 0, len(opts.reviewers)),
+	}
+
+	for _, r := range opts.reviewers {
+		method := r.method
+
+		if method == "" {
+			method = methodReviewer
+		}
+
+		res.Reviewers = append(res.Reviewers, resolvedReviewer{
+			Handle:          r.Handle,
+			Name:            r.Name,
+			Chat:            r.Chat,
+			Timezone:        r.Timezone,
+			Method:          method,
+			AlwaysRequested: opts.alwaysRequested[strings.ToLower(r.Handle)],
+		})
+	}
+
+	return res
+}
+
+// printResolution outputs who reviews would be requested from, without looking
+// at or changing any pull requests
+func printResolution(stdout, stderr io.Writer, opts resolveOptions) int {
+	res := newResolution(opts)
+
+	if opts.format == "json" {
+		out, err := json.MarshalIndent(res, "", "  ")
+
+		if err != nil {
+			fmt.Fprintf(stderr, "could not marshal resolution: %v\n", err)
+
+			return 1
+		}
+
+		fmt.Fprintln(stdout, string(out))
+
+		return 0
+	}
+
+	fmt.Fprintf(stdout, "reviews would be requested on %s from the %s group:\n", opts.repo, opts.group)
+
+	for _, reviewer := range opts.reviewers {
+		var notes []string
+
+		if opts.alwaysRequested[strings.ToLower(reviewer.Handle)] {
+			notes = append(notes, "always requested")
+		}
+
+		if note := describeMethod(reviewer); note != "" {
+			notes = append(notes, note)
+		}
+
+		if len(notes) == 0 {
+			fmt.Fprintf(stdout, "  - %s\n", reviewer)
+		} else {
+			fmt.Fprintf(stdout, "  - %s (%s)\n", reviewer, strings.Join(notes, ", "))
+		}
+	}
+
+	return 0
+}
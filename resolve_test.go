@@ -0,0 +1,89 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+func Test_run_Resolve(t *testing.T) {
+	t.Parallel()
+
+	config := `
+		repositories:
+			octocat/hello-world:
+				default:
+					- octocat
+					- handle: octopus
+						name: Octo Pus
+						chat: '@octo'
+						tz: Pacific/Auckland
+				infra: [octodog, octokitten]
+				always:
+					reviewers: [octobear]
+					method: assignee
+	`
+
+	tests := []struct {
+		name string
+		args []string
+		exit int
+	}{
+		{
+			name: "when resolving the default group",
+			args: []string{"resolve"},
+			exit: 0,
+		},
+		{
+			name: "when resolving a specific group",
+			args: []string{"resolve", "--from", "infra", "--order", "alphabetical"},
+			exit: 0,
+		},
+		{
+			name: "when outputting json",
+			args: []string{"resolve", "--format", "json"},
+			exit: 0,
+		},
+		{
+			name: "when using an unsupported format",
+			args: []string{"resolve", "--format", "csv"},
+			exit: 1,
+		},
+		{
+			name: "when the group does not exist",
+			args: []string{"resolve", "--from", "security"},
+			exit: 1,
+		},
+		{
+			name: "when given a pull request",
+			args: []string{"resolve", "123"},
+			exit: 1,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			configDir := writeConfigFileInTempDir(t, dedent(t, config))
+
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+
+			got := run(
+				append([]string{"--config-dir", configDir, "--repo", "octocat/hello-world"}, tt.args...),
+				stdout,
+				stderr,
+				expectNoCallToGh(t),
+			)
+
+			if got != tt.exit {
+				t.Errorf("run() = %v, want %v", got, tt.exit)
+			}
+
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stdout))
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stderr))
+		})
+	}
+}
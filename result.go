@@ -0,0 +1,76 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// requestResult describes a pull request after reviews have been requested on
+// it, based on what gh reports rather than what it happened to output
+type requestResult struct {
+	Repository     string   `json:"repository"`
+	Number         int      `json:"number"`
+	URL            string   `json:"url"`
+	Title          string   `json:"title"`
+	Group          string   `json:"group"`
+	Requested      []string `json:"requested"`
+	ReviewRequests []string `json:"reviewRequests"`
+}
+
+// fetchRequestResult uses gh to get the details of the pull request that reviews
+// were just requested on, including everyone that reviews are now requested from
+func fetchRequestResult(ghExec ghExecutor, repo, target, group string, reviewers []reviewer) (requestResult, error) {
+	out, errMsg := ghExec("pr", "view", target, "--repo", repo, "--json", "number,url,title,reviewRequests")
+
+	if errMsg != "" {
+		return requestResult{}, fmt.Errorf("could not get details of the pull request: %s", strings.TrimSpace(errMsg))
+	}
+
+	var pr openPullRequest
+
+	if err := json.Unmarshal([]byte(out), &pr); err != nil {
+		return requestResult{}, fmt.Errorf("could not get details of the pull request: %w", err)
+	}
+
+	res := requestResult{
+		Repository:     repo,
+		Number:         pr.Number,
+		URL:            pr.URL,
+		Title:          pr.Title,
+		Group:          group,
+		Requested:      make([]string, 0, len(reviewers)),
+		ReviewRequests: make([]string, 0, len(pr.ReviewRequests)),
+	}
+
+	for _, reviewer := range reviewers {
+		res.Requested = append(res.Requested, reviewer.Handle)
+	}
+
+	for _, request := range pr.ReviewRequests {
+		switch {
+		case request.Login != "":
+			res.ReviewRequests = append(res.ReviewRequests, request.Login)
+		case request.Slug != "":
+			res.ReviewRequests = append(res.ReviewRequests, request.Slug)
+		default:
+			res.ReviewRequests = append(res.ReviewRequests, request.Name)
+		}
+	}
+
+	return res, nil
+}
+
+// printRequestResult outputs the result as indented json
+func printRequestResult(w io.Writer, res requestResult) error {
+	out, err := json.MarshalIndent(res, "", "  ")
+
+	if err != nil {
+		return fmt.Errorf("could not marshal result: %w", err)
+	}
+
+	fmt.Fprintln(w, string(out))
+
+	return nil
+}
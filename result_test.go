@@ -0,0 +1,112 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+// fakeResultGh acts as gh for a repository where viewing pull request #13
+// always fails, and every other pull request has reviews requested from a
+// person and a team
+func fakeResultGh(t *testing.T, calls *[][]string) ghExecutor {
+	t.Helper()
+
+	return func(args ...string) (string, string) {
+		t.Helper()
+
+		*calls = append(*calls, args)
+
+		switch {
+		case len(args) > 2 && args[0] == "pr" && args[1] == "edit":
+			return fmt.Sprintf("https://github.com/octocat/hello-world/pull/%s", args[2]), ""
+		case len(args) > 2 && args[0] == "pr" && args[1] == "view":
+			if args[2] == "13" {
+				return "", "GraphQL: Could not resolve to a PullRequest with the number of 13."
+			}
+
+			return fmt.Sprintf(`{
+				"number": %s,
+				"url": "https://github.com/octocat/hello-world/pull/%s",
+				"title": "Add a greeting",
+				"reviewRequests": [
+					{"__typename": "User", "login": "octocat"},
+					{"__typename": "Team", "name": "Security", "slug": "octocat/security"}
+				]
+			}`, args[2], args[2]), ""
+		}
+
+		t.Errorf("unexpected call to gh: %v", args)
+
+		return "", ""
+	}
+}
+
+func Test_run_WithJSONResult(t *testing.T) {
+	t.Parallel()
+
+	config := `
+		settings:
+			output_template: 'requested {{ len .Reviewers }} reviewers'
+		repositories:
+			octocat/hello-world:
+				default: [octocat, octocat/security]
+	`
+
+	tests := []struct {
+		name string
+		args []string
+		exit int
+	}{
+		{
+			name: "when requesting reviews",
+			args: []string{"1", "--format", "json"},
+			exit: 0,
+		},
+		{
+			name: "when the pull request cannot be viewed",
+			args: []string{"13", "--format", "json"},
+			exit: 1,
+		},
+		{
+			name: "when using a template",
+			args: []string{"1", "--format", "json", "--template", "{{ .URL }}"},
+			exit: 1,
+		},
+		{
+			name: "when marking the pull request as ready",
+			args: []string{"ready", "1", "--format", "json"},
+			exit: 1,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			configDir := writeConfigFileInTempDir(t, dedent(t, config))
+
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+
+			var calls [][]string
+
+			got := run(
+				append([]string{"--config-dir", configDir, "--repo", "octocat/hello-world"}, tt.args...),
+				stdout,
+				stderr,
+				fakeResultGh(t, &calls),
+			)
+
+			if got != tt.exit {
+				t.Errorf("run() = %v, want %v", got, tt.exit)
+			}
+
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stdout))
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stderr))
+			snaps.MatchJSON(t, calls)
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// rosterPrefix marks a reviewer as being whoever is currently listed by an http
+// endpoint (such as an internal on-call roster), rather than a specific user
+const rosterPrefix = "url:"
+
+// rosterTimeout is how long a roster has to respond before giving up on it
+const rosterTimeout = 10 * time.Second
+
+// rosterCache holds the logins listed by each roster that has been fetched, as
+// the same roster is often resolved multiple times when gh-rr is run
+var rosterCache = struct {
+	sync.Mutex
+	rosters map[string][]string
+}{rosters: make(map[string][]string)}
+
+// fetchRoster returns the logins listed by the roster at the given url, which
+// is expected to respond with a json array of logins; each roster is only
+// fetched once for the life of the process
+func fetchRoster(url string) ([]string, error) {
+	rosterCache.Lock()
+	defer rosterCache.Unlock()
+
+	if logins, ok := rosterCache.rosters[url]; ok {
+		return logins, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), rosterTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+
+	if err != nil {
+		return nil, fmt.Errorf("could not get the roster at %s: %w", url, err)
+	}
+
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+
+	if err != nil {
+		return nil, fmt.Errorf("could not get the roster at %s: %w", url, err)
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+
+		return nil, fmt.Errorf("could not get the roster at %s: %s: %s", url, resp.Status, strings.TrimSpace(string(msg)))
+	}
+
+	var logins []string
+
+	if err := json.NewDecoder(resp.Body).Decode(&logins); err != nil {
+		return nil, fmt.Errorf("could not parse the roster at %s: %w", url, err)
+	}
+
+	rosterCache.rosters[url] = logins
+
+	return logins, nil
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+// newRosterServer returns a server that lists on-call rosters, along with the
+// number of times each roster has been requested
+func newRosterServer(t *testing.T) (*httptest.Server, map[string]int) {
+	t.Helper()
+
+	var mu sync.Mutex
+
+	hits := make(map[string]int)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		hits[r.URL.Path]++
+		mu.Unlock()
+
+		switch r.URL.Path {
+		case "/oncall/backend":
+			_, _ = w.Write([]byte(`["octodog", "OctoCat"]`))
+		case "/oncall/empty":
+			_, _ = w.Write([]byte(`[]`))
+		case "/oncall/invalid":
+			_, _ = w.Write([]byte(`{"logins": "octodog"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte("no such roster"))
+		}
+	}))
+
+	t.Cleanup(server.Close)
+
+	return server, hits
+}
+
+func Test_run_WithRosters(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		args   []string
+		config string
+		exit   int
+	}{
+		{
+			name: "when a group includes a roster",
+			args: []string{"123"},
+			config: `
+				repositories:
+					octocat/hello-world:
+						- octocat
+						- url:{{roster}}/oncall/backend
+			`,
+			exit: 0,
+		},
+		{
+			name: "when the same roster is used multiple times",
+			args: []string{"123"},
+			config: `
+				repositories:
+					octocat/hello-world:
+						default: [url:{{roster}}/oncall/backend]
+						always: [octopus, url:{{roster}}/oncall/backend]
+			`,
+			exit: 0,
+		},
+		{
+			name: "when the roster is empty",
+			args: []string{"123"},
+			config: `
+				repositories:
+					octocat/hello-world:
+						- octocat
+						- url:{{roster}}/oncall/empty
+			`,
+			exit: 0,
+		},
+		{
+			name: "when the roster cannot be found",
+			args: []string{"123"},
+			config: `
+				repositories:
+					octocat/hello-world:
+						- url:{{roster}}/oncall/frontend
+			`,
+			exit: 1,
+		},
+		{
+			name: "when the roster is not a list of logins",
+			args: []string{"123"},
+			config: `
+				repositories:
+					octocat/hello-world:
+						- url:{{roster}}/oncall/invalid
+			`,
+			exit: 1,
+		},
+		{
+			name: "when checking who is on a roster",
+			args: []string{"who", "octodog"},
+			config: `
+				repositories:
+					octocat/hello-world:
+						- url:{{roster}}/oncall/backend
+			`,
+			exit: 0,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			server, hits := newRosterServer(t)
+
+			configDir := writeConfigFileInTempDir(t, strings.ReplaceAll(dedent(t, tt.config), "{{roster}}", server.URL))
+
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+
+			got := run(
+				append([]string{"--config-dir", configDir, "--repo", "octocat/hello-world"}, tt.args...),
+				stdout,
+				stderr,
+				expectCallToGh(t, "octocat/hello-world", "123"),
+			)
+
+			if got != tt.exit {
+				t.Errorf("run() = %v, want %v", got, tt.exit)
+			}
+
+			snaps.MatchSnapshot(t, strings.ReplaceAll(normalizeStdStream(t, stdout), server.URL, "<roster>"))
+			snaps.MatchSnapshot(t, strings.ReplaceAll(normalizeStdStream(t, stderr), server.URL, "<roster>"))
+			snaps.MatchJSON(t, hits)
+		})
+	}
+}
@@ -0,0 +1,215 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+type searchOptions struct {
+	repo            string
+	query           string
+	group           string
+	reviewers       []reviewer
+	perPullRequest  int
+	order           reviewerOrder
+	alwaysRequested map[string]bool
+	guards          guards
+	skipPaths       []string
+	checks          *checksOptions
+	notifications   notifications
+	urgentLabel     string
+	isDryRun        bool
+
+	// authors are the rules for routing pull requests based on their author,
+	// with route requesting reviews on a pull request from another group
+	authors []authorRule
+	route   func(number, group string) int
+}
+
+// searchPullRequests returns the open pull requests in the repository that
+// match the given search query
+func searchPullRequests(ghExec ghExecutor, repo, query string) ([]openPullRequest, error) {
+	out, errMsg := ghExec("pr", "list", "--repo", repo, "--state", "open", "--search", query, "--json", "number,url,reviewRequests,author")
+
+	if errMsg != "" {
+		return nil, fmt.Errorf("could not search pull requests: %s", strings.TrimSpace(errMsg))
+	}
+
+	var prs []openPullRequest
+
+	if err := json.Unmarshal([]byte(out), &prs); err != nil {
+		return nil, fmt.Errorf("could not parse pull requests: %w", err)
+	}
+
+	return prs, nil
+}
+
+// pickReviewers returns who to request reviews from on the nth pull request of
+// the search results when only some of the group should be requested on each,
+// which is whoever is always requested along with enough of the rest of the
+// group who have not already been requested; the rest of the group is either
+// shuffled or rotated for each pull request depending on the order, so that
+// the reviews are spread out
+func pickReviewers(pr pullRequest, opts searchOptions, nth int) []reviewer {
+	var always, pool []reviewer
+
+	requested := 0
+
+	for _, r := range opts.reviewers {
+		switch {
+		case opts.alwaysRequested[strings.ToLower(r.Handle)]:
+			always = append(always, r)
+		case pr.hasRequestedReviewFrom(r.Handle):
+			requested++
+		default:
+			pool = append(pool, r)
+		}
+	}
+
+	wanted := min(opts.perPullRequest-requested, len(pool))
+
+	if wanted <= 0 {
+		return always
+	}
+
+	if opts.order == orderShuffle {
+		pool = sortReviewers(pool, orderShuffle)
+	} else {
+		offset := (nth * opts.perPullRequest) % len(pool)
+		pool = append(slices.Clone(pool[offset:]), pool[:offset]...)
+	}
+
+	return append(pool[:wanted], always...)
+}
+
+// requestOnSearchResults requests reviews from the reviewers on every open pull
+// request that matches the search query, skipping those that already have
+// reviews requested from everyone
+func requestOnSearchResults(stdout, stderr io.Writer, ghExec ghExecutor, opts searchOptions) int {
+	prs, err := searchPullRequests(ghExec, opts.repo, opts.query)
+
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+
+		return 1
+	}
+
+	if len(prs) == 0 {
+		fmt.Fprintf(stdout, "there are no open pull requests matching %s\n", opts.query)
+
+		return 0
+	}
+
+	exit := 0
+	picked := 0
+
+	for _, pr := range prs {
+		number := fmt.Sprint(pr.Number)
+
+		if rule, ok := findAuthorRule(opts.authors, normalizeLogin(pr.Author.Login)); ok {
+			if rule.Skip {
+				fmt.Fprintf(stdout, "not requesting reviews on %s as it was authored by %s\n", pr.URL, normalizeLogin(pr.Author.Login))
+
+				continue
+			}
+
+			if rule.Group != "" && rule.Group != opts.group {
+				if opts.route(number, rule.Group) != 0 {
+					exit = 1
+				}
+
+				continue
+			}
+		}
+
+		if len(opts.skipPaths) > 0 {
+			skip, err := shouldSkipPullRequest(ghExec, opts.skipPaths, opts.repo, number)
+
+			if err != nil {
+				fmt.Fprintf(stderr, "%s: %v\n", pr.URL, err)
+
+				exit = 1
+
+				continue
+			}
+
+			if skip {
+				fmt.Fprintf(stdout, "not requesting reviews on %s as it only changes files that do not need reviewing\n", pr.URL)
+
+				continue
+			}
+		}
+
+		reviewers := opts.reviewers
+
+		if opts.perPullRequest > 0 {
+			reviewers = pickReviewers(pr.pullRequest, opts, picked)
+
+			if hasRequestedReviewFromAll(pr.pullRequest, reviewers) {
+				fmt.Fprintf(stdout, "reviews have already been requested on %s from %d people in the %s group\n", pr.URL, opts.perPullRequest, opts.group)
+
+				continue
+			}
+
+			picked++
+		} else if hasRequestedReviewFromAll(pr.pullRequest, reviewers) {
+			fmt.Fprintf(stdout, "reviews have already been requested on %s from everyone in the %s group\n", pr.URL, opts.group)
+
+			continue
+		}
+
+		if !opts.guards.isEmpty() && !enforceGuards(stderr, ghExec, opts.guards, opts.repo, number, pr.URL) {
+			exit = 1
+
+			continue
+		}
+
+		if opts.isDryRun {
+			fmt.Fprintf(stdout, "would have requested reviews on %s from:\n", pr.URL)
+		} else {
+			if opts.checks != nil {
+				checks := *opts.checks
+				checks.target = number
+
+				if err := gateOnChecks(stderr, ghExec, checks); err != nil {
+					fmt.Fprintf(stderr, "%s: %v\n", pr.URL, err)
+
+					exit = 1
+
+					continue
+				}
+			}
+
+			if _, err := attachReviewers(ghExec, opts.repo, number, reviewers, opts.urgentLabel); err != nil {
+				fmt.Fprintf(stderr, "could not request reviews on %s: %v\n", pr.URL, err)
+
+				exit = 1
+
+				continue
+			}
+
+			fmt.Fprintf(stdout, "requested reviews on %s from:\n", pr.URL)
+		}
+
+		for _, reviewer := range reviewers {
+			fmt.Fprintf(stdout, "  - %s\n", reviewer)
+		}
+
+		printUrgent(stdout, opts.urgentLabel, opts.isDryRun)
+
+		if !opts.isDryRun {
+			sendNotifications(stderr, opts.notifications, reviewRequest{
+				Repository: opts.repo,
+				Group:      opts.group,
+				URL:        pr.URL,
+				Reviewers:  reviewers,
+				Urgent:     opts.urgentLabel != "",
+			})
+		}
+	}
+
+	return exit
+}
@@ -0,0 +1,315 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+// fakeSearchGh acts as gh for a repository where searching returns the given
+// pull requests, and requesting reviews on pull request #13 always fails
+func fakeSearchGh(t *testing.T, prs string, calls *[][]string) ghExecutor {
+	t.Helper()
+
+	return func(args ...string) (string, string) {
+		t.Helper()
+
+		*calls = append(*calls, args)
+
+		switch {
+		case len(args) > 1 && args[0] == "pr" && args[1] == "list":
+			return prs, ""
+		case len(args) > 2 && args[0] == "pr" && args[1] == "view":
+			if args[2] == "2" {
+				return `{"body":""}`, ""
+			}
+
+			return `{"body":"fixes the thing"}`, ""
+		case len(args) > 2 && args[0] == "pr" && args[1] == "edit":
+			if args[2] == "13" {
+				return "", "GraphQL: Could not resolve to a PullRequest with the number of 13."
+			}
+
+			return fmt.Sprintf("https://github.com/octocat/hello-world/pull/%s", args[2]), ""
+		}
+
+		t.Errorf("unexpected call to gh: %v", args)
+
+		return "", ""
+	}
+}
+
+func Test_run_Search(t *testing.T) {
+	t.Parallel()
+
+	prs := `[
+		{"number":1,"url":"https://github.com/octocat/hello-world/pull/1","reviewRequests":[]},
+		{"number":2,"url":"https://github.com/octocat/hello-world/pull/2","reviewRequests":[{"login":"octocat"}]},
+		{"number":3,"url":"https://github.com/octocat/hello-world/pull/3","reviewRequests":[{"login":"octocat"},{"login":"octopus"}]}
+	]`
+
+	tests := []struct {
+		name   string
+		args   []string
+		config string
+		prs    string
+		exit   int
+	}{
+		{
+			name: "when pull requests match the search",
+			args: []string{"--search", "label:needs-review author:app/renovate"},
+			prs:  prs,
+			exit: 0,
+		},
+		{
+			name: "when doing a dry-run",
+			args: []string{"--dry-run", "--search", "label:needs-review"},
+			prs:  prs,
+			exit: 0,
+		},
+		{
+			name: "when no pull requests match the search",
+			args: []string{"--search", "label:needs-review"},
+			prs:  `[]`,
+			exit: 0,
+		},
+		{
+			name: "when reviews cannot be requested on a pull request",
+			args: []string{"--search", "label:needs-review"},
+			prs: `[
+				{"number":13,"url":"https://github.com/octocat/hello-world/pull/13","reviewRequests":[]},
+				{"number":1,"url":"https://github.com/octocat/hello-world/pull/1","reviewRequests":[]}
+			]`,
+			exit: 1,
+		},
+		{
+			name: "when a pull request does not pass the guards",
+			args: []string{"--search", "label:needs-review"},
+			config: `
+				settings:
+					guards:
+						description: block
+			`,
+			prs:  prs,
+			exit: 1,
+		},
+		{
+			name: "when there are author rules",
+			args: []string{"--search", "label:needs-review"},
+			config: `
+				authors:
+					- match: ['renovate[bot]']
+						skip: true
+					- match: ['dependabot[bot]']
+						group: deps
+				owners:
+					octocat:
+						deps: [octodog]
+			`,
+			prs: `[
+				{"number":1,"url":"https://github.com/octocat/hello-world/pull/1","reviewRequests":[],"author":{"login":"octokitten"}},
+				{"number":4,"url":"https://github.com/octocat/hello-world/pull/4","reviewRequests":[],"author":{"login":"app/renovate"}},
+				{"number":5,"url":"https://github.com/octocat/hello-world/pull/5","reviewRequests":[],"author":{"login":"app/dependabot"}}
+			]`,
+			exit: 0,
+		},
+		{
+			name: "when there are author rules and a group is given",
+			args: []string{"--search", "label:needs-review", "--from", "default"},
+			config: `
+				authors:
+					- match: ['renovate[bot]']
+						group: deps
+			`,
+			prs: `[
+				{"number":4,"url":"https://github.com/octocat/hello-world/pull/4","reviewRequests":[],"author":{"login":"app/renovate"}}
+			]`,
+			exit: 0,
+		},
+		{
+			name: "when given a pull request",
+			args: []string{"--search", "label:needs-review", "123"},
+			prs:  prs,
+			exit: 1,
+		},
+		{
+			name: "when using the json format",
+			args: []string{"--dry-run", "--format", "json", "--search", "label:needs-review"},
+			prs:  prs,
+			exit: 1,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			configDir := writeConfigFileInTempDir(t, dedent(t, tt.config+`
+				repositories:
+					octocat/hello-world:
+						- octocat
+						- octopus
+			`))
+
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+
+			var calls [][]string
+
+			got := run(
+				append([]string{"--config-dir", configDir, "--repo", "octocat/hello-world"}, tt.args...),
+				stdout,
+				stderr,
+				fakeSearchGh(t, tt.prs, &calls),
+			)
+
+			if got != tt.exit {
+				t.Errorf("run() = %v, want %v", got, tt.exit)
+			}
+
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stdout))
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stderr))
+			snaps.MatchJSON(t, calls)
+		})
+	}
+}
+
+func Test_run_SearchWithReviewersPerPR(t *testing.T) {
+	t.Parallel()
+
+	prs := `[
+		{"number":1,"url":"https://github.com/octocat/hello-world/pull/1","reviewRequests":[]},
+		{"number":2,"url":"https://github.com/octocat/hello-world/pull/2","reviewRequests":[]},
+		{"number":3,"url":"https://github.com/octocat/hello-world/pull/3","reviewRequests":[]}
+	]`
+
+	tests := []struct {
+		name   string
+		args   []string
+		config string
+		prs    string
+		exit   int
+	}{
+		{
+			name: "when the repository sets how many reviewers to request",
+			args: []string{"--search", "label:needs-review"},
+			config: `
+				repositories:
+					octocat/hello-world:
+						reviewers_per_pr: 1
+						default: [octocat, octodog, octopus]
+			`,
+			prs:  prs,
+			exit: 0,
+		},
+		{
+			name: "when the group sets how many reviewers to request",
+			args: []string{"--search", "label:needs-review"},
+			config: `
+				repositories:
+					octocat/hello-world:
+						reviewers_per_pr: 1
+						default:
+							reviewers_per_pr: 2
+							reviewers: [octocat, octodog, octopus]
+			`,
+			prs:  prs,
+			exit: 0,
+		},
+		{
+			name: "when using alphabetical order",
+			args: []string{"--search", "label:needs-review", "--order", "alphabetical"},
+			config: `
+				repositories:
+					octocat/hello-world:
+						reviewers_per_pr: 2
+						default: [octopus, octodog, octocat]
+			`,
+			prs:  prs,
+			exit: 0,
+		},
+		{
+			name: "when some reviewers have already been requested",
+			args: []string{"--search", "label:needs-review"},
+			config: `
+				repositories:
+					octocat/hello-world:
+						reviewers_per_pr: 2
+						default: [octocat, octodog, octopus]
+			`,
+			prs: `[
+				{"number":1,"url":"https://github.com/octocat/hello-world/pull/1","reviewRequests":[{"login":"octodog"}]},
+				{"number":2,"url":"https://github.com/octocat/hello-world/pull/2","reviewRequests":[{"login":"octocat"},{"login":"octopus"}]}
+			]`,
+			exit: 0,
+		},
+		{
+			name: "when there is an always group",
+			args: []string{"--search", "label:needs-review"},
+			config: `
+				repositories:
+					octocat/hello-world:
+						reviewers_per_pr: 1
+						default: [octocat, octodog]
+						always: [octokitten]
+			`,
+			prs:  prs,
+			exit: 0,
+		},
+		{
+			name: "when the repository count is invalid",
+			args: []string{"--search", "label:needs-review"},
+			config: `
+				repositories:
+					octocat/hello-world:
+						reviewers_per_pr: -1
+						default: [octocat, octodog]
+			`,
+			prs:  prs,
+			exit: 1,
+		},
+		{
+			name: "when the group count is invalid",
+			args: []string{"--search", "label:needs-review"},
+			config: `
+				repositories:
+					octocat/hello-world:
+						default:
+							reviewers_per_pr: -2
+							reviewers: [octocat, octodog]
+			`,
+			prs:  prs,
+			exit: 1,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			configDir := writeConfigFileInTempDir(t, dedent(t, tt.config))
+
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+
+			var calls [][]string
+
+			got := run(
+				append([]string{"--config-dir", configDir, "--repo", "octocat/hello-world"}, tt.args...),
+				stdout,
+				stderr,
+				fakeSearchGh(t, tt.prs, &calls),
+			)
+
+			if got != tt.exit {
+				t.Errorf("run() = %v, want %v", got, tt.exit)
+			}
+
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stdout))
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stderr))
+			snaps.MatchJSON(t, calls)
+		})
+	}
+}
@@ -0,0 +1,269 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"math/rand"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+type settings struct {
+	OnNoop           noopBehavior     `yaml:"on_noop"`
+	OnArchived       archivedBehavior `yaml:"on_archived"`
+	ReminderTemplate string           `yaml:"reminder_template"`
+	OutputTemplate   string           `yaml:"output_template"`
+	Order            reviewerOrder    `yaml:"order"`
+	GroupSearch      groupSearch      `yaml:"group_search"`
+	WorkingHours     *workingHours    `yaml:"working_hours"`
+	Guards           guards           `yaml:"guards"`
+	GroupFromTeam    bool             `yaml:"group_from_team"`
+	OptedOut         []string         `yaml:"opted_out"`
+	ExceptTeams      []string         `yaml:"except_teams"`
+	UrgentLabel      string           `yaml:"urgent_label"`
+	StateGist        string           `yaml:"state_gist"`
+	ConfigGist       string           `yaml:"config_gist"`
+	SkipPaths        []string         `yaml:"skip_paths"`
+	Strict           bool             `yaml:"strict"`
+	DefaultGroup     string           `yaml:"default_group"`
+
+	// TrustSharedConfig is whether the shared config of a repository can do more
+	// than add groups, which is only honoured in the personal config
+	TrustSharedConfig bool `yaml:"trust_shared_config"`
+
+	// present are the keys that were given in the config, so that settings can
+	// be merged individually even when they are being set to their zero value
+	present map[string]bool
+}
+
+func (s *settings) UnmarshalYAML(value *yaml.Node) error {
+	type rawSettings settings
+
+	if err := value.Decode((*rawSettings)(s)); err != nil {
+		return err
+	}
+
+	s.present = make(map[string]bool, len(value.Content)/2)
+
+	for i := 0; i+1 < len(value.Content); i += 2 {
+		s.present[value.Content[i].Value] = true
+	}
+
+	return nil
+}
+
+// noopBehavior controls what happens when reviews have already been requested
+// from everyone that would be requested, meaning there is nothing to do
+type noopBehavior string
+
+const (
+	noopSuccess noopBehavior = "success"
+	noopWarn    noopBehavior = "warn"
+	noopError   noopBehavior = "error"
+)
+
+func (b *noopBehavior) UnmarshalYAML(value *yaml.Node) error {
+	var behavior string
+
+	if err := value.Decode(&behavior); err != nil {
+		return err
+	}
+
+	switch noopBehavior(behavior) {
+	case noopSuccess, noopWarn, noopError:
+		*b = noopBehavior(behavior)
+	default:
+		return fmt.Errorf("line %d: on_noop must be one of success, warn, or error, not `%s`", value.Line, behavior)
+	}
+
+	return nil
+}
+
+// reportNoop outputs that there was nothing to do, returning the exit code
+// that should be used based on the configured behavior
+func reportNoop(stdout, stderr io.Writer, behavior noopBehavior, group string) int {
+	msg := fmt.Sprintf("reviews have already been requested from everyone in the %s group", group)
+
+	switch behavior {
+	case noopWarn:
+		fmt.Fprintf(stderr, "warning: %s\n", msg)
+	case noopError:
+		fmt.Fprintln(stderr, msg)
+
+		return 1
+	case noopSuccess:
+		fmt.Fprintln(stdout, msg)
+	}
+
+	return 0
+}
+
+// groupSearch controls where groups are looked for when the current
+// repository does not have the requested group
+type groupSearch string
+
+const (
+	groupSearchRepository groupSearch = "repository"
+	groupSearchAny        groupSearch = "any"
+)
+
+func (s *groupSearch) UnmarshalYAML(value *yaml.Node) error {
+	var search string
+
+	if err := value.Decode(&search); err != nil {
+		return err
+	}
+
+	switch groupSearch(search) {
+	case groupSearchRepository, groupSearchAny:
+		*s = groupSearch(search)
+	default:
+		return fmt.Errorf("line %d: group_search must be either repository or any, not `%s`", value.Line, search)
+	}
+
+	return nil
+}
+
+// reviewerOrder controls the order that reviews are requested in, which matters
+// as the first reviewer listed tends to be the one that responds first
+type reviewerOrder string
+
+const (
+	orderConfig       reviewerOrder = "config"
+	orderAlphabetical reviewerOrder = "alphabetical"
+	orderShuffle      reviewerOrder = "shuffle"
+)
+
+func (o reviewerOrder) isValid() bool {
+	return o == orderConfig || o == orderAlphabetical || o == orderShuffle
+}
+
+func (o *reviewerOrder) UnmarshalYAML(value *yaml.Node) error {
+	var order string
+
+	if err := value.Decode(&order); err != nil {
+		return err
+	}
+
+	if !reviewerOrder(order).isValid() {
+		return fmt.Errorf("line %d: order must be one of config, alphabetical, or shuffle, not `%s`", value.Line, order)
+	}
+
+	*o = reviewerOrder(order)
+
+	return nil
+}
+
+// sortReviewers returns a copy of the reviewers in the given order
+func sortReviewers(reviewers []reviewer, order reviewerOrder) []reviewer {
+	sorted := slices.Clone(reviewers)
+
+	switch order {
+	case orderAlphabetical:
+		slices.SortStableFunc(sorted, func(a, b reviewer) int {
+			return strings.Compare(strings.ToLower(a.Handle), strings.ToLower(b.Handle))
+		})
+	case orderShuffle:
+		//nolint:gosec // this does not need to be cryptographically secure
+		rand.Shuffle(len(sorted), func(i, j int) {
+			sorted[i], sorted[j] = sorted[j], sorted[i]
+		})
+	case orderConfig:
+	}
+
+	return sorted
+}
+
+// clockTime is a time of day, as the number of minutes since midnight
+type clockTime int
+
+var clockTimeRe = regexp.MustCompile(`^(\d{1,2}):(\d{2})$`)
+
+func (c *clockTime) UnmarshalYAML(value *yaml.Node) error {
+	var raw string
+
+	if err := value.Decode(&raw); err != nil {
+		return err
+	}
+
+	matches := clockTimeRe.FindStringSubmatch(raw)
+
+	if matches != nil {
+		hours, _ := strconv.Atoi(matches[1])
+		minutes, _ := strconv.Atoi(matches[2])
+
+		// 24:00 is allowed so that the working day can go until midnight
+		if minutes < 60 && (hours < 24 || (hours == 24 && minutes == 0)) {
+			*c = clockTime(hours*60 + minutes)
+
+			return nil
+		}
+	}
+
+	return fmt.Errorf("line %d: time must be in the format of HH:MM, not `%s`", value.Line, raw)
+}
+
+// workingHours is when queued review requests can be made, in the timezone of
+// each reviewer if known or otherwise local time
+type workingHours struct {
+	Days  []weekday `yaml:"days"`
+	Start clockTime `yaml:"start"`
+	End   clockTime `yaml:"end"`
+}
+
+var defaultWorkingHours = workingHours{
+	Days:  []weekday{weekday(time.Monday), weekday(time.Tuesday), weekday(time.Wednesday), weekday(time.Thursday), weekday(time.Friday)},
+	Start: 9 * 60,
+	End:   17 * 60,
+}
+
+func (w *workingHours) UnmarshalYAML(value *yaml.Node) error {
+	type rawWorkingHours workingHours
+
+	hours := rawWorkingHours(defaultWorkingHours)
+
+	if err := value.Decode(&hours); err != nil {
+		return err
+	}
+
+	if hours.End < hours.Start {
+		return fmt.Errorf("line %d: working hours must end after they start", value.Line)
+	}
+
+	*w = workingHours(hours)
+
+	return nil
+}
+
+// contains checks if the given time is within the working hours
+func (w workingHours) contains(t time.Time) bool {
+	minutes := clockTime(t.Hour()*60 + t.Minute())
+
+	return slices.Contains(w.Days, weekday(t.Weekday())) && minutes >= w.Start && minutes < w.End
+}
+
+// weekday is a day of the week, such as "monday"
+type weekday time.Weekday
+
+func (d *weekday) UnmarshalYAML(value *yaml.Node) error {
+	var raw string
+
+	if err := value.Decode(&raw); err != nil {
+		return err
+	}
+
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if strings.EqualFold(day.String(), raw) {
+			*d = weekday(day)
+
+			return nil
+		}
+	}
+
+	return fmt.Errorf("line %d: day must be a day of the week, not `%s`", value.Line, raw)
+}
@@ -0,0 +1,251 @@
+package main
+
+import (
+	"bytes"
+	"slices"
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+// fakeReviewRequestsGh acts as gh for a pull request that already has
+// reviews requested from the given logins
+func fakeReviewRequestsGh(t *testing.T, requested string, calls *[][]string) ghExecutor {
+	t.Helper()
+
+	return func(args ...string) (string, string) {
+		t.Helper()
+
+		*calls = append(*calls, args)
+
+		switch {
+		case len(args) > 1 && args[0] == "pr" && args[1] == "view":
+			if requested == "" {
+				return "", "no pull requests found for branch \"main\""
+			}
+
+			return `{"reviewRequests":` + requested + `}`, ""
+		case len(args) > 1 && args[0] == "pr" && args[1] == "edit":
+			return "https://github.com/octocat/hello-world/pull/123", ""
+		}
+
+		t.Errorf("unexpected call to gh: %v", args)
+
+		return "", ""
+	}
+}
+
+func Test_run_OnNoop(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		onNoop    string
+		requested string
+		exit      int
+	}{
+		{
+			name:      "when everyone has been requested and on_noop is success",
+			onNoop:    "success",
+			requested: `[{"login":"octocat"},{"login":"OctoPus"},{"login":"octodog"}]`,
+			exit:      0,
+		},
+		{
+			name:      "when everyone has been requested and on_noop is warn",
+			onNoop:    "warn",
+			requested: `[{"login":"octocat"},{"login":"octopus"}]`,
+			exit:      0,
+		},
+		{
+			name:      "when everyone has been requested and on_noop is error",
+			onNoop:    "error",
+			requested: `[{"login":"octocat"},{"login":"octopus"}]`,
+			exit:      1,
+		},
+		{
+			name:      "when only some people have been requested",
+			onNoop:    "error",
+			requested: `[{"login":"octocat"}]`,
+			exit:      0,
+		},
+		{
+			name:      "when the pull request cannot be found",
+			onNoop:    "error",
+			requested: "",
+			exit:      1,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			configDir := writeConfigFileInTempDir(t, dedent(t, `
+				settings:
+					on_noop: `+tt.onNoop+`
+				repositories:
+					octocat/hello-world:
+						- octocat
+						- octopus
+			`))
+
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+
+			var calls [][]string
+
+			got := run(
+				[]string{"--config-dir", configDir, "--repo", "octocat/hello-world", "123"},
+				stdout,
+				stderr,
+				fakeReviewRequestsGh(t, tt.requested, &calls),
+			)
+
+			if got != tt.exit {
+				t.Errorf("run() = %v, want %v", got, tt.exit)
+			}
+
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stdout))
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stderr))
+			snaps.MatchJSON(t, calls)
+		})
+	}
+}
+
+func Test_run_Order(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		args   []string
+		config string
+		exit   int
+	}{
+		{
+			name: "when no order is configured",
+			args: []string{"123"},
+			exit: 0,
+		},
+		{
+			name: "when ordering alphabetically in the config",
+			args: []string{"123"},
+			config: `
+				settings:
+					order: alphabetical
+			`,
+			exit: 0,
+		},
+		{
+			name: "when ordering alphabetically with a flag",
+			args: []string{"--order", "alphabetical", "123"},
+			exit: 0,
+		},
+		{
+			name: "when the flag overrides the config",
+			args: []string{"--order", "config", "123"},
+			config: `
+				settings:
+					order: alphabetical
+			`,
+			exit: 0,
+		},
+		{
+			name: "when the order in the config is invalid",
+			args: []string{"123"},
+			config: `
+				settings:
+					order: reverse
+			`,
+			exit: 1,
+		},
+		{
+			name: "when the order flag is invalid",
+			args: []string{"--order", "reverse", "123"},
+			exit: 1,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			configDir := writeConfigFileInTempDir(t, dedent(t, tt.config+`
+				repositories:
+					octocat/hello-world:
+						- octopus
+						- Octodog
+						- octocat
+			`))
+
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+
+			var ghExecArgs []string
+
+			got := run(
+				append([]string{"--config-dir", configDir, "--repo", "octocat/hello-world"}, tt.args...),
+				stdout,
+				stderr,
+				func(args ...string) (string, string) {
+					ghExecArgs = args
+
+					return "https://github.com/octocat/hello-world/pull/123", ""
+				},
+			)
+
+			if got != tt.exit {
+				t.Errorf("run() = %v, want %v", got, tt.exit)
+			}
+
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stdout))
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stderr))
+			snaps.MatchJSON(t, ghExecArgs)
+		})
+	}
+}
+
+func Test_run_Order_Shuffle(t *testing.T) {
+	t.Parallel()
+
+	configDir := writeConfigFileInTempDir(t, dedent(t, `
+		repositories:
+			octocat/hello-world:
+				- octopus
+				- octodog
+				- octocat
+	`))
+
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+
+	var ghExecArgs []string
+
+	got := run(
+		[]string{"--config-dir", configDir, "--repo", "octocat/hello-world", "--order", "shuffle", "123"},
+		stdout,
+		stderr,
+		func(args ...string) (string, string) {
+			ghExecArgs = args
+
+			return "https://github.com/octocat/hello-world/pull/123", ""
+		},
+	)
+
+	if got != 0 {
+		t.Errorf("run() = %v, want %v", got, 0)
+	}
+
+	var requested []string
+
+	for i, arg := range ghExecArgs {
+		if arg == "--add-reviewer" {
+			requested = append(requested, ghExecArgs[i+1])
+		}
+	}
+
+	slices.Sort(requested)
+
+	if want := []string{"octocat", "octodog", "octopus"}; !slices.Equal(requested, want) {
+		t.Errorf("requested reviews from %v, want %v", requested, want)
+	}
+}
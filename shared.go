@@ -0,0 +1,194 @@
+package main
+
+import (
+	"errors"
+	"maps"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+)
+
+// sharedConfigPath is where the config that is shared by everyone working on a
+// repository lives within it
+const sharedConfigPath = ".github/gh-rr.yml"
+
+// findLocalSharedConfig returns the path to the shared config of the git
+// repository that the current directory is within, if it is within one
+func findLocalSharedConfig() string {
+	dir, err := os.Getwd()
+
+	if err != nil {
+		return ""
+	}
+
+	for {
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			return filepath.Join(dir, filepath.FromSlash(sharedConfigPath))
+		}
+
+		parent := filepath.Dir(dir)
+
+		if parent == dir {
+			return ""
+		}
+
+		dir = parent
+	}
+}
+
+// mergeRepositories layers the groups of the overlay on top of those of the
+// base, with groups of the same name in the same repository being replaced
+func mergeRepositories(base, overlay repositories) repositories {
+	merged := make(repositories, len(base))
+
+	for _, repos := range []repositories{base, overlay} {
+		for repo, groups := range repos {
+			if _, ok := merged[repo]; !ok {
+				merged[repo] = make(map[string]group, len(groups))
+			}
+
+			maps.Copy(merged[repo], groups)
+		}
+	}
+
+	return merged
+}
+
+// mergeConfigs layers the overlay on top of the base config, with groups,
+// profiles, and settings being merged individually while anything else in the
+// overlay replaces that of the base if it has been set
+func mergeConfigs(base config, overlay config) config {
+	merged := base
+	merged.Repositories = mergeRepositories(base.Repositories, overlay.Repositories)
+	merged.Owners = mergeRepositories(base.Owners, overlay.Owners)
+	merged.Hosts = mergeHosts(base.Hosts, overlay.Hosts)
+
+	if len(overlay.Profiles) > 0 {
+		merged.Profiles = make(map[string]profile, len(base.Profiles)+len(overlay.Profiles))
+		maps.Copy(merged.Profiles, base.Profiles)
+		maps.Copy(merged.Profiles, overlay.Profiles)
+	}
+
+	if len(overlay.Phases) > 0 {
+		merged.Phases = make(map[string][]string, len(base.Phases)+len(overlay.Phases))
+		maps.Copy(merged.Phases, base.Phases)
+		maps.Copy(merged.Phases, overlay.Phases)
+	}
+
+	if len(overlay.Aliases) > 0 {
+		merged.Aliases = make(map[string]string, len(base.Aliases)+len(overlay.Aliases))
+		maps.Copy(merged.Aliases, base.Aliases)
+		maps.Copy(merged.Aliases, overlay.Aliases)
+	}
+
+	if len(overlay.Required) > 0 {
+		merged.Required = make(map[string][]string, len(base.Required)+len(overlay.Required))
+		maps.Copy(merged.Required, base.Required)
+		maps.Copy(merged.Required, overlay.Required)
+	}
+
+	if overlay.Extends != "" {
+		merged.Extends = overlay.Extends
+	}
+
+	if len(overlay.Notifications.Teams) > 0 {
+		merged.Notifications = overlay.Notifications
+	}
+
+	if len(overlay.Authors) > 0 {
+		merged.Authors = overlay.Authors
+	}
+
+	merged.Settings = mergeSettings(base.Settings, overlay.Settings)
+
+	return merged
+}
+
+// mergeSettings layers the settings given in the overlay on top of those of
+// the base, including any that the overlay sets to their zero value
+func mergeSettings(base, overlay settings) settings {
+	merged := base
+	m := reflect.ValueOf(&merged).Elem()
+	o := reflect.ValueOf(overlay)
+
+	for i := 0; i < m.NumField(); i++ {
+		name, _, _ := strings.Cut(m.Type().Field(i).Tag.Get("yaml"), ",")
+
+		if name == "" || !overlay.present[name] {
+			continue
+		}
+
+		m.Field(i).Set(o.Field(i))
+	}
+
+	merged.present = make(map[string]bool, len(base.present)+len(overlay.present))
+	maps.Copy(merged.present, base.present)
+	maps.Copy(merged.present, overlay.present)
+
+	return merged
+}
+
+// restrictSharedConfig limits the shared config of a repository to its groups,
+// as it comes from whatever repository is checked out, which should not be able
+// to send notifications to webhooks of its choosing or change how gh-rr behaves
+func restrictSharedConfig(conf config) config {
+	restricted := config{
+		Repositories: conf.Repositories,
+		Owners:       conf.Owners,
+		Hosts:        conf.Hosts,
+		Phases:       conf.Phases,
+		Required:     conf.Required,
+	}
+
+	if len(conf.Profiles) > 0 {
+		restricted.Profiles = make(map[string]profile, len(conf.Profiles))
+
+		for name, p := range conf.Profiles {
+			restricted.Profiles[name] = profile{config: restrictSharedConfig(p.config), Match: p.Match}
+		}
+	}
+
+	return restricted
+}
+
+// parseLayeredConfig parses and merges the personal config, the shared config
+// of the repository, and the config given with --config, in that order of
+// precedence, returning the path of
+// whichever config could not be parsed; only the config given with --config is
+// required to exist, so long as at least one of the configs does
+func parseLayeredConfig(personal, shared, extra string) (config, string, error) {
+	conf := config{Repositories: repositories{}}
+	found := false
+
+	for _, file := range []string{personal, shared, extra} {
+		if file == "" {
+			continue
+		}
+
+		layer, err := parseConfig(file)
+
+		if errors.Is(err, os.ErrNotExist) && file != extra {
+			continue
+		}
+
+		if err != nil {
+			return conf, file, err
+		}
+
+		// at this point conf only has the personal config, which is the only one
+		// that is able to say the shared config can be trusted
+		if file == shared && !conf.Settings.TrustSharedConfig {
+			layer = restrictSharedConfig(layer)
+		}
+
+		conf = mergeConfigs(conf, layer)
+		found = true
+	}
+
+	if !found {
+		return conf, personal, os.ErrNotExist
+	}
+
+	return conf, "", nil
+}
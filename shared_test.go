@@ -0,0 +1,312 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+// chdirToCheckoutWithSharedConfig changes into a fake checkout of a repository
+// that has the given shared config, for the rest of the test
+func chdirToCheckoutWithSharedConfig(t *testing.T, content string) {
+	t.Helper()
+
+	dir := writeConfigFileInTempDir(t, "")
+
+	if err := os.MkdirAll(filepath.Join(dir, ".git"), 0700); err != nil {
+		t.Fatalf("could not create fake checkout: %v", err)
+	}
+
+	if content != "" {
+		if err := os.MkdirAll(filepath.Join(dir, ".github"), 0700); err != nil {
+			t.Fatalf("could not create fake checkout: %v", err)
+		}
+
+		if err := os.WriteFile(filepath.Join(dir, ".github", "gh-rr.yml"), []byte(content), 0600); err != nil {
+			t.Fatalf("could not create shared config: %v", err)
+		}
+	}
+
+	// this is done within a subdirectory, as gh-rr can be run from anywhere
+	// within the checkout
+	sub := filepath.Join(dir, "src")
+
+	if err := os.MkdirAll(sub, 0700); err != nil {
+		t.Fatalf("could not create fake checkout: %v", err)
+	}
+
+	wd, err := os.Getwd()
+
+	if err != nil {
+		t.Fatalf("could not get working directory: %v", err)
+	}
+
+	if err := os.Chdir(sub); err != nil {
+		t.Fatalf("could not change directory: %v", err)
+	}
+
+	t.Cleanup(func() { _ = os.Chdir(wd) })
+}
+
+// these tests cannot be run in parallel as they change the working directory
+func Test_run_WithSharedConfig(t *testing.T) {
+	t.Setenv("GH_REPO", "octocat/hello-world")
+
+	shared := dedent(t, `
+		repositories:
+			octocat/hello-world:
+				default: [octocat, octopus]
+				docs: [octokitten]
+	`)
+
+	tests := []struct {
+		name     string
+		args     []string
+		personal string
+		shared   string
+		extra    string
+		exit     int
+	}{
+		{
+			name:   "when there is only a shared config",
+			args:   []string{"123"},
+			shared: shared,
+			exit:   0,
+		},
+		{
+			name: "when the shared config overrides a group",
+			args: []string{"123"},
+			personal: `
+				repositories:
+					octocat/hello-world:
+						default: [octodog]
+			`,
+			shared: shared,
+			exit:   0,
+		},
+		{
+			name: "when using a group that is only in the shared config",
+			args: []string{"--from", "docs", "123"},
+			personal: `
+				repositories:
+					octocat/hello-world:
+						default: [octodog]
+			`,
+			shared: shared,
+			exit:   0,
+		},
+		{
+			name: "when a config is passed as a flag",
+			args: []string{"--config", "{{extra}}", "123"},
+			personal: `
+				repositories:
+					octocat/hello-world:
+						docs: [octodog]
+			`,
+			shared: shared,
+			extra: `
+				repositories:
+					octocat/hello-world:
+						default: [octobear]
+			`,
+			exit: 0,
+		},
+		{
+			name: "when a config is passed as a flag and the group is only in the personal config",
+			args: []string{"--config", "{{extra}}", "--from", "infra", "123"},
+			personal: `
+				repositories:
+					octocat/hello-world:
+						infra: [octodog]
+			`,
+			shared: shared,
+			extra: `
+				repositories:
+					octocat/hello-world:
+						default: [octobear]
+			`,
+			exit: 0,
+		},
+		{
+			name: "when a config passed as a flag clears a setting of the personal config",
+			args: []string{"--config", "{{extra}}", "123"},
+			personal: `
+				settings:
+					output_template: '{{range .Reviewers}}@{{.Handle}} {{end}}'
+			`,
+			shared: shared,
+			extra: `
+				settings:
+					output_template: ''
+			`,
+			exit: 0,
+		},
+		{
+			name: "when linting a config passed as a flag",
+			args: []string{"lint", "--config", "{{extra}}"},
+			personal: `
+				repositories:
+					octocat/hello-world:
+						default: [octodog]
+			`,
+			shared: shared,
+			extra: `
+				repositories:
+					'*':
+						default: [octobear]
+					octocat/hello-world:
+						default: [octobear]
+			`,
+			exit: 1,
+		},
+		{
+			name: "when each config has different settings",
+			args: []string{"123"},
+			personal: `
+				settings:
+					order: alphabetical
+					trust_shared_config: true
+			`,
+			shared: dedent(t, `
+				repositories:
+					octocat/hello-world: [octopus, octocat]
+				settings:
+					output_template: '{{range .Reviewers}}@{{.Handle}} {{end}}'
+			`),
+			exit: 0,
+		},
+		{
+			name:   "when the config passed as a flag does not exist",
+			args:   []string{"--config", "{{extra}}", "123"},
+			shared: shared,
+			exit:   1,
+		},
+		{
+			name:   "when the shared config is invalid",
+			args:   []string{"123"},
+			shared: "repositories: [",
+			exit:   1,
+		},
+		{
+			name: "when there is no shared config",
+			args: []string{"123"},
+			exit: 1,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			chdirToCheckoutWithSharedConfig(t, tt.shared)
+
+			personal := ""
+
+			if tt.personal != "" {
+				personal = dedent(t, tt.personal)
+			}
+
+			configDir := writeConfigFileInTempDir(t, personal)
+			extraDir := writeConfigFileInTempDir(t, "")
+
+			if tt.extra != "" {
+				extraDir = writeConfigFileInTempDir(t, dedent(t, tt.extra))
+			}
+
+			args := slices.Clone(tt.args)
+
+			for i, arg := range args {
+				if arg == "{{extra}}" {
+					args[i] = filepath.Join(extraDir, "gh-rr.yml")
+				}
+			}
+
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+
+			got := run(
+				append([]string{"--config-dir", configDir}, args...),
+				stdout,
+				stderr,
+				expectCallToGh(t, "octocat/hello-world", "123"),
+			)
+
+			if got != tt.exit {
+				t.Errorf("run() = %v, want %v", got, tt.exit)
+			}
+
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stdout))
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stderr))
+		})
+	}
+}
+
+// these tests cannot be run in parallel as they change the working directory
+func Test_run_WithUntrustedSharedConfig(t *testing.T) {
+	t.Setenv("GH_REPO", "octocat/hello-world")
+
+	shared := `
+		notifications:
+			teams:
+				- webhook: {{webhook}}
+		repositories:
+			octocat/hello-world: [octopus, octocat]
+		settings:
+			output_template: 'sent to {{range .Reviewers}}@{{.Handle}} {{end}}'
+			trust_shared_config: true
+	`
+
+	tests := []struct {
+		name     string
+		personal string
+	}{
+		{
+			name: "when the shared config is not trusted",
+			personal: `
+				settings:
+					order: alphabetical
+			`,
+		},
+		{
+			name: "when the shared config is trusted",
+			personal: `
+				settings:
+					order: alphabetical
+					trust_shared_config: true
+			`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			server, recorder := newNotificationServer(t, http.StatusOK)
+
+			chdirToCheckoutWithSharedConfig(t, strings.ReplaceAll(dedent(t, shared), "{{webhook}}", server.URL))
+
+			configDir := writeConfigFileInTempDir(t, dedent(t, tt.personal))
+
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+
+			got := run(
+				[]string{"--config-dir", configDir, "123"},
+				stdout,
+				stderr,
+				expectCallToGh(t, "octocat/hello-world", "123"),
+			)
+
+			if got != 0 {
+				t.Errorf("run() = %v, want %v", got, 0)
+			}
+
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stdout))
+			snaps.MatchSnapshot(t, strings.ReplaceAll(normalizeStdStream(t, stderr), server.URL, "<webhook>"))
+			snaps.MatchSnapshot(t, fmt.Sprintf("%q", recorder.bodies))
+		})
+	}
+}
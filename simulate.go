@@ -0,0 +1,197 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+type simulateOptions struct {
+	repo  string
+	since string
+	until string
+}
+
+// historicalPullRequest is a past pull request, along with who reviewed it
+type historicalPullRequest struct {
+	pullRequest
+	Number int `json:"number"`
+}
+
+// reviewers returns the logins of everyone who reviewed the pull request, in
+// the order that they first reviewed it
+func (pr historicalPullRequest) reviewers() []string {
+	var logins []string
+
+	seen := make(map[string]bool)
+
+	for _, review := range pr.Reviews {
+		login := review.Author.Login
+		key := strings.ToLower(login)
+
+		if seen[key] || strings.EqualFold(login, pr.Author.Login) {
+			continue
+		}
+
+		seen[key] = true
+		logins = append(logins, login)
+	}
+
+	return logins
+}
+
+// buildCreatedQualifier returns a search qualifier for pull requests that were
+// created within the given dates, with the end being optional
+func buildCreatedQualifier(since, until string) (string, error) {
+	for _, date := range []string{since, until} {
+		if date == "" {
+			continue
+		}
+
+		if _, err := time.Parse(time.DateOnly, date); err != nil {
+			return "", fmt.Errorf("dates must be in the format of YYYY-MM-DD, not `%s`", date)
+		}
+	}
+
+	if until == "" {
+		return "created:>=" + since, nil
+	}
+
+	return fmt.Sprintf("created:%s..%s", since, until), nil
+}
+
+// fetchHistoricalPullRequests returns the pull requests in the repository that
+// match the given search query, regardless of their state
+func fetchHistoricalPullRequests(ghExec ghExecutor, repo, query string) ([]historicalPullRequest, error) {
+	out, errMsg := ghExec("pr", "list", "--repo", repo, "--state", "all", "--search", query, "--limit", "1000", "--json", "number,author,reviews")
+
+	if errMsg != "" {
+		return nil, fmt.Errorf("could not search pull requests: %s", strings.TrimSpace(errMsg))
+	}
+
+	var prs []historicalPullRequest
+
+	if err := json.Unmarshal([]byte(out), &prs); err != nil {
+		return nil, fmt.Errorf("could not parse pull requests: %w", err)
+	}
+
+	return prs, nil
+}
+
+// simulateRouting outputs which group the author rules would route each of the
+// pull requests created in the given dates to, along with who actually ended
+// up reviewing them, so that changes to the rules can be checked before use
+func simulateRouting(stdout, stderr io.Writer, ghExec ghExecutor, conf config, opts simulateOptions) int {
+	if opts.since == "" {
+		fmt.Fprintln(stderr, "please provide the date to simulate from with --since")
+
+		return 1
+	}
+
+	qualifier, err := buildCreatedQualifier(opts.since, opts.until)
+
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+
+		return 1
+	}
+
+	prs, err := fetchHistoricalPullRequests(ghExec, opts.repo, qualifier)
+
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+
+		return 1
+	}
+
+	if len(prs) == 0 {
+		fmt.Fprintf(stdout, "there are no pull requests matching %s\n", qualifier)
+
+		return 0
+	}
+
+	cache := &teamMemberCache{ghExec: ghExec, members: make(map[string][]string)}
+	groups := conf.Repositories[strings.ToLower(opts.repo)]
+
+	routed := 0
+	matched := 0
+
+	for _, pr := range prs {
+		author := normalizeLogin(pr.Author.Login)
+		name := "default"
+
+		rule, ok := findAuthorRule(conf.Authors, author)
+
+		if ok && rule.Skip {
+			fmt.Fprintf(stdout, "#%d by %s would be skipped\n", pr.Number, author)
+
+			continue
+		}
+
+		if ok && rule.Group != "" {
+			name = rule.Group
+		}
+
+		routed++
+
+		g, exists := groups[name]
+
+		if !exists {
+			fmt.Fprintf(stdout, "#%d by %s would be routed to the %s group, which does not exist\n", pr.Number, author, name)
+
+			continue
+		}
+
+		reviewers := pr.reviewers()
+
+		if len(reviewers) == 0 {
+			fmt.Fprintf(stdout, "#%d by %s would be routed to the %s group, and was not reviewed\n", pr.Number, author, name)
+
+			continue
+		}
+
+		var inGroup, outsideGroup []string
+
+		for _, login := range reviewers {
+			_, isMember, err := findMembership(cache, g, login)
+
+			if err != nil {
+				fmt.Fprintln(stderr, err)
+
+				return 1
+			}
+
+			if isMember {
+				inGroup = append(inGroup, login)
+			} else {
+				outsideGroup = append(outsideGroup, login)
+			}
+		}
+
+		if len(inGroup) > 0 {
+			matched++
+		}
+
+		msg := fmt.Sprintf("#%d by %s would be routed to the %s group, and was reviewed by", pr.Number, author, name)
+
+		if len(inGroup) > 0 {
+			msg += fmt.Sprintf(" %s from the group", strings.Join(inGroup, ", "))
+
+			if len(outsideGroup) > 0 {
+				msg += " along with"
+			}
+		}
+
+		if len(outsideGroup) > 0 {
+			msg += fmt.Sprintf(" %s from outside the group", strings.Join(outsideGroup, ", "))
+		}
+
+		fmt.Fprintln(stdout, msg)
+	}
+
+	fmt.Fprintf(stdout, "\n%d of %d routed pull requests were reviewed by someone in their group\n", matched, routed)
+
+	return 0
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+// fakeSimulateGh acts as gh for a repository where searching returns the given
+// pull requests, and the octo-org/reviewers team has the given members
+func fakeSimulateGh(t *testing.T, prs string, members string, calls *[][]string) ghExecutor {
+	t.Helper()
+
+	return func(args ...string) (string, string) {
+		t.Helper()
+
+		*calls = append(*calls, args)
+
+		switch {
+		case len(args) > 1 && args[0] == "pr" && args[1] == "list":
+			return prs, ""
+		case len(args) > 1 && args[0] == "api" && args[1] == "orgs/octo-org/teams/reviewers/members?role=all":
+			return members, ""
+		}
+
+		t.Errorf("unexpected call to gh: %v", args)
+
+		return "", ""
+	}
+}
+
+func Test_run_Simulate(t *testing.T) {
+	t.Parallel()
+
+	prs := `[
+		{"number":1,"author":{"login":"app/dependabot"},"reviews":[{"author":{"login":"octopus"},"state":"APPROVED"}]},
+		{"number":2,"author":{"login":"app/renovate"},"reviews":[]},
+		{"number":3,"author":{"login":"octocat"},"reviews":[
+			{"author":{"login":"octocat"},"state":"COMMENTED"},
+			{"author":{"login":"octokitten"},"state":"CHANGES_REQUESTED"},
+			{"author":{"login":"octodog"},"state":"APPROVED"},
+			{"author":{"login":"octokitten"},"state":"APPROVED"}
+		]},
+		{"number":4,"author":{"login":"octodog"},"reviews":[{"author":{"login":"octobear"},"state":"APPROVED"}]},
+		{"number":5,"author":{"login":"octobear"},"reviews":[]},
+		{"number":6,"author":{"login":"app/github-actions"},"reviews":[]}
+	]`
+
+	config := `
+		authors:
+			- match: ['dependabot[bot]']
+				group: deps
+			- match: ['renovate[bot]']
+				group: renovate
+			- match: ['*[bot]']
+				skip: true
+		repositories:
+			octocat/hello-world:
+				default: [octocat, octo-org/reviewers]
+				deps: [octopus]
+	`
+
+	tests := []struct {
+		name string
+		args []string
+		prs  string
+		exit int
+	}{
+		{
+			name: "when pull requests were created in the dates",
+			args: []string{"simulate", "--since", "2024-01-01", "--until", "2024-01-31"},
+			prs:  prs,
+			exit: 0,
+		},
+		{
+			name: "when only a start date is given",
+			args: []string{"simulate", "--since", "2024-01-01"},
+			prs:  prs,
+			exit: 0,
+		},
+		{
+			name: "when no pull requests were created in the dates",
+			args: []string{"simulate", "--since", "2024-01-01"},
+			prs:  `[]`,
+			exit: 0,
+		},
+		{
+			name: "when no start date is given",
+			args: []string{"simulate"},
+			prs:  prs,
+			exit: 1,
+		},
+		{
+			name: "when a date is invalid",
+			args: []string{"simulate", "--since", "2024-01-01", "--until", "yesterday"},
+			prs:  prs,
+			exit: 1,
+		},
+		{
+			name: "when given a pull request",
+			args: []string{"simulate", "--since", "2024-01-01", "123"},
+			prs:  prs,
+			exit: 1,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			configDir := writeConfigFileInTempDir(t, dedent(t, config))
+
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+
+			var calls [][]string
+
+			got := run(
+				append([]string{"--config-dir", configDir, "--repo", "octocat/hello-world"}, tt.args...),
+				stdout,
+				stderr,
+				fakeSimulateGh(t, tt.prs, `[{"login":"octodog"},{"login":"octobear"}]`, &calls),
+			)
+
+			if got != tt.exit {
+				t.Errorf("run() = %v, want %v", got, tt.exit)
+			}
+
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stdout))
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stderr))
+			snaps.MatchJSON(t, calls)
+		})
+	}
+}
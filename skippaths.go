@@ -0,0 +1,53 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+)
+
+// onlyChangesSkippedPaths checks if every one of the files matches at least one
+// of the patterns, which follow the same rules as CODEOWNERS
+func onlyChangesSkippedPaths(patterns []string, files []string) (bool, error) {
+	if len(files) == 0 {
+		return false, nil
+	}
+
+	res := make([]*regexp.Regexp, 0, len(patterns))
+
+	for _, pattern := range patterns {
+		re, err := codeownersPatternToRegexp(pattern)
+
+		if err != nil {
+			return false, fmt.Errorf("could not parse skip path %s: %w", pattern, err)
+		}
+
+		res = append(res, re)
+	}
+
+	for _, file := range files {
+		if !slices.ContainsFunc(res, func(re *regexp.Regexp) bool { return re.MatchString(file) }) {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// shouldSkipPullRequest checks if the pull request only changes files that do
+// not need to be reviewed, based on the given patterns
+func shouldSkipPullRequest(ghExec ghExecutor, patterns []string, repo, target string) (bool, error) {
+	pr, err := fetchPullRequest(ghExec, repo, target, "files")
+
+	if err != nil {
+		return false, err
+	}
+
+	files := make([]string, 0, len(pr.Files))
+
+	for _, file := range pr.Files {
+		files = append(files, file.Path)
+	}
+
+	return onlyChangesSkippedPaths(patterns, files)
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+// fakeSkipPathsGh acts as gh for a repository where each pull request changes
+// different files based on its number, with #1 only changing documentation
+func fakeSkipPathsGh(t *testing.T, calls *[][]string) ghExecutor {
+	t.Helper()
+
+	files := map[string]string{
+		"1": `[{"path":"README.md"},{"path":"docs/setup.md"},{"path":"CHANGELOG.md"}]`,
+		"2": `[{"path":"README.md"},{"path":"main.go"}]`,
+		"3": `[]`,
+	}
+
+	return func(args ...string) (string, string) {
+		t.Helper()
+
+		*calls = append(*calls, args)
+
+		switch {
+		case len(args) > 1 && args[0] == "pr" && args[1] == "list":
+			return `[
+				{"number":1,"url":"https://github.com/octocat/hello-world/pull/1","reviewRequests":[]},
+				{"number":2,"url":"https://github.com/octocat/hello-world/pull/2","reviewRequests":[]}
+			]`, ""
+		case len(args) > 2 && args[0] == "pr" && args[1] == "view" && strings.Contains(strings.Join(args, " "), "--json files"):
+			if args[2] == "13" {
+				return "", "GraphQL: Could not resolve to a PullRequest with the number of 13."
+			}
+
+			return fmt.Sprintf(`{"files":%s}`, files[args[2]]), ""
+		case len(args) > 2 && args[0] == "pr" && args[1] == "edit":
+			return fmt.Sprintf("https://github.com/octocat/hello-world/pull/%s", args[2]), ""
+		}
+
+		t.Errorf("unexpected call to gh: %v", args)
+
+		return "", ""
+	}
+}
+
+func Test_run_WithSkipPaths(t *testing.T) {
+	t.Parallel()
+
+	config := `
+		settings:
+			skip_paths: ['**/*.md', 'CHANGELOG*']
+		repositories:
+			octocat/hello-world: [octocat, octopus]
+	`
+
+	tests := []struct {
+		name string
+		args []string
+		exit int
+	}{
+		{
+			name: "when the pull request only changes skipped paths",
+			args: []string{"1"},
+			exit: 0,
+		},
+		{
+			name: "when the pull request changes other paths",
+			args: []string{"2"},
+			exit: 0,
+		},
+		{
+			name: "when the pull request does not change any files",
+			args: []string{"3"},
+			exit: 0,
+		},
+		{
+			name: "when forcing the request",
+			args: []string{"1", "--force"},
+			exit: 0,
+		},
+		{
+			name: "when doing a dry-run",
+			args: []string{"1", "--dry-run"},
+			exit: 0,
+		},
+		{
+			name: "when the files cannot be fetched",
+			args: []string{"13"},
+			exit: 1,
+		},
+		{
+			name: "when searching",
+			args: []string{"--search", "label:docs"},
+			exit: 0,
+		},
+		{
+			name: "when forcing the search",
+			args: []string{"--search", "label:docs", "--force"},
+			exit: 0,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			configDir := writeConfigFileInTempDir(t, dedent(t, config))
+
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+
+			var calls [][]string
+
+			got := run(
+				append([]string{"--config-dir", configDir, "--repo", "octocat/hello-world"}, tt.args...),
+				stdout,
+				stderr,
+				fakeSkipPathsGh(t, &calls),
+			)
+
+			if got != tt.exit {
+				t.Errorf("run() = %v, want %v", got, tt.exit)
+			}
+
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stdout))
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stderr))
+			snaps.MatchJSON(t, calls)
+		})
+	}
+}
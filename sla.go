@@ -0,0 +1,233 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// sla is how long a review request to a group can go unanswered, such as
+// "36 hours", "2 days", or "2 business days"
+type sla struct {
+	raw      string
+	amount   int
+	unit     string
+	business bool
+}
+
+var slaRe = regexp.MustCompile(`^(\d+) (business )?(hour|day)s?$`)
+
+func (s *sla) UnmarshalYAML(value *yaml.Node) error {
+	var raw string
+
+	if err := value.Decode(&raw); err != nil {
+		return err
+	}
+
+	matches := slaRe.FindStringSubmatch(strings.TrimSpace(raw))
+
+	// business hours are not supported as we do not know when the working day is
+	if matches == nil || (matches[2] != "" && matches[3] == "hour") {
+		return fmt.Errorf("line %d: sla must be a number of hours, days, or business days, not `%s`", value.Line, raw)
+	}
+
+	amount, _ := strconv.Atoi(matches[1])
+
+	*s = sla{raw: raw, amount: amount, unit: matches[3], business: matches[2] != ""}
+
+	return nil
+}
+
+func (s sla) String() string {
+	return s.raw
+}
+
+// deadline returns when a review requested at the given time is due by
+func (s sla) deadline(requestedAt time.Time) time.Time {
+	if s.unit == "hour" {
+		return requestedAt.Add(time.Duration(s.amount) * time.Hour)
+	}
+
+	if !s.business {
+		return requestedAt.AddDate(0, 0, s.amount)
+	}
+
+	deadline := requestedAt
+
+	for added := 0; added < s.amount; {
+		deadline = deadline.AddDate(0, 0, 1)
+
+		if deadline.Weekday() != time.Saturday && deadline.Weekday() != time.Sunday {
+			added++
+		}
+	}
+
+	return deadline
+}
+
+// slaBreach is a pull request with review requests to a group that have gone
+// unanswered for longer than the sla of that group
+type slaBreach struct {
+	repo    string
+	group   string
+	sla     sla
+	request staleRequest
+}
+
+func (b slaBreach) handles() string {
+	handles := make([]string, 0, len(b.request.reviewers))
+
+	for _, reviewer := range b.request.reviewers {
+		handles = append(handles, reviewer.Handle)
+	}
+
+	return strings.Join(handles, ", ")
+}
+
+// findSLABreaches checks each group with an sla in every configured repository
+// for review requests that have gone unanswered for too long, returning false
+// if there are no groups with an sla
+func findSLABreaches(ghExec ghExecutor, conf config, now time.Time) ([]slaBreach, bool, error) {
+	checked := false
+
+	var breaches []slaBreach
+
+	repos := make([]string, 0, len(conf.Repositories))
+
+	// patterns cannot be searched, as they do not name a specific repository
+	for repo := range conf.Repositories {
+		if repo != "*" && !isRepositoryPattern(repo) {
+			repos = append(repos, repo)
+		}
+	}
+
+	slices.Sort(repos)
+
+	for _, repo := range repos {
+		groups := conf.Repositories[repo]
+		names := make([]string, 0, len(groups))
+
+		for name := range groups {
+			names = append(names, name)
+		}
+
+		slices.Sort(names)
+
+		for _, name := range names {
+			g := groups[name]
+
+			if g.SLA.amount == 0 {
+				continue
+			}
+
+			checked = true
+
+			stale, err := findStaleRequests(ghExec, repo, g.Reviewers, func(requestedAt time.Time) bool {
+				return now.After(g.SLA.deadline(requestedAt))
+			})
+
+			if err != nil {
+				return nil, checked, fmt.Errorf("could not check %s: %w", repo, err)
+			}
+
+			for _, request := range stale {
+				breaches = append(breaches, slaBreach{repo: repo, group: name, sla: g.SLA, request: request})
+			}
+		}
+	}
+
+	return breaches, checked, nil
+}
+
+// printSLABreaches outputs the breaches grouped by repository and group
+func printSLABreaches(w io.Writer, breaches []slaBreach, now time.Time) {
+	if len(breaches) == 0 {
+		fmt.Fprintln(w, "all review requests are within their sla")
+
+		return
+	}
+
+	for i, breach := range breaches {
+		if i == 0 || breach.repo != breaches[i-1].repo || breach.group != breaches[i-1].group {
+			fmt.Fprintf(w, "%s (%s group, %s):\n", breach.repo, breach.group, breach.sla)
+		}
+
+		fmt.Fprintf(
+			w,
+			"  - %s has been waiting on %s for %d days\n",
+			breach.request.pr.URL,
+			breach.handles(),
+			breach.request.daysWaited(now),
+		)
+	}
+}
+
+// writeSLABreachesCSV outputs the breaches as csv, with a row for each pull request
+func writeSLABreachesCSV(w io.Writer, breaches []slaBreach, now time.Time) error {
+	cw := csv.NewWriter(w)
+
+	_ = cw.Write([]string{"repository", "group", "sla", "pull_request", "reviewers", "days_waiting"})
+
+	for _, breach := range breaches {
+		_ = cw.Write([]string{
+			breach.repo,
+			breach.group,
+			breach.sla.String(),
+			breach.request.pr.URL,
+			breach.handles(),
+			strconv.Itoa(breach.request.daysWaited(now)),
+		})
+	}
+
+	cw.Flush()
+
+	return cw.Error()
+}
+
+// reportSLAs lists the pull requests in each configured repository that have
+// review requests to a group which have gone unanswered for longer than the
+// sla of that group
+func reportSLAs(stdout, stderr io.Writer, ghExec ghExecutor, conf config, format string) int {
+	if format != "text" && format != "csv" {
+		fmt.Fprintf(stderr, "unsupported format %s, must be either text or csv\n", format)
+
+		return 1
+	}
+
+	now := time.Now()
+
+	breaches, checked, err := findSLABreaches(ghExec, conf, now)
+
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+
+		return 1
+	}
+
+	if !checked {
+		fmt.Fprintln(stderr, "none of the configured groups have an sla")
+
+		return 1
+	}
+
+	if format == "text" {
+		printSLABreaches(stdout, breaches, now)
+
+		return 0
+	}
+
+	if err := writeSLABreachesCSV(stdout, breaches, now); err != nil {
+		fmt.Fprintf(stderr, "could not write csv: %v\n", err)
+
+		return 1
+	}
+
+	return 0
+}
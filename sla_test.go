@@ -0,0 +1,214 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+// fakeSLAGh acts as gh for repositories with the given open pull requests, and
+// the timeline of each of those pull requests keyed by their api path
+func fakeSLAGh(t *testing.T, prs map[string]string, timelines map[string]string, calls *[][]string) ghExecutor {
+	t.Helper()
+
+	return func(args ...string) (string, string) {
+		t.Helper()
+
+		*calls = append(*calls, args)
+
+		switch {
+		case len(args) > 3 && args[0] == "pr" && args[1] == "list":
+			if out, ok := prs[args[3]]; ok {
+				return out, ""
+			}
+
+			return "", "GraphQL: Could not resolve to a Repository with the name '" + args[3] + "'."
+		case len(args) > 1 && args[0] == "api":
+			return timelines[args[1]], ""
+		}
+
+		t.Errorf("unexpected call to gh: %v", args)
+
+		return "", ""
+	}
+}
+
+func Test_run_SLA(t *testing.T) {
+	t.Parallel()
+
+	prs := map[string]string{
+		"octocat/hello-world": `[
+			{"number":1,"url":"https://github.com/octocat/hello-world/pull/1","reviewRequests":[{"login":"octocat"},{"login":"octopus"}]},
+			{"number":2,"url":"https://github.com/octocat/hello-world/pull/2","reviewRequests":[{"login":"octodog"}]}
+		]`,
+		"octocat/spoon-knife": `[
+			{"number":3,"url":"https://github.com/octocat/spoon-knife/pull/3","reviewRequests":[{"login":"octodog"}]}
+		]`,
+	}
+
+	timelines := map[string]string{
+		"repos/octocat/hello-world/issues/1/timeline": "[" + requestedDaysAgo("octocat", 10) + "," + requestedDaysAgo("octopus", 0) + "]",
+		"repos/octocat/hello-world/issues/2/timeline": "[" + requestedDaysAgo("octodog", 10) + "]",
+		"repos/octocat/spoon-knife/issues/3/timeline": "[" + requestedDaysAgo("octodog", 2) + "]",
+	}
+
+	tests := []struct {
+		name   string
+		args   []string
+		config string
+		exit   int
+	}{
+		{
+			name: "when there are requests that have breached their sla",
+			config: `
+				repositories:
+					'*':
+						everyone:
+							sla: 1 day
+							reviewers: [octocat, octodog]
+					octocat/hello-world:
+						default:
+							sla: 2 business days
+							reviewers: [octocat, octopus]
+						infra:
+							sla: 36 hours
+							reviewers: [octodog]
+						security: [octodog]
+					octocat/spoon-knife:
+						default:
+							sla: 3 days
+							reviewers: [octodog]
+						infra:
+							sla: 24 hours
+							reviewers: [octodog]
+			`,
+			exit: 0,
+		},
+		{
+			name: "when outputting as csv",
+			args: []string{"--format", "csv"},
+			config: `
+				repositories:
+					octocat/hello-world:
+						default:
+							sla: 2 business days
+							reviewers: [octocat, octopus]
+						infra:
+							sla: 36 hours
+							reviewers: [octodog]
+			`,
+			exit: 0,
+		},
+		{
+			name: "when outputting as csv and all requests are within their sla",
+			args: []string{"--format", "csv"},
+			config: `
+				repositories:
+					octocat/spoon-knife:
+						default:
+							sla: 3 days
+							reviewers: [octodog]
+			`,
+			exit: 0,
+		},
+		{
+			name: "when outputting in an unsupported format",
+			args: []string{"--format", "xlsx"},
+			config: `
+				repositories:
+					octocat/hello-world:
+						default:
+							sla: 2 business days
+							reviewers: [octocat, octopus]
+			`,
+			exit: 1,
+		},
+		{
+			name: "when all requests are within their sla",
+			config: `
+				repositories:
+					octocat/hello-world:
+						default:
+							sla: 20 business days
+							reviewers: [octocat, octopus]
+					octocat/spoon-knife:
+						default: [octodog]
+			`,
+			exit: 0,
+		},
+		{
+			name: "when no groups have an sla",
+			config: `
+				repositories:
+					octocat/hello-world:
+						- octocat
+			`,
+			exit: 1,
+		},
+		{
+			name: "when the current repository is only configured through its owner",
+			config: `
+				owners:
+					octocat:
+						default:
+							sla: 1 day
+							reviewers: [octocat]
+				repositories:
+					octocat/spoon-knife:
+						default: [octodog]
+			`,
+			exit: 1,
+		},
+		{
+			name: "when a repository cannot be checked",
+			config: `
+				repositories:
+					octocat/does-not-exist:
+						default:
+							sla: 1 day
+							reviewers: [octocat]
+			`,
+			exit: 1,
+		},
+		{
+			name: "when an sla is invalid",
+			config: `
+				repositories:
+					octocat/hello-world:
+						default:
+							sla: 2 business hours
+							reviewers: [octocat]
+			`,
+			exit: 1,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			configDir := writeConfigFileInTempDir(t, dedent(t, tt.config))
+
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+
+			var calls [][]string
+
+			got := run(
+				append([]string{"--config-dir", configDir, "--repo", "octocat/hello-world", "sla"}, tt.args...),
+				stdout,
+				stderr,
+				fakeSLAGh(t, prs, timelines, &calls),
+			)
+
+			if got != tt.exit {
+				t.Errorf("run() = %v, want %v", got, tt.exit)
+			}
+
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stdout))
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stderr))
+			snaps.MatchJSON(t, calls)
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+type snoozeOptions struct {
+	repo      string
+	target    string
+	until     string
+	statePath string
+	stateGist string
+	isDryRun  bool
+}
+
+// snoozeKey returns the key used to track when reminders for a pull request
+// are snoozed until
+func snoozeKey(repo string, number int) string {
+	return fmt.Sprintf("%s#%d", strings.ToLower(repo), number)
+}
+
+// isSnoozed checks if reminders for the given pull request are snoozed, which
+// they are up to and including the date they are snoozed until
+func isSnoozed(snoozed map[string]string, repo string, number int, now time.Time) bool {
+	until, ok := snoozed[snoozeKey(repo, number)]
+
+	return ok && now.Format(time.DateOnly) <= until
+}
+
+// snooze records that reminders and escalations should not happen for the
+// pull request until after the given date
+func snooze(stdout, stderr io.Writer, ghExec ghExecutor, opts snoozeOptions) int {
+	if opts.until == "" {
+		fmt.Fprintln(stderr, "please provide the date to snooze reminders until with --until")
+
+		return 1
+	}
+
+	if _, err := time.Parse(time.DateOnly, opts.until); err != nil {
+		fmt.Fprintf(stderr, "%s is not a valid date, as it should be in the format of YYYY-MM-DD\n", opts.until)
+
+		return 1
+	}
+
+	args := []string{"pr", "view"}
+
+	if opts.target != "" {
+		args = append(args, opts.target)
+	}
+
+	out, errMsg := ghExec(append(args, "--repo", opts.repo, "--json", "number,url")...)
+
+	if errMsg != "" {
+		fmt.Fprintf(stderr, "could not find pull request: %s\n", strings.TrimSpace(errMsg))
+
+		return 1
+	}
+
+	var pr struct {
+		Number int    `json:"number"`
+		URL    string `json:"url"`
+	}
+
+	if err := json.Unmarshal([]byte(out), &pr); err != nil {
+		fmt.Fprintf(stderr, "could not parse pull request: %v\n", err)
+
+		return 1
+	}
+
+	if opts.isDryRun {
+		fmt.Fprintf(stdout, "would have snoozed reminders on %s until %s\n", pr.URL, opts.until)
+
+		return 0
+	}
+
+	st, err := readState(ghExec, opts.statePath, opts.stateGist)
+
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+
+		return 1
+	}
+
+	st.Snoozed[snoozeKey(opts.repo, pr.Number)] = opts.until
+
+	if err := writeState(ghExec, opts.statePath, opts.stateGist, st); err != nil {
+		fmt.Fprintf(stderr, "could not save state: %v\n", err)
+
+		return 1
+	}
+
+	fmt.Fprintf(stdout, "snoozed reminders on %s until %s\n", pr.URL, opts.until)
+
+	return 0
+}
@@ -0,0 +1,228 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+// fakeSnoozeGh acts as gh for viewing pull requests, with the pull request of
+// the current branch being #7
+func fakeSnoozeGh(t *testing.T, calls *[][]string) ghExecutor {
+	t.Helper()
+
+	return func(args ...string) (string, string) {
+		t.Helper()
+
+		*calls = append(*calls, args)
+
+		if len(args) > 1 && args[0] == "pr" && args[1] == "view" {
+			number := "7"
+
+			if args[2] != "--repo" {
+				number = args[2]
+			}
+
+			if number == "404" {
+				return "", "GraphQL: Could not resolve to a PullRequest with the number of 404."
+			}
+
+			return fmt.Sprintf(`{"number":%s,"url":"https://github.com/octocat/hello-world/pull/%s"}`, number, number), ""
+		}
+
+		t.Errorf("unexpected call to gh: %v", args)
+
+		return "", ""
+	}
+}
+
+func Test_run_Snooze(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		args  []string
+		state string
+		exit  int
+	}{
+		{
+			name:  "when snoozing a pull request",
+			args:  []string{"snooze", "12", "--until", "2030-01-31"},
+			state: "",
+			exit:  0,
+		},
+		{
+			name:  "when snoozing the pull request of the current branch",
+			args:  []string{"snooze", "--until", "2030-01-31"},
+			state: "",
+			exit:  0,
+		},
+		{
+			name:  "when there is existing state",
+			args:  []string{"snooze", "12", "--until", "2030-02-28"},
+			state: `{"roundRobin": {"octocat/hello-world#triage": 1}, "snoozed": {"octocat/hello-world#12": "2030-01-31"}}`,
+			exit:  0,
+		},
+		{
+			name:  "when doing a dry-run",
+			args:  []string{"snooze", "12", "--until", "2030-01-31", "--dry-run"},
+			state: "",
+			exit:  0,
+		},
+		{
+			name:  "when the pull request does not exist",
+			args:  []string{"snooze", "404", "--until", "2030-01-31"},
+			state: "",
+			exit:  1,
+		},
+		{
+			name:  "when no date is given",
+			args:  []string{"snooze", "12"},
+			state: "",
+			exit:  1,
+		},
+		{
+			name:  "when the date is invalid",
+			args:  []string{"snooze", "12", "--until", "31/01/2030"},
+			state: "",
+			exit:  1,
+		},
+		{
+			name:  "when given multiple pull requests",
+			args:  []string{"snooze", "12", "13", "--until", "2030-01-31"},
+			state: "",
+			exit:  1,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			configDir := writeConfigFileInTempDir(t, dedent(t, `
+				repositories:
+					octocat/hello-world:
+						- octocat
+			`))
+
+			statePath := stateFilePath(configDir)
+
+			if tt.state != "" {
+				if err := os.WriteFile(statePath, []byte(tt.state), 0600); err != nil {
+					t.Fatalf("could not write state: %v", err)
+				}
+			}
+
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+
+			var calls [][]string
+
+			got := run(
+				append([]string{"--config-dir", configDir, "--repo", "octocat/hello-world"}, tt.args...),
+				stdout,
+				stderr,
+				fakeSnoozeGh(t, &calls),
+			)
+
+			if got != tt.exit {
+				t.Errorf("run() = %v, want %v", got, tt.exit)
+			}
+
+			st, _ := os.ReadFile(filepath.Clean(statePath))
+
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stdout))
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stderr))
+			snaps.MatchJSON(t, calls)
+			snaps.MatchSnapshot(t, strings.TrimSpace(string(st)))
+		})
+	}
+}
+
+func Test_run_Remind_Snoozed(t *testing.T) {
+	t.Parallel()
+
+	prs := `[
+		{"number":1,"url":"https://github.com/octocat/hello-world/pull/1","reviewRequests":[{"login":"octocat"}]},
+		{"number":2,"url":"https://github.com/octocat/hello-world/pull/2","reviewRequests":[{"login":"octocat"}]},
+		{"number":3,"url":"https://github.com/octocat/hello-world/pull/3","reviewRequests":[{"login":"octocat"}]}
+	]`
+
+	timelines := map[string]string{
+		"1": "[" + requestedDaysAgo("octocat", 5) + "]",
+		"2": "[" + requestedDaysAgo("octocat", 5) + "]",
+		"3": "[" + requestedDaysAgo("octocat", 5) + "]",
+	}
+
+	tests := []struct {
+		name  string
+		args  []string
+		state string
+		exit  int
+	}{
+		{
+			name:  "when some pull requests are snoozed",
+			args:  []string{"remind"},
+			state: `{"snoozed": {"octocat/hello-world#1": "2999-12-31", "octocat/hello-world#2": "2000-01-01"}}`,
+			exit:  0,
+		},
+		{
+			name:  "when doing a dry-run",
+			args:  []string{"remind", "--dry-run"},
+			state: `{"snoozed": {"octocat/hello-world#3": "2999-12-31"}}`,
+			exit:  0,
+		},
+		{
+			name:  "when the state is invalid",
+			args:  []string{"remind"},
+			state: `{"snoozed": [}`,
+			exit:  1,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			configDir := writeConfigFileInTempDir(t, dedent(t, `
+				repositories:
+					octocat/hello-world:
+						default:
+							reviewers: [octocat]
+							escalation:
+								after: 4
+								to: leads
+						leads: [octodog]
+			`))
+
+			if err := os.WriteFile(stateFilePath(configDir), []byte(tt.state), 0600); err != nil {
+				t.Fatalf("could not write state: %v", err)
+			}
+
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+
+			var calls [][]string
+
+			got := run(
+				append([]string{"--config-dir", configDir, "--repo", "octocat/hello-world"}, tt.args...),
+				stdout,
+				stderr,
+				fakeRemindGh(t, prs, timelines, &calls),
+			)
+
+			if got != tt.exit {
+				t.Errorf("run() = %v, want %v", got, tt.exit)
+			}
+
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stdout))
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stderr))
+			snaps.MatchJSON(t, calls)
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+var errSopsNotFound = errors.New("sops could not be found")
+
+// isSopsEncrypted checks if the given yaml document has been encrypted by sops,
+// which is indicated by the presence of a top-level "sops" metadata key
+func isSopsEncrypted(content []byte) bool {
+	var doc struct {
+		Sops map[string]any `yaml:"sops"`
+	}
+
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		return false
+	}
+
+	return doc.Sops != nil
+}
+
+// decryptSopsFile uses the sops binary to decrypt the given file, which will in
+// turn use whatever key sources (age, pgp, cloud kms) the user has available
+func decryptSopsFile(file string) ([]byte, error) {
+	bin, err := exec.LookPath("sops")
+
+	if err != nil {
+		return nil, errSopsNotFound
+	}
+
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+
+	// json configs are parsed as json, so they need to stay json once decrypted
+	format := "yaml"
+
+	if isJSONConfig(file) {
+		format = "json"
+	}
+
+	cmd := exec.Command(bin, "--decrypt", "--input-type", format, "--output-type", format, file)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+
+	if err := cmd.Run(); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+
+		if msg == "" {
+			msg = err.Error()
+		}
+
+		return nil, fmt.Errorf("could not decrypt %s with sops: %s", file, msg)
+	}
+
+	return stdout.Bytes(), nil
+}
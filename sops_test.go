@@ -0,0 +1,171 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+const sopsEncryptedConfig = `
+	repositories:
+		octocat/hello-world:
+			- ENC[AES256_GCM,data:tbUe1vI=,iv:NZBGTqo=,tag:Avd0Zfs=,type:str]
+	sops:
+		age:
+			- recipient: age1ql3z7hjy54pw3hyww5ayyfg7zqgvc7w3j2elw8zmrj2kg5sfn9aqmcac8p
+		lastmodified: "2024-04-01T00:00:00Z"
+		mac: ENC[AES256_GCM,data:a2V5,iv:aXY=,tag:dGFn,type:str]
+		version: 3.8.1
+`
+
+func Test_isSopsEncrypted(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		content string
+		want    bool
+	}{
+		{
+			name:    "when the config is encrypted",
+			content: sopsEncryptedConfig,
+			want:    true,
+		},
+		{
+			name: "when the config is not encrypted",
+			content: `
+				repositories:
+					octocat/hello-world:
+						- octodog
+			`,
+			want: false,
+		},
+		{
+			name:    "when the config is not valid yaml",
+			content: "!!!",
+			want:    false,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := isSopsEncrypted([]byte(dedent(t, tt.content))); got != tt.want {
+				t.Errorf("isSopsEncrypted() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_run_WithSopsEncryptedConfig_WithoutSops(t *testing.T) {
+	t.Setenv("PATH", "")
+
+	configDir := writeConfigFileInTempDir(t, dedent(t, sopsEncryptedConfig))
+
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+
+	got := run(
+		[]string{"--config-dir", configDir, "--repo", "octocat/hello-world"},
+		stdout,
+		stderr,
+		expectNoCallToGh(t),
+	)
+
+	if got != 1 {
+		t.Errorf("run() = %v, want %v", got, 1)
+	}
+
+	snaps.MatchSnapshot(t, normalizeStdStream(t, stdout))
+	snaps.MatchSnapshot(t, normalizeStdStream(t, stderr))
+}
+
+func Test_run_WithSopsEncryptedConfig(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake sops binary is a shell script")
+	}
+
+	binDir := writeConfigFileInTempDir(t, "")
+
+	// a fake sops binary which "decrypts" the config by ignoring it entirely
+	script := "#!/bin/sh\nprintf 'repositories:\\n  octocat/hello-world:\\n    - octodog\\n'\n"
+
+	err := os.WriteFile(filepath.Join(binDir, "sops"), []byte(script), 0700) //nolint:gosec // it needs to be executable
+	if err != nil {
+		t.Fatalf("could not create fake sops: %v", err)
+	}
+
+	t.Setenv("PATH", binDir)
+
+	configDir := writeConfigFileInTempDir(t, dedent(t, sopsEncryptedConfig))
+
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+
+	got := run(
+		[]string{"--config-dir", configDir, "--repo", "octocat/hello-world", "--dry-run"},
+		stdout,
+		stderr,
+		expectNoCallToGh(t),
+	)
+
+	if got != 0 {
+		t.Errorf("run() = %v, want %v", got, 0)
+	}
+
+	snaps.MatchSnapshot(t, normalizeStdStream(t, stdout))
+	snaps.MatchSnapshot(t, normalizeStdStream(t, stderr))
+}
+
+func Test_run_WithSopsEncryptedJSONConfig(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake sops binary is a shell script")
+	}
+
+	binDir := writeConfigFileInTempDir(t, "")
+
+	// a fake sops binary which only "decrypts" the config if asked for json
+	script := `#!/bin/sh
+case "$*" in
+  *"--input-type json --output-type json"*) printf '{"repositories":{"octocat/hello-world":["octodog"]}}\n' ;;
+  *) echo "expected to be decrypting json: $*" >&2; exit 1 ;;
+esac
+`
+
+	err := os.WriteFile(filepath.Join(binDir, "sops"), []byte(script), 0700) //nolint:gosec // it needs to be executable
+	if err != nil {
+		t.Fatalf("could not create fake sops: %v", err)
+	}
+
+	t.Setenv("PATH", binDir)
+
+	configDir := writeConfigFileInTempDir(t, "")
+	configFile := filepath.Join(configDir, "gh-rr.json")
+
+	err = os.WriteFile(configFile, []byte(`{"repositories":"ENC[AES256_GCM,data:...,type:str]","sops":{"version":"3.8.1"}}`), 0600)
+	if err != nil {
+		t.Fatalf("could not create test config: %v", err)
+	}
+
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+
+	got := run(
+		[]string{"--config-dir", configDir, "--config", configFile, "--repo", "octocat/hello-world", "--dry-run"},
+		stdout,
+		stderr,
+		expectNoCallToGh(t),
+	)
+
+	if got != 0 {
+		t.Errorf("run() = %v, want %v", got, 0)
+	}
+
+	snaps.MatchSnapshot(t, normalizeStdStream(t, stdout))
+	snaps.MatchSnapshot(t, normalizeStdStream(t, stderr))
+}
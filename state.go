@@ -0,0 +1,143 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// state is information that gh-rr keeps between runs, such as whose turn it
+// is to be assigned next when using round-robin
+type state struct {
+	RoundRobin map[string]int `json:"roundRobin,omitempty"`
+
+	// Snoozed is the date (in the YYYY-MM-DD format) that reminders are snoozed
+	// until for each pull request
+	Snoozed map[string]string `json:"snoozed,omitempty"`
+}
+
+// initMaps ensures the maps of the state are not nil, so they can be written to
+func (st *state) initMaps() {
+	if st.RoundRobin == nil {
+		st.RoundRobin = map[string]int{}
+	}
+
+	if st.Snoozed == nil {
+		st.Snoozed = map[string]string{}
+	}
+}
+
+// stateFilePath returns the path to the file that state is stored in, which
+// lives alongside the configuration file
+func stateFilePath(configDir string) string {
+	return filepath.Join(configDir, ".gh-rr-state.json")
+}
+
+// stateGistFilename is the name of the file within a gist that state is synced
+// through, so that it can be shared between machines
+const stateGistFilename = ".gh-rr-state.json"
+
+// loadState reads the state from the given file, returning an empty state if
+// the file does not exist yet
+func loadState(file string) (state, error) {
+	st := state{}
+	st.initMaps()
+
+	out, err := os.ReadFile(file)
+
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return st, nil
+		}
+
+		return st, err
+	}
+
+	if err := json.Unmarshal(out, &st); err != nil {
+		return st, fmt.Errorf("could not parse %s: %w", file, err)
+	}
+
+	st.initMaps()
+
+	return st, nil
+}
+
+func saveState(file string, st state) error {
+	out, err := json.MarshalIndent(st, "", "  ")
+
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(file, append(out, '\n'), 0600)
+}
+
+// loadGistState reads the state from the given gist, returning an empty state
+// if the gist does not have a state file yet
+func loadGistState(ghExec ghExecutor, gist string) (state, error) {
+	st := state{}
+	st.initMaps()
+
+	out, errMsg := ghExec(
+		"api", "gists/"+gist,
+		"--jq", fmt.Sprintf(`.files[%q].content // ""`, stateGistFilename),
+	)
+
+	if errMsg != "" {
+		return st, fmt.Errorf("could not read state from gist %s: %s", gist, strings.TrimSpace(errMsg))
+	}
+
+	if strings.TrimSpace(out) == "" {
+		return st, nil
+	}
+
+	if err := json.Unmarshal([]byte(out), &st); err != nil {
+		return st, fmt.Errorf("could not parse state from gist %s: %w", gist, err)
+	}
+
+	st.initMaps()
+
+	return st, nil
+}
+
+func saveGistState(ghExec ghExecutor, gist string, st state) error {
+	out, err := json.MarshalIndent(st, "", "  ")
+
+	if err != nil {
+		return err
+	}
+
+	_, errMsg := ghExec(
+		"api", "-X", "PATCH", "gists/"+gist,
+		"-f", fmt.Sprintf("files[%s][content]=%s", stateGistFilename, out),
+	)
+
+	if errMsg != "" {
+		return errors.New(strings.TrimSpace(errMsg))
+	}
+
+	return nil
+}
+
+// readState reads the state from the given gist if there is one, otherwise
+// from the given file
+func readState(ghExec ghExecutor, file, gist string) (state, error) {
+	if gist != "" {
+		return loadGistState(ghExec, gist)
+	}
+
+	return loadState(file)
+}
+
+// writeState saves the state to the given gist if there is one, otherwise to
+// the given file
+func writeState(ghExec ghExecutor, file, gist string, st state) error {
+	if gist != "" {
+		return saveGistState(ghExec, gist, st)
+	}
+
+	return saveState(file, st)
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+type syncOptions struct {
+	file     string
+	write    bool
+	isDryRun bool
+}
+
+// teamDrift is the difference between the reviewers of a group and the
+// members of the team that it is linked to
+type teamDrift struct {
+	loc     groupLocation
+	team    string
+	missing []string
+	extra   []string
+}
+
+// diffTeamMembers returns the members of the team that are not reviewers, and
+// the reviewers that are not members of the team
+func diffTeamMembers(reviewers *yaml.Node, members []string) ([]string, []string) {
+	var missing, extra []string
+
+	handles := make([]string, 0, len(reviewers.Content))
+
+	for _, node := range reviewers.Content {
+		handles = append(handles, reviewerNodeHandle(node))
+	}
+
+	for _, member := range members {
+		if !slices.ContainsFunc(handles, func(handle string) bool { return strings.EqualFold(handle, member) }) {
+			missing = append(missing, member)
+		}
+	}
+
+	for _, handle := range handles {
+		if !slices.ContainsFunc(members, func(member string) bool { return strings.EqualFold(handle, member) }) {
+			extra = append(extra, handle)
+		}
+	}
+
+	return missing, extra
+}
+
+// applyTeamDrift updates the reviewers so that they match the members of the
+// team, keeping the details of any reviewers that are still members
+func applyTeamDrift(reviewers *yaml.Node, drift teamDrift) {
+	kept := reviewers.Content[:0]
+
+	for _, node := range reviewers.Content {
+		if !slices.Contains(drift.extra, reviewerNodeHandle(node)) {
+			kept = append(kept, node)
+		}
+	}
+
+	for _, member := range drift.missing {
+		kept = append(kept, scalarNode(member))
+	}
+
+	reviewers.Content = kept
+}
+
+// syncTeams checks the reviewers of every group that is linked to a team
+// against the current members of that team, optionally updating them
+func syncTeams(stdout, stderr io.Writer, ghExec ghExecutor, opts syncOptions) int {
+	doc, original, err := loadConfigDocument(opts.file)
+
+	if err != nil {
+		printConfigEditError(stderr, opts.file, err)
+
+		return 1
+	}
+
+	cache := &teamMemberCache{ghExec: ghExec, members: make(map[string][]string)}
+	linked := 0
+
+	var drifts []teamDrift
+
+	walkGroups(doc, func(loc groupLocation, g *yaml.Node, reviewers *yaml.Node) {
+		team := mappingValue(g, "team")
+
+		if err != nil || team == nil || team.Value == "" {
+			return
+		}
+
+		linked++
+
+		members, isTeam, fetchErr := cache.membersOf(team.Value)
+
+		if fetchErr != nil {
+			err = fetchErr
+
+			return
+		}
+
+		if !isTeam {
+			err = fmt.Errorf("the team of %s should be in the format of <org>/<team>, not %s", loc, team.Value)
+
+			return
+		}
+
+		missing, extra := diffTeamMembers(reviewers, members)
+
+		if len(missing) == 0 && len(extra) == 0 {
+			return
+		}
+
+		drift := teamDrift{loc: loc, team: team.Value, missing: missing, extra: extra}
+		drifts = append(drifts, drift)
+
+		if opts.write {
+			applyTeamDrift(reviewers, drift)
+		}
+	})
+
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+
+		return 1
+	}
+
+	if linked == 0 {
+		fmt.Fprintln(stderr, "none of the configured groups are linked to a team")
+
+		return 1
+	}
+
+	if len(drifts) == 0 {
+		fmt.Fprintln(stdout, "all groups are in sync with their teams")
+
+		return 0
+	}
+
+	if opts.write {
+		if err := saveConfigDocument(stdout, opts.file, doc, original, opts.isDryRun); err != nil {
+			fmt.Fprintln(stderr, err)
+
+			return 1
+		}
+	}
+
+	for _, drift := range drifts {
+		switch {
+		case opts.write && opts.isDryRun:
+			fmt.Fprintf(stdout, "would have updated %s to match %s:\n", drift.loc, drift.team)
+		case opts.write:
+			fmt.Fprintf(stdout, "updated %s to match %s:\n", drift.loc, drift.team)
+		default:
+			fmt.Fprintf(stdout, "%s is out of sync with %s:\n", drift.loc, drift.team)
+		}
+
+		for _, member := range drift.missing {
+			fmt.Fprintf(stdout, "  + %s\n", member)
+		}
+
+		for _, handle := range drift.extra {
+			fmt.Fprintf(stdout, "  - %s\n", handle)
+		}
+	}
+
+	// when only checking, drift is treated as a failure so this can be used in ci
+	if !opts.write {
+		return 1
+	}
+
+	return 0
+}
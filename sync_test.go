@@ -0,0 +1,163 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+func Test_run_Sync(t *testing.T) {
+	t.Parallel()
+
+	teams := map[string]string{
+		"orgs/octo-org/teams/backend/members?role=all":         `[{"login":"octocat"},{"login":"priyak"}]`,
+		"orgs/octo-org/teams/platform/members?role=all":        `[{"login":"octodog"}]`,
+		"orgs/octo-org/teams/platform/members?role=maintainer": `[{"login":"octodog"}]`,
+	}
+
+	config := `
+		repositories:
+			my-org/billing:
+				backend:
+					team: octo-org/backend
+					reviewers:
+						- handle: OctoCat
+							name: Octo Cat
+						- octopus # left in march
+				platform:
+					team: octo-org/platform
+					reviewers: [octodog]
+				leads:
+					team: team-maintainers:octo-org/platform
+					reviewers: []
+				security: [octokitten]
+	`
+
+	tests := []struct {
+		name   string
+		args   []string
+		config string
+		exit   int
+	}{
+		{
+			name:   "when checking for drift",
+			args:   []string{"sync", "--check"},
+			config: config,
+			exit:   1,
+		},
+		{
+			name:   "when checking for drift implicitly",
+			args:   []string{"sync"},
+			config: config,
+			exit:   1,
+		},
+		{
+			name:   "when writing the changes",
+			args:   []string{"sync", "--write"},
+			config: config,
+			exit:   0,
+		},
+		{
+			name:   "when doing a dry-run of writing the changes",
+			args:   []string{"sync", "--write", "--dry-run"},
+			config: config,
+			exit:   0,
+		},
+		{
+			name: "when everything is in sync",
+			args: []string{"sync", "--check"},
+			config: `
+				repositories:
+					my-org/billing:
+						platform:
+							team: octo-org/platform
+							reviewers: [OctoDog]
+			`,
+			exit: 0,
+		},
+		{
+			name: "when the groups of an owner are linked to a team",
+			args: []string{"sync", "--write"},
+			config: `
+				owners:
+					my-org:
+						backend:
+							team: octo-org/backend
+							reviewers: [octopus]
+			`,
+			exit: 0,
+		},
+		{
+			name: "when no groups are linked to a team",
+			args: []string{"sync"},
+			config: `
+				repositories:
+					my-org/billing: [octocat]
+			`,
+			exit: 1,
+		},
+		{
+			name: "when a team cannot be fetched",
+			args: []string{"sync"},
+			config: `
+				repositories:
+					my-org/billing:
+						default:
+							team: octo-org/nope
+							reviewers: [octocat]
+			`,
+			exit: 1,
+		},
+		{
+			name: "when a team is not in the right format",
+			args: []string{"sync"},
+			config: `
+				repositories:
+					my-org/billing:
+						default:
+							team: backend
+							reviewers: [octocat]
+			`,
+			exit: 1,
+		},
+		{
+			name:   "when checking and writing at the same time",
+			args:   []string{"sync", "--check", "--write"},
+			config: config,
+			exit:   1,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			configDir := writeConfigFileInTempDir(t, dedent(t, tt.config))
+
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+
+			var calls [][]string
+
+			got := run(
+				append([]string{"--config-dir", configDir}, tt.args...),
+				stdout,
+				stderr,
+				fakeTeamsGh(t, teams, &calls),
+			)
+
+			if got != tt.exit {
+				t.Errorf("run() = %v, want %v", got, tt.exit)
+			}
+
+			content, _ := os.ReadFile(filepath.Join(configDir, "gh-rr.yml"))
+
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stdout))
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stderr))
+			snaps.MatchSnapshot(t, string(content))
+		})
+	}
+}
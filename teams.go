@@ -0,0 +1,106 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// teamMaintainersPrefix marks a reviewer as being the maintainers of a team,
+// rather than a user or the team as a whole
+const teamMaintainersPrefix = "team-maintainers:"
+
+// fetchTeamMembers returns the logins of the members of the given team with
+// the given role, which can be either "all", "member", or "maintainer"
+func fetchTeamMembers(ghExec ghExecutor, org, slug, role string) ([]string, error) {
+	noun := "members"
+
+	if role == "maintainer" {
+		noun = "maintainers"
+	}
+
+	out, errMsg := ghExec("api", fmt.Sprintf("orgs/%s/teams/%s/members?role=%s", org, slug, role), "--paginate")
+
+	if errMsg != "" {
+		return nil, fmt.Errorf("could not get the %s of %s/%s: %s", noun, org, slug, strings.TrimSpace(errMsg))
+	}
+
+	members, err := decodePages[struct {
+		Login string `json:"login"`
+	}](out)
+
+	if err != nil {
+		return nil, fmt.Errorf("could not parse the %s of %s/%s: %w", noun, org, slug, err)
+	}
+
+	logins := make([]string, 0, len(members))
+
+	for _, member := range members {
+		logins = append(logins, member.Login)
+	}
+
+	return logins, nil
+}
+
+// fetchTeamMaintainers returns the logins of the maintainers of the given team,
+// which should be in the format of <org>/<slug>
+func fetchTeamMaintainers(ghExec ghExecutor, team string) ([]string, error) {
+	org, slug, found := strings.Cut(team, "/")
+
+	if !found || org == "" || slug == "" {
+		return nil, fmt.Errorf("%s%s should be in the format of %s<org>/<team>", teamMaintainersPrefix, team, teamMaintainersPrefix)
+	}
+
+	return fetchTeamMembers(ghExec, org, slug, "maintainer")
+}
+
+// expandDynamicReviewers replaces any team maintainer and roster reviewers with
+// the users that they currently resolve to, skipping anyone who is already a
+// reviewer
+func expandDynamicReviewers(ghExec ghExecutor, reviewers []reviewer) ([]reviewer, error) {
+	expanded := make([]reviewer, 0, len(reviewers))
+	seen := make(map[string]bool)
+
+	for _, r := range reviewers {
+		if !isDynamicReviewer(r) {
+			seen[strings.ToLower(r.Handle)] = true
+		}
+	}
+
+	for _, r := range reviewers {
+		if !isDynamicReviewer(r) {
+			expanded = append(expanded, r)
+
+			continue
+		}
+
+		var logins []string
+		var err error
+
+		if url, ok := strings.CutPrefix(r.Handle, rosterPrefix); ok {
+			logins, err = fetchRoster(url)
+		} else {
+			logins, err = fetchTeamMaintainers(ghExec, strings.TrimPrefix(r.Handle, teamMaintainersPrefix))
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		for _, login := range logins {
+			if seen[strings.ToLower(login)] {
+				continue
+			}
+
+			seen[strings.ToLower(login)] = true
+			expanded = append(expanded, reviewer{Handle: login, method: r.method})
+		}
+	}
+
+	return expanded, nil
+}
+
+// isDynamicReviewer checks if the reviewer resolves to different users over
+// time, rather than being a specific user or team
+func isDynamicReviewer(r reviewer) bool {
+	return strings.HasPrefix(r.Handle, teamMaintainersPrefix) || strings.HasPrefix(r.Handle, rosterPrefix)
+}
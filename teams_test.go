@@ -0,0 +1,249 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+// fakeTeamsGh acts as gh for an organization with the given team maintainers,
+// keyed by the api path used to list them
+func fakeTeamsGh(t *testing.T, maintainers map[string]string, calls *[][]string) ghExecutor {
+	t.Helper()
+
+	return func(args ...string) (string, string) {
+		t.Helper()
+
+		*calls = append(*calls, args)
+
+		switch {
+		case len(args) > 1 && args[0] == "api":
+			if out, ok := maintainers[args[1]]; ok {
+				return out, ""
+			}
+
+			return "", "gh: Not Found (HTTP 404)"
+		case len(args) > 1 && args[0] == "pr" && args[1] == "edit":
+			return "https://github.com/octocat/hello-world/pull/123", ""
+		}
+
+		t.Errorf("unexpected call to gh: %v", args)
+
+		return "", ""
+	}
+}
+
+func Test_run_TeamMaintainers(t *testing.T) {
+	t.Parallel()
+
+	maintainers := map[string]string{
+		"orgs/octo-org/teams/infra/members?role=maintainer":    `[{"login":"octodog"},{"login":"OctoCat"}]`,
+		"orgs/octo-org/teams/security/members?role=maintainer": `[{"login":"octopus"}]`,
+		"orgs/octo-org/teams/empty/members?role=maintainer":    `[]`,
+	}
+
+	tests := []struct {
+		name   string
+		args   []string
+		config string
+		exit   int
+	}{
+		{
+			name: "when a group includes the maintainers of a team",
+			args: []string{"123"},
+			config: `
+				repositories:
+					octocat/hello-world:
+						- octocat
+						- team-maintainers:octo-org/infra
+			`,
+			exit: 0,
+		},
+		{
+			name: "when a group includes the maintainers of multiple teams",
+			args: []string{"123"},
+			config: `
+				repositories:
+					octocat/hello-world:
+						- team-maintainers:octo-org/security
+						- team-maintainers:octo-org/infra
+						- team-maintainers:octo-org/empty
+			`,
+			exit: 0,
+		},
+		{
+			name: "when doing a dry-run",
+			args: []string{"--dry-run", "123"},
+			config: `
+				repositories:
+					octocat/hello-world:
+						- team-maintainers:octo-org/infra
+			`,
+			exit: 0,
+		},
+		{
+			name: "when the team does not exist",
+			args: []string{"123"},
+			config: `
+				repositories:
+					octocat/hello-world:
+						- octocat
+						- team-maintainers:octo-org/nope
+			`,
+			exit: 1,
+		},
+		{
+			name: "when the team is not in the right format",
+			args: []string{"123"},
+			config: `
+				repositories:
+					octocat/hello-world:
+						- team-maintainers:infra
+			`,
+			exit: 1,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			configDir := writeConfigFileInTempDir(t, dedent(t, tt.config))
+
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+
+			var calls [][]string
+
+			got := run(
+				append([]string{"--config-dir", configDir, "--repo", "octocat/hello-world"}, tt.args...),
+				stdout,
+				stderr,
+				fakeTeamsGh(t, maintainers, &calls),
+			)
+
+			if got != tt.exit {
+				t.Errorf("run() = %v, want %v", got, tt.exit)
+			}
+
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stdout))
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stderr))
+			snaps.MatchJSON(t, calls)
+		})
+	}
+}
+
+func Test_run_GroupsFromTeams(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		args   []string
+		config string
+		exit   int
+	}{
+		{
+			name: "when requesting a group from a team",
+			args: []string{"--from", "security", "123"},
+			config: `
+				repositories:
+					octocat/hello-world:
+						default: [octocat]
+						groups_from_teams: [platform, security]
+			`,
+			exit: 0,
+		},
+		{
+			name: "when the team is in another org",
+			args: []string{"--from", "platform", "123"},
+			config: `
+				repositories:
+					octocat/hello-world:
+						groups_from_teams: [octo-org/platform]
+			`,
+			exit: 0,
+		},
+		{
+			name: "when a group has been configured with the same name as a team",
+			args: []string{"--from", "security", "123"},
+			config: `
+				repositories:
+					octocat/hello-world:
+						security: [octopus]
+						groups_from_teams: [security]
+			`,
+			exit: 0,
+		},
+		{
+			name: "when using a global group from a team",
+			args: []string{"--global", "--from", "platform", "123"},
+			config: `
+				repositories:
+					'*':
+						groups_from_teams: [octo-org/platform]
+			`,
+			exit: 0,
+		},
+		{
+			name: "when a global team does not have an org",
+			args: []string{"--global", "--from", "platform", "123"},
+			config: `
+				repositories:
+					'*':
+						groups_from_teams: [platform]
+			`,
+			exit: 1,
+		},
+		{
+			name: "when listing groups",
+			args: []string{"groups"},
+			config: `
+				repositories:
+					octocat/hello-world:
+						default: [octocat]
+						groups_from_teams: [platform, security]
+			`,
+			exit: 0,
+		},
+		{
+			name: "when offboarding someone with the same name as a team",
+			args: []string{"offboard", "security", "--dry-run"},
+			config: `
+				repositories:
+					octocat/hello-world:
+						default: [octocat, security]
+						groups_from_teams: [security]
+			`,
+			exit: 0,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			configDir := writeConfigFileInTempDir(t, dedent(t, tt.config))
+
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+
+			var calls [][]string
+
+			got := run(
+				append([]string{"--config-dir", configDir, "--repo", "octocat/hello-world"}, tt.args...),
+				stdout,
+				stderr,
+				fakeTeamsGh(t, map[string]string{}, &calls),
+			)
+
+			if got != tt.exit {
+				t.Errorf("run() = %v, want %v", got, tt.exit)
+			}
+
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stdout))
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stderr))
+			snaps.MatchJSON(t, calls)
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"fmt"
+	"io"
+)
+
+// the label that is applied to pull requests when requesting urgent reviews,
+// unless another one has been configured
+const defaultUrgentLabel = "urgent"
+
+// buildUrgentArgs adds the urgent label to the args for editing a pull request,
+// if the request is urgent
+func buildUrgentArgs(args []string, label string) []string {
+	if label == "" {
+		return args
+	}
+
+	return append(args, "--add-label", label)
+}
+
+// printUrgent outputs that the pull request has been labelled as urgent, if the
+// request is urgent
+func printUrgent(stdout io.Writer, label string, isDryRun bool) {
+	if label == "" {
+		return
+	}
+
+	if isDryRun {
+		fmt.Fprintf(stdout, "would have marked it as urgent with the %s label\n", label)
+	} else {
+		fmt.Fprintf(stdout, "marked it as urgent with the %s label\n", label)
+	}
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+func Test_run_Urgent(t *testing.T) {
+	t.Parallel()
+
+	prs := `[{"number":1,"url":"https://github.com/octocat/hello-world/pull/1","reviewRequests":[]}]`
+
+	tests := []struct {
+		name   string
+		args   []string
+		config string
+		exit   int
+	}{
+		{
+			name: "when requesting urgent reviews",
+			args: []string{"--urgent", "123"},
+			exit: 0,
+		},
+		{
+			name: "when a label has been configured",
+			args: []string{"--urgent", "123"},
+			config: `
+				settings:
+					urgent_label: 'priority: high'
+			`,
+			exit: 0,
+		},
+		{
+			name: "when doing a dry-run",
+			args: []string{"--urgent", "--dry-run", "123"},
+			exit: 0,
+		},
+		{
+			name: "when searching",
+			args: []string{"--urgent", "--search", "label:hotfix"},
+			exit: 0,
+		},
+		{
+			name: "when queueing",
+			args: []string{"queue", "--urgent", "123"},
+			exit: 1,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			server, recorder := newNotificationServer(t, http.StatusOK)
+
+			configDir := writeConfigFileInTempDir(t, dedent(t, tt.config+`
+				notifications:
+					teams:
+						- webhook: `+server.URL+`
+				repositories:
+					octocat/hello-world:
+						- octocat
+						- octopus
+			`))
+
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+
+			var calls [][]string
+
+			got := run(
+				append([]string{"--config-dir", configDir, "--repo", "octocat/hello-world"}, tt.args...),
+				stdout,
+				stderr,
+				func(args ...string) (string, string) {
+					calls = append(calls, args)
+
+					switch {
+					case len(args) > 1 && args[0] == "pr" && args[1] == "list":
+						return prs, ""
+					case len(args) > 2 && args[0] == "pr" && args[1] == "edit":
+						return fmt.Sprintf("https://github.com/octocat/hello-world/pull/%s", args[2]), ""
+					}
+
+					t.Errorf("unexpected call to gh: %v", args)
+
+					return "", ""
+				},
+			)
+
+			if got != tt.exit {
+				t.Errorf("run() = %v, want %v", got, tt.exit)
+			}
+
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stdout))
+			snaps.MatchSnapshot(t, strings.ReplaceAll(normalizeStdStream(t, stderr), server.URL, "<webhook>"))
+			snaps.MatchJSON(t, calls)
+			snaps.MatchSnapshot(t, fmt.Sprintf("%q", recorder.bodies))
+		})
+	}
+}
@@ -0,0 +1,376 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// configDocument is the shape of a config file as a whole, which can have
+// directives like includes alongside the config itself
+type configDocument struct {
+	config         `yaml:",inline"`
+	configIncludes `yaml:",inline"`
+}
+
+// resolveAliasNode returns the node that an alias points to, or the node
+// itself if it is not an alias
+func resolveAliasNode(node *yaml.Node) *yaml.Node {
+	for node.Kind == yaml.AliasNode && node.Alias != nil {
+		node = node.Alias
+	}
+
+	return node
+}
+
+// yamlFields returns the types of the fields of the struct by the keys they are
+// decoded from, including those of any inlined structs
+func yamlFields(t reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type, t.NumField())
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, opts, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+
+		if slices.Contains(strings.Split(opts, ","), "inline") {
+			for key, ft := range yamlFields(field.Type) {
+				fields[key] = ft
+			}
+
+			continue
+		}
+
+		if !field.IsExported() || name == "-" {
+			continue
+		}
+
+		if name == "" {
+			name = strings.ToLower(field.Name)
+		}
+
+		fields[name] = field.Type
+	}
+
+	return fields
+}
+
+// findUnknownKeys returns a problem for every key within the node that does not
+// correspond to a field of the given type, accounting for the shorthands that
+// the config supports
+func findUnknownKeys(node *yaml.Node, t reflect.Type) []yamlProblem {
+	node = resolveAliasNode(node)
+
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch t {
+	case reflect.TypeOf(repositories{}):
+		return findUnknownKeys(node, reflect.TypeOf(map[string]repositoryGroups{}))
+	case reflect.TypeOf(repositoryGroups{}):
+		if node.Kind == yaml.SequenceNode {
+			return findUnknownKeys(node, reflect.TypeOf([]reviewer{}))
+		}
+
+		groups := *node
+		groups.Content = nil
+
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if !isRepositorySettingKey(node.Content[i].Value) {
+				groups.Content = append(groups.Content, node.Content[i], node.Content[i+1])
+			}
+		}
+
+		return findUnknownKeys(&groups, reflect.TypeOf(map[string]group{}))
+	case reflect.TypeOf(group{}):
+		if node.Kind == yaml.SequenceNode {
+			return findUnknownKeys(node, reflect.TypeOf([]reviewer{}))
+		}
+	}
+
+	var problems []yamlProblem
+
+	switch {
+	case t.Kind() == reflect.Struct && node.Kind == yaml.MappingNode:
+		fields := yamlFields(t)
+
+		// types that only have unexported fields are decoded by hand
+		if len(fields) == 0 {
+			return nil
+		}
+
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+
+			// merge keys are expected to point to something of the same type
+			if key.Value == "<<" {
+				problems = append(problems, findUnknownKeys(value, t)...)
+
+				continue
+			}
+
+			ft, ok := fields[key.Value]
+
+			if !ok {
+				problems = append(problems, yamlProblem{
+					line:    key.Line,
+					column:  key.Column,
+					message: fmt.Sprintf("unknown key `%s`", key.Value),
+				})
+
+				continue
+			}
+
+			problems = append(problems, findUnknownKeys(value, ft)...)
+		}
+	case t.Kind() == reflect.Map && node.Kind == yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Value == "<<" {
+				problems = append(problems, findUnknownKeys(node.Content[i+1], t)...)
+
+				continue
+			}
+
+			problems = append(problems, findUnknownKeys(node.Content[i+1], t.Elem())...)
+		}
+	case t.Kind() == reflect.Slice && node.Kind == yaml.SequenceNode:
+		for _, item := range node.Content {
+			problems = append(problems, findUnknownKeys(item, t.Elem())...)
+		}
+	}
+
+	return problems
+}
+
+// mappingPairs returns the key and value nodes of the mapping, or nothing if
+// the node is not a mapping
+func mappingPairs(node *yaml.Node) [][2]*yaml.Node {
+	node = resolveAliasNode(node)
+
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	pairs := make([][2]*yaml.Node, 0, len(node.Content)/2)
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		pairs = append(pairs, [2]*yaml.Node{node.Content[i], resolveAliasNode(node.Content[i+1])})
+	}
+
+	return pairs
+}
+
+// isEmptyGroupNode checks if the group does not have any way of finding people
+// to request reviews from, either directly or through a team or other groups
+func isEmptyGroupNode(node *yaml.Node) bool {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		return node.Tag == "!!null"
+	case yaml.SequenceNode:
+		return len(node.Content) == 0
+	case yaml.MappingNode:
+		for _, pair := range mappingPairs(node) {
+			switch pair[0].Value {
+			case "reviewers":
+				if !isEmptyGroupNode(pair[1]) {
+					return false
+				}
+			case "team", "extends", "<<":
+				return false
+			}
+		}
+
+		return true
+	}
+
+	return false
+}
+
+// findEmptyGroups returns a problem for every group in the repositories that
+// does not have any reviewers
+func findEmptyGroups(repos *yaml.Node) []yamlProblem {
+	var problems []yamlProblem
+
+	for _, repo := range mappingPairs(repos) {
+		if repo[1].Kind == yaml.SequenceNode || repo[1].Tag == "!!null" {
+			if isEmptyGroupNode(repo[1]) {
+				problems = append(problems, yamlProblem{
+					line:    repo[0].Line,
+					column:  repo[0].Column,
+					message: fmt.Sprintf("the default group of %s does not have any reviewers", repo[0].Value),
+				})
+			}
+
+			continue
+		}
+
+		for _, g := range mappingPairs(repo[1]) {
+			if isRepositorySettingKey(g[0].Value) || g[0].Value == "<<" {
+				continue
+			}
+
+			if isEmptyGroupNode(g[1]) {
+				problems = append(problems, yamlProblem{
+					line:    g[0].Line,
+					column:  g[0].Column,
+					message: fmt.Sprintf("the %s group of %s does not have any reviewers", g[0].Value, repo[0].Value),
+				})
+			}
+		}
+	}
+
+	return problems
+}
+
+// findEmptyGroupsInConfig returns a problem for every empty group within the
+// config, including those of its hosts and profiles
+func findEmptyGroupsInConfig(node *yaml.Node) []yamlProblem {
+	var problems []yamlProblem
+
+	for _, pair := range mappingPairs(node) {
+		switch pair[0].Value {
+		case "repositories", "owners":
+			problems = append(problems, findEmptyGroups(pair[1])...)
+		case "hosts", "profiles":
+			for _, nested := range mappingPairs(pair[1]) {
+				problems = append(problems, findEmptyGroupsInConfig(nested[1])...)
+			}
+		}
+	}
+
+	return problems
+}
+
+// findUnknownConfigKeys returns a problem for every key within the config
+// that is not part of the config
+func findUnknownConfigKeys(node *yaml.Node) []yamlProblem {
+	return findUnknownKeys(node, reflect.TypeOf(configDocument{}))
+}
+
+// checkConfigSource parses the config, returning an error describing every
+// problem that is found by the given checks
+func checkConfigSource(file string, source []byte, checks ...func(*yaml.Node) []yamlProblem) error {
+	var doc yaml.Node
+
+	if err := yaml.Unmarshal(source, &doc); err != nil {
+		return describeYAMLError(file, source, err)
+	}
+
+	// empty documents are valid, just not very useful
+	if len(doc.Content) == 0 {
+		return nil
+	}
+
+	var problems []yamlProblem
+
+	for _, check := range checks {
+		problems = append(problems, check(doc.Content[0])...)
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+
+	slices.SortStableFunc(problems, func(a, b yamlProblem) int {
+		if a.line != b.line {
+			return a.line - b.line
+		}
+
+		return a.column - b.column
+	})
+
+	lines := strings.Split(strings.ReplaceAll(string(source), "\r\n", "\n"), "\n")
+
+	return &yamlSourceError{file: file, lines: lines, problems: problems}
+}
+
+// describeProblemCount returns a description of how many problems were found
+func describeProblemCount(count int) string {
+	if count == 1 {
+		return "1 problem"
+	}
+
+	return fmt.Sprintf("%d problems", count)
+}
+
+// validateConfigSource checks the config for problems that yaml does not catch
+// by itself, like unknown keys and empty groups
+func validateConfigSource(file string, source []byte) error {
+	return checkConfigSource(file, source, findUnknownConfigKeys, findEmptyGroupsInConfig)
+}
+
+// checkConfigStrictly errors if the config at the given path, or any of the
+// configs that it includes, has keys that are not part of the config
+func checkConfigStrictly(file string) error {
+	sources, err := collectConfigSources(file, nil)
+
+	if err != nil {
+		return err
+	}
+
+	for _, source := range sources {
+		content, err := readConfigSource(source.file)
+
+		if err == nil {
+			err = checkConfigSource(source.file, content, findUnknownConfigKeys)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validateConfig strictly checks the config at the given path along with any
+// configs it includes, outputting every problem that is found
+func validateConfig(stdout, stderr io.Writer, file string) int {
+	sources, err := collectConfigSources(file, nil)
+
+	if err != nil {
+		printParseConfigError(stderr, file, err)
+
+		return 1
+	}
+
+	exit := 0
+
+	for _, source := range sources {
+		content, err := readConfigSource(source.file)
+
+		if err == nil {
+			err = validateConfigSource(source.file, content)
+		}
+
+		if err == nil {
+			continue
+		}
+
+		// separate the problems of each config
+		if exit != 0 {
+			fmt.Fprintln(stdout)
+		}
+
+		exit = 1
+
+		var srcErr *yamlSourceError
+
+		if errors.As(err, &srcErr) {
+			fmt.Fprintf(stdout, "found %s in %s:\n%s\n", describeProblemCount(len(srcErr.problems)), source.file, srcErr.describeProblems())
+		} else {
+			fmt.Fprintln(stderr, err)
+		}
+	}
+
+	if exit == 0 {
+		fmt.Fprintf(stdout, "%s is valid\n", file)
+	}
+
+	return exit
+}
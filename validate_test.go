@@ -0,0 +1,274 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+func Test_run_ConfigValidate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		args   []string
+		config string
+		exit   int
+	}{
+		{
+			name: "when the config is valid",
+			args: []string{"config", "validate"},
+			config: `
+				include: [other.yml]
+				settings:
+					order: shuffle
+				repositories:
+					octocat/hello-world:
+						reviewers_per_pr: 1
+						groups_from_teams: [engineering]
+						default: [octocat, {handle: octopus, name: Octo Pus}]
+						security:
+							team: octocat/security
+						infra:
+							extends: [security]
+						<<: &shared
+							docs:
+								reviewers: [octodog]
+				profiles:
+					work:
+						match:
+							owners: [octocat]
+						repositories:
+							octocat/spoon-knife: [octocat]
+			`,
+			exit: 0,
+		},
+		{
+			name: "when there are unknown keys",
+			args: []string{"config", "validate"},
+			config: `
+				setings:
+					order: shuffle
+				settings:
+					ordr: shuffle
+				repositories:
+					octocat/hello-world:
+						default:
+							reviewer: [octocat]
+						security:
+							reviewers:
+								- handle: octopus
+									nmae: Octo Pus
+				hosts:
+					github.example.com:
+						repository:
+							octocat/hello-world: [octocat]
+			`,
+			exit: 1,
+		},
+		{
+			name: "when there are empty groups",
+			args: []string{"config", "validate"},
+			config: `
+				repositories:
+					octocat/hello-world: []
+					octocat/spoon-knife:
+						default:
+						security:
+							reviewers: []
+						docs:
+							description: Documentation
+				profiles:
+					work:
+						owners:
+							octocat:
+								default: []
+			`,
+			exit: 1,
+		},
+		{
+			name: "when there are the wrong types",
+			args: []string{"config", "validate"},
+			config: `
+				settings:
+					group_from_team: sometimes
+				repositories:
+					octocat/hello-world:
+						default:
+							reviewers_per_pr: many
+			`,
+			exit: 1,
+		},
+		{
+			name:   "when validating a specific file that does not exist",
+			args:   []string{"config", "validate", "testdata/does-not-exist.yml"},
+			config: "repositories: {octocat/hello-world: [octocat]}",
+			exit:   1,
+		},
+		{
+			name:   "when there is no config",
+			args:   []string{"config", "validate"},
+			config: "",
+			exit:   1,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			configDir := writeConfigFileInTempDir(t, dedent(t, tt.config))
+
+			// used to check that included configs are validated too
+			err := os.WriteFile(filepath.Join(configDir, "other.yml"), []byte("repositories: {}\n"), 0600)
+			if err != nil {
+				t.Fatalf("could not create included config: %v", err)
+			}
+
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+
+			got := run(
+				append([]string{"--config-dir", configDir}, tt.args...),
+				stdout,
+				stderr,
+				expectNoCallToGh(t),
+			)
+
+			if got != tt.exit {
+				t.Errorf("run() = %v, want %v", got, tt.exit)
+			}
+
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stdout))
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stderr))
+		})
+	}
+}
+
+func Test_run_ConfigValidate_WithIncludes(t *testing.T) {
+	t.Parallel()
+
+	configDir := writeConfigFileInTempDir(t, dedent(t, `
+		include: [other.yml]
+		setings: {}
+		repositories:
+			octocat/hello-world: [octocat]
+	`))
+
+	err := os.WriteFile(filepath.Join(configDir, "other.yml"), []byte("repositories:\n  octocat/spoon-knife:\n    default: []\n"), 0600)
+	if err != nil {
+		t.Fatalf("could not create included config: %v", err)
+	}
+
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+
+	got := run([]string{"--config-dir", configDir, "config", "validate"}, stdout, stderr, expectNoCallToGh(t))
+
+	if got != 1 {
+		t.Errorf("run() = %v, want %v", got, 1)
+	}
+
+	snaps.MatchSnapshot(t, normalizeStdStream(t, stdout))
+	snaps.MatchSnapshot(t, normalizeStdStream(t, stderr))
+}
+
+func Test_run_Strict(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		args   []string
+		config string
+		exit   int
+	}{
+		{
+			name: "when the config is valid",
+			args: []string{"--strict"},
+			config: `
+				repositories:
+					octocat/hello-world:
+						default: [octocat, {handle: octopus, name: Octo Pus}]
+			`,
+			exit: 0,
+		},
+		{
+			name: "when there are unknown keys",
+			args: []string{"--strict"},
+			config: `
+				repositories:
+					octocat/hello-world:
+						default:
+							reviewer: [octocat]
+			`,
+			exit: 1,
+		},
+		{
+			name: "when strict is enabled in the config",
+			args: []string{},
+			config: `
+				settings:
+					strict: true
+				repositorys:
+					octocat/spoon-knife: [octocat]
+				repositories:
+					octocat/hello-world: [octocat]
+			`,
+			exit: 1,
+		},
+		{
+			name: "when strict is not enabled",
+			args: []string{},
+			config: `
+				repositorys:
+					octocat/spoon-knife: [octocat]
+				repositories:
+					octocat/hello-world: [octocat]
+			`,
+			exit: 0,
+		},
+		{
+			name: "when an included config has unknown keys",
+			args: []string{"--strict"},
+			config: `
+				include: [other.yml]
+				repositories:
+					octocat/hello-world: [octocat]
+			`,
+			exit: 1,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			configDir := writeConfigFileInTempDir(t, dedent(t, tt.config))
+
+			err := os.WriteFile(filepath.Join(configDir, "other.yml"), []byte("repositories: {}\nsetings: {}\n"), 0600)
+			if err != nil {
+				t.Fatalf("could not create included config: %v", err)
+			}
+
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+
+			got := run(
+				append([]string{"--config-dir", configDir, "--repo", "octocat/hello-world", "--dry-run", "123"}, tt.args...),
+				stdout,
+				stderr,
+				expectCallToGh(t, "octocat/hello-world", "123"),
+			)
+
+			if got != tt.exit {
+				t.Errorf("run() = %v, want %v", got, tt.exit)
+			}
+
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stdout))
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stderr))
+		})
+	}
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+// membership is a group that someone is a reviewer in, either directly or as
+// part of a team
+type membership struct {
+	repo  string
+	group string
+	via   string
+}
+
+// teamMemberCache remembers the members of the teams that have been fetched,
+// as the same team is often used in multiple groups
+type teamMemberCache struct {
+	ghExec  ghExecutor
+	members map[string][]string
+}
+
+// membersOf returns the logins of the people that the given reviewer handle
+// refers to, if it is for a team or roster, or otherwise false
+func (c *teamMemberCache) membersOf(handle string) ([]string, bool, error) {
+	if members, ok := c.members[handle]; ok {
+		return members, true, nil
+	}
+
+	// contributors depend on the files changed by a pull request, so they are
+	// not a team even though their globs can contain slashes
+	if strings.HasPrefix(handle, contributorsPrefix) {
+		return nil, false, nil
+	}
+
+	var members []string
+	var err error
+
+	if url, ok := strings.CutPrefix(handle, rosterPrefix); ok {
+		members, err = fetchRoster(url)
+	} else if team, ok := strings.CutPrefix(handle, teamMaintainersPrefix); ok {
+		members, err = fetchTeamMaintainers(c.ghExec, team)
+	} else if org, slug, ok := strings.Cut(handle, "/"); ok {
+		members, err = fetchTeamMembers(c.ghExec, org, slug, "all")
+	} else {
+		return nil, false, nil
+	}
+
+	if err != nil {
+		return nil, true, err
+	}
+
+	c.members[handle] = members
+
+	return members, true, nil
+}
+
+// findMembership checks if the login is a reviewer in the given group, and
+// returns the team that they are a member of the group via (if any)
+func findMembership(cache *teamMemberCache, g group, login string) (string, bool, error) {
+	for _, r := range g.Reviewers {
+		if strings.EqualFold(r.Handle, login) {
+			return "", true, nil
+		}
+	}
+
+	for _, r := range g.Reviewers {
+		members, isTeam, err := cache.membersOf(r.Handle)
+
+		if err != nil {
+			return "", false, err
+		}
+
+		if isTeam && slices.ContainsFunc(members, func(member string) bool {
+			return strings.EqualFold(member, login)
+		}) {
+			return r.Handle, true, nil
+		}
+	}
+
+	return "", false, nil
+}
+
+// findMemberships returns every group in the config that the given login is a
+// reviewer in, sorted by repository (with the global groups first) and group
+func findMemberships(ghExec ghExecutor, conf config, login string) ([]membership, error) {
+	cache := &teamMemberCache{ghExec: ghExec, members: make(map[string][]string)}
+
+	var memberships []membership
+
+	repos := make([]string, 0, len(conf.Repositories))
+
+	for repo := range conf.Repositories {
+		repos = append(repos, repo)
+	}
+
+	// "*" sorts before letters, so the global groups will naturally be first
+	slices.Sort(repos)
+
+	for _, repo := range repos {
+		names := make([]string, 0, len(conf.Repositories[repo]))
+
+		for name := range conf.Repositories[repo] {
+			names = append(names, name)
+		}
+
+		slices.Sort(names)
+
+		for _, name := range names {
+			via, ok, err := findMembership(cache, conf.Repositories[repo][name], login)
+
+			if err != nil {
+				return nil, err
+			}
+
+			if ok {
+				memberships = append(memberships, membership{repo: repo, group: name, via: via})
+			}
+		}
+	}
+
+	return memberships, nil
+}
+
+// printMemberships outputs every group that the given login is a reviewer in
+func printMemberships(stdout, stderr io.Writer, ghExec ghExecutor, conf config, args []string) int {
+	if len(args) != 1 {
+		fmt.Fprintln(stderr, "please provide the login of the person to look for")
+
+		return 1
+	}
+
+	login := strings.TrimPrefix(args[0], "@")
+
+	memberships, err := findMemberships(ghExec, conf, login)
+
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+
+		return 1
+	}
+
+	if len(memberships) == 0 {
+		fmt.Fprintf(stdout, "%s is not in any groups\n", login)
+
+		return 0
+	}
+
+	fmt.Fprintf(stdout, "%s is in the following groups:\n", login)
+
+	for i, m := range memberships {
+		if i == 0 || m.repo != memberships[i-1].repo {
+			if m.repo == "*" {
+				fmt.Fprintln(stdout, "  global groups:")
+			} else {
+				fmt.Fprintf(stdout, "  %s:\n", m.repo)
+			}
+		}
+
+		if m.via == "" {
+			fmt.Fprintf(stdout, "    - %s\n", m.group)
+		} else {
+			fmt.Fprintf(stdout, "    - %s (via %s)\n", m.group, m.via)
+		}
+	}
+
+	return 0
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+func Test_run_Who(t *testing.T) {
+	t.Parallel()
+
+	teams := map[string]string{
+		"orgs/octo-org/teams/payments/members?role=all":        `[{"login":"priyak"},{"login":"octocat"}]`,
+		"orgs/octo-org/teams/payments/members?role=maintainer": `[{"login":"PriyaK"}]`,
+		"orgs/octo-org/teams/infra/members?role=all":           `[{"login":"octodog"}]`,
+	}
+
+	config := `
+		repositories:
+			'*':
+				security: [octopus, priyak]
+			octocat/payments-api:
+				default: [octo-org/payments]
+				signoff: [team-maintainers:octo-org/payments]
+				infra: [octo-org/infra]
+			octocat/payments-web:
+				- octocat
+				- octo-org/payments
+			octocat/hello-world:
+				- octodog
+	`
+
+	tests := []struct {
+		name   string
+		args   []string
+		config string
+		exit   int
+	}{
+		{
+			name:   "when the login is in groups directly and via teams",
+			args:   []string{"who", "priyak"},
+			config: config,
+			exit:   0,
+		},
+		{
+			name:   "when the login is given as a mention",
+			args:   []string{"who", "@OctoCat"},
+			config: config,
+			exit:   0,
+		},
+		{
+			name:   "when the login is not in any groups",
+			args:   []string{"who", "octokitten"},
+			config: config,
+			exit:   0,
+		},
+		{
+			name: "when a team cannot be fetched",
+			args: []string{"who", "octocat"},
+			config: `
+				repositories:
+					octocat/hello-world:
+						- octo-org/nope
+			`,
+			exit: 1,
+		},
+		{
+			name: "when a group has recent contributors",
+			args: []string{"who", "octodog"},
+			config: `
+				repositories:
+					octocat/hello-world:
+						docs: [octodog, contributors:docs/**]
+			`,
+			exit: 0,
+		},
+		{
+			name: "when the current repository matches a pattern",
+			args: []string{"who", "octodog"},
+			config: `
+				repositories:
+					octocat/*: [octodog]
+					octocat/spoon-knife: [octocat]
+			`,
+			exit: 0,
+		},
+		{
+			name:   "when no login is given",
+			args:   []string{"who"},
+			config: config,
+			exit:   1,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			configDir := writeConfigFileInTempDir(t, dedent(t, tt.config))
+
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+
+			var calls [][]string
+
+			got := run(
+				append([]string{"--config-dir", configDir, "--repo", "octocat/hello-world"}, tt.args...),
+				stdout,
+				stderr,
+				fakeTeamsGh(t, teams, &calls),
+			)
+
+			if got != tt.exit {
+				t.Errorf("run() = %v, want %v", got, tt.exit)
+			}
+
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stdout))
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stderr))
+			snaps.MatchJSON(t, calls)
+		})
+	}
+}
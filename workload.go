@@ -0,0 +1,63 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// fetchOpenReviewCount returns how many open pull requests across GitHub have
+// a review requested from the given user or team
+func fetchOpenReviewCount(ghExec ghExecutor, handle string) (int, error) {
+	qualifier := "review-requested"
+
+	if strings.Contains(handle, "/") {
+		qualifier = "team-review-requested"
+	}
+
+	out, errMsg := ghExec(
+		"api", "search/issues", "-X", "GET",
+		"-f", fmt.Sprintf("q=is:pr is:open %s:%s", qualifier, handle),
+		"--jq", ".total_count",
+	)
+
+	if errMsg != "" {
+		return 0, fmt.Errorf("could not get the open review requests of %s: %s", handle, strings.TrimSpace(errMsg))
+	}
+
+	count, err := strconv.Atoi(strings.TrimSpace(out))
+
+	if err != nil {
+		return 0, fmt.Errorf("could not parse the open review requests of %s: %w", handle, err)
+	}
+
+	return count, nil
+}
+
+// fetchWorkloads returns the number of open review requests each of the
+// reviewers currently has, keyed by their lowercased handle
+func fetchWorkloads(ghExec ghExecutor, reviewers []reviewer) (map[string]int, error) {
+	workloads := make(map[string]int, len(reviewers))
+
+	for _, r := range reviewers {
+		count, err := fetchOpenReviewCount(ghExec, r.Handle)
+
+		if err != nil {
+			return nil, err
+		}
+
+		workloads[strings.ToLower(r.Handle)] = count
+	}
+
+	return workloads, nil
+}
+
+// describeWorkload returns a description of how many open review requests
+// someone has, for annotating the reviewers that are output
+func describeWorkload(count int) string {
+	if count == 1 {
+		return "1 open review"
+	}
+
+	return fmt.Sprintf("%d open reviews", count)
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+// fakeWorkloadGh acts as gh for a repository where searching for open review
+// requests returns the given counts, keyed by the search qualifier
+func fakeWorkloadGh(t *testing.T, counts map[string]string, calls *[][]string) ghExecutor {
+	t.Helper()
+
+	return func(args ...string) (string, string) {
+		t.Helper()
+
+		*calls = append(*calls, args)
+
+		switch {
+		case len(args) > 4 && args[0] == "api" && args[1] == "search/issues":
+			qualifier := strings.TrimPrefix(args[5], "q=is:pr is:open ")
+
+			if count, ok := counts[qualifier]; ok {
+				return count, ""
+			}
+
+			return "", "HTTP 422: Validation Failed (https://api.github.com/search/issues)"
+		case len(args) > 2 && args[0] == "pr" && args[1] == "edit":
+			return fmt.Sprintf("https://github.com/octocat/hello-world/pull/%s", args[2]), ""
+		}
+
+		t.Errorf("unexpected call to gh: %v", args)
+
+		return "", ""
+	}
+}
+
+func Test_run_Workload(t *testing.T) {
+	t.Parallel()
+
+	counts := map[string]string{
+		"review-requested:octocat":                "3",
+		"review-requested:octopus":                "1",
+		"team-review-requested:octo-org/octoteam": "0",
+	}
+
+	tests := []struct {
+		name   string
+		args   []string
+		counts map[string]string
+		exit   int
+	}{
+		{
+			name:   "when requesting reviews",
+			args:   []string{"--workload", "123"},
+			counts: counts,
+			exit:   0,
+		},
+		{
+			name:   "when doing a dry-run",
+			args:   []string{"--workload", "--dry-run", "123"},
+			counts: counts,
+			exit:   0,
+		},
+		{
+			name:   "when the open review requests cannot be searched",
+			args:   []string{"--workload", "123"},
+			counts: map[string]string{},
+			exit:   1,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			configDir := writeConfigFileInTempDir(t, dedent(t, `
+				repositories:
+					octocat/hello-world:
+						- octocat
+						- octopus
+						- octo-org/octoteam
+			`))
+
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+
+			var calls [][]string
+
+			got := run(
+				append([]string{"--config-dir", configDir, "--repo", "octocat/hello-world"}, tt.args...),
+				stdout,
+				stderr,
+				fakeWorkloadGh(t, tt.counts, &calls),
+			)
+
+			if got != tt.exit {
+				t.Errorf("run() = %v, want %v", got, tt.exit)
+			}
+
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stdout))
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stderr))
+			snaps.MatchJSON(t, calls)
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// xdgConfigPath returns where the config file lives within the XDG config
+// directory, which is $XDG_CONFIG_HOME or otherwise ~/.config
+func xdgConfigPath() string {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+
+	if dir == "" {
+		dir = filepath.Join(mustGetUserHomeDir(), ".config")
+	}
+
+	return filepath.Join(dir, "gh-rr", "config.yml")
+}
+
+// personalConfigPaths returns the paths that the personal config is looked for
+// at in order of precedence, along with the path that it should be created at;
+// this is only the given directory if it has been explicitly given, or
+// otherwise the path in GH_RR_CONFIG if that is set
+func personalConfigPaths(configDir string, explicit bool) ([]string, string) {
+	home := filepath.Join(configDir, "gh-rr.yml")
+
+	if explicit {
+		return withJSONAlternatives(home), home
+	}
+
+	if p := os.Getenv("GH_RR_CONFIG"); p != "" {
+		return []string{p}, p
+	}
+
+	return append(withJSONAlternatives(xdgConfigPath()), withJSONAlternatives(home)...), home
+}
+
+// withJSONAlternatives returns the yaml config path along with the same path
+// but for a json config, which is looked for after the yaml one
+func withJSONAlternatives(p string) []string {
+	return []string{p, strings.TrimSuffix(p, filepath.Ext(p)) + ".json"}
+}
+
+// findPersonalConfig returns the first of the paths that exists, or otherwise
+// the given default path so that it can be suggested for creating
+func findPersonalConfig(paths []string, defaultPath string) string {
+	for _, p := range paths {
+		if _, err := os.Stat(p); err == nil {
+			return p
+		}
+	}
+
+	return defaultPath
+}
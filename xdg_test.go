@@ -0,0 +1,115 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+// writeXDGConfigFile writes the given config to where gh-rr expects it to be
+// within the given XDG config directory
+func writeXDGConfigFile(t *testing.T, dir, content string) {
+	t.Helper()
+
+	if err := os.MkdirAll(filepath.Join(dir, "gh-rr"), 0700); err != nil {
+		t.Fatalf("could not create config directory: %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "gh-rr", "config.yml"), []byte(content), 0600); err != nil {
+		t.Fatalf("could not create config file: %v", err)
+	}
+}
+
+// these tests cannot be run in parallel as they change the environment
+func Test_run_WithXDGConfig(t *testing.T) {
+	xdg := dedent(t, `
+		repositories:
+			octocat/hello-world:
+				- octopus
+	`)
+
+	tests := []struct {
+		name          string
+		args          []string
+		xdgConfigHome bool
+		xdg           string
+		home          string
+		exit          int
+	}{
+		{
+			name:          "when there is a config in XDG_CONFIG_HOME",
+			args:          []string{"--repo", "octocat/hello-world", "123"},
+			xdgConfigHome: true,
+			xdg:           xdg,
+			home:          "repositories: {octocat/hello-world: [octocat]}",
+			exit:          0,
+		},
+		{
+			name: "when there is a config in ~/.config",
+			args: []string{"--repo", "octocat/hello-world", "123"},
+			xdg:  xdg,
+			home: "repositories: {octocat/hello-world: [octocat]}",
+			exit: 0,
+		},
+		{
+			name:          "when there is only a config in the home directory",
+			args:          []string{"--repo", "octocat/hello-world", "123"},
+			xdgConfigHome: true,
+			home:          "repositories: {octocat/hello-world: [octocat]}",
+			exit:          0,
+		},
+		{
+			name:          "when there is no config",
+			args:          []string{"--repo", "octocat/hello-world", "123"},
+			xdgConfigHome: true,
+			exit:          1,
+		},
+		{
+			name:          "when listing where configs are looked for",
+			args:          []string{"open-config"},
+			xdgConfigHome: true,
+			xdg:           xdg,
+			exit:          0,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			home := writeConfigFileInTempDir(t, tt.home)
+			xdgDir := filepath.Join(home, ".config")
+
+			t.Setenv("HOME", home)
+			t.Setenv("XDG_CONFIG_HOME", "")
+
+			if tt.xdgConfigHome {
+				xdgDir = writeConfigFileInTempDir(t, "")
+
+				t.Setenv("XDG_CONFIG_HOME", xdgDir)
+			}
+
+			if tt.xdg != "" {
+				writeXDGConfigFile(t, xdgDir, tt.xdg)
+			}
+
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+
+			got := run(
+				tt.args,
+				stdout,
+				stderr,
+				expectCallToGh(t, "octocat/hello-world", "123"),
+			)
+
+			if got != tt.exit {
+				t.Errorf("run() = %v, want %v", got, tt.exit)
+			}
+
+			snaps.MatchSnapshot(t, normalizeRepositoryDirectory(t, normalizeStdStream(t, stdout)))
+			snaps.MatchSnapshot(t, normalizeStdStream(t, stderr))
+		})
+	}
+}
@@ -0,0 +1,282 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// the number of lines to show either side of the offending line in a snippet
+const yamlSnippetContextLines = 2
+
+var yamlErrorLineRe = regexp.MustCompile(`^(?:yaml: )?line (\d+): (.+)$`)
+var yamlErrorValueRe = regexp.MustCompile("`([^`]*)`")
+var yamlErrorUnmarshalRe = regexp.MustCompile("^cannot unmarshal (!!\\w+)(?: `([^`]*)`)? into (.+)$")
+
+// yamlTagDescriptions describe the kinds of yaml values in a friendly way
+var yamlTagDescriptions = map[string]string{
+	"!!str":       "a string",
+	"!!int":       "a number",
+	"!!float":     "a number",
+	"!!bool":      "a boolean",
+	"!!null":      "nothing",
+	"!!seq":       "a list",
+	"!!map":       "a mapping",
+	"!!timestamp": "a date",
+}
+
+// goTypeDescriptions describe the types that yaml values are decoded into in a
+// friendly way, as the names of the types themselves mean nothing to users
+var goTypeDescriptions = map[string]string{
+	"main.config":                      "a mapping of repositories and settings",
+	"main.repositories":                "a mapping of repositories to their groups",
+	"map[string]main.repositoryGroups": "a mapping of repositories to their groups",
+	"map[string]main.group":            "a mapping of group names to their reviewers",
+	"main.group":                       "a list of reviewers, or a mapping with the details of a group",
+	"main.rawGroup":                    "a list of reviewers, or a mapping with the details of a group",
+	"[]main.reviewer":                  "a list of reviewers",
+	"main.reviewer":                    "a handle, or a mapping with the details of a reviewer",
+	"main.rawReviewer":                 "a handle, or a mapping with the details of a reviewer",
+	"main.settings":                    "a mapping of settings",
+	"string":                           "a string",
+	"int":                              "a number",
+	"bool":                             "true or false",
+	"[]string":                         "a list of strings",
+}
+
+// describeGoType returns a friendly description of the type that a yaml value
+// was being decoded into
+func describeGoType(t string) string {
+	t = strings.TrimLeft(t, "*")
+
+	if desc, ok := goTypeDescriptions[t]; ok {
+		return desc
+	}
+
+	switch {
+	case strings.HasPrefix(t, "[]"):
+		return "a list"
+	case strings.HasPrefix(t, "map["), strings.HasPrefix(t, "main."):
+		return "a mapping"
+	}
+
+	return "a " + t
+}
+
+// describeYAMLMessage rewrites messages about values being of the wrong type
+// to not mention the internal types that they were being decoded into
+func describeYAMLMessage(message string) string {
+	matches := yamlErrorUnmarshalRe.FindStringSubmatch(message)
+
+	if matches == nil {
+		return message
+	}
+
+	got, ok := yamlTagDescriptions[matches[1]]
+
+	if !ok {
+		got = matches[1]
+	}
+
+	if matches[2] != "" {
+		got = fmt.Sprintf("%s (`%s`)", got, matches[2])
+	}
+
+	return fmt.Sprintf("expected %s, but got %s", describeGoType(matches[3]), got)
+}
+
+type yamlProblem struct {
+	line    int
+	column  int
+	message string
+}
+
+// yamlSourceError is an error that occurred while parsing a yaml document,
+// which includes the source so that the offending lines can be shown with
+// some surrounding context rather than just a line number
+type yamlSourceError struct {
+	file     string
+	lines    []string
+	problems []yamlProblem
+	err      error
+}
+
+func (e *yamlSourceError) Error() string {
+	return fmt.Sprintf("could not parse %s:\n%s", e.file, e.describeProblems())
+}
+
+// describeProblems renders each of the problems along with a snippet of the
+// lines around where they are
+func (e *yamlSourceError) describeProblems() string {
+	var sb strings.Builder
+
+	for _, problem := range e.problems {
+		fmt.Fprintf(&sb, "\n  line %d, column %d: %s\n\n", problem.line, problem.column, problem.message)
+		sb.WriteString(e.snippet(problem))
+	}
+
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+func (e *yamlSourceError) Unwrap() error {
+	return e.err
+}
+
+// snippet renders the lines around the given problem with a gutter, along with
+// a caret pointing at the column the problem was found at
+func (e *yamlSourceError) snippet(problem yamlProblem) string {
+	var sb strings.Builder
+
+	start := max(problem.line-yamlSnippetContextLines, 1)
+	end := min(problem.line+yamlSnippetContextLines, len(e.lines))
+	width := len(strconv.Itoa(end))
+
+	for i := start; i <= end; i++ {
+		line := e.lines[i-1]
+
+		fmt.Fprintf(&sb, "  %*d | %s\n", width, i, line)
+
+		if i == problem.line {
+			fmt.Fprintf(&sb, "  %*s | %s^\n", width, "", caretPadding(line, problem.column))
+		}
+	}
+
+	return sb.String()
+}
+
+// caretPadding builds the whitespace needed to position a caret under the
+// given column of the line, preserving any tabs so that it lines up visually
+func caretPadding(line string, column int) string {
+	var sb strings.Builder
+
+	for i, r := range []rune(line) {
+		if i >= column-1 {
+			break
+		}
+
+		if r == '\t' {
+			sb.WriteRune('\t')
+		} else {
+			sb.WriteRune(' ')
+		}
+	}
+
+	return sb.String()
+}
+
+// firstNonSpaceColumn returns the column of the first character on the line
+// that is not whitespace, which is the best guess we have for where a problem
+// is when yaml does not tell us
+func firstNonSpaceColumn(line string) int {
+	for i, r := range []rune(line) {
+		if r != ' ' && r != '\t' {
+			return i + 1
+		}
+	}
+
+	return 1
+}
+
+// nodesOnLine collects all the nodes within the tree that start on the given line
+func nodesOnLine(node *yaml.Node, line int) []*yaml.Node {
+	var nodes []*yaml.Node
+
+	if node.Line == line {
+		nodes = append(nodes, node)
+	}
+
+	for _, child := range node.Content {
+		nodes = append(nodes, nodesOnLine(child, line)...)
+	}
+
+	return nodes
+}
+
+// findNodeColumn attempts to find the column of the node on the given line,
+// preferring the node whose value matches the one mentioned in the error, and
+// then the node of the kind mentioned in the error
+func findNodeColumn(root *yaml.Node, line int, value, tag string) (int, bool) {
+	nodes := nodesOnLine(root, line)
+
+	if len(nodes) == 0 {
+		return 0, false
+	}
+
+	for _, node := range nodes {
+		if value != "" && node.Value == value {
+			return node.Column, true
+		}
+	}
+
+	// nodes are collected parents first, and it's the innermost value that will
+	// be the one with the wrong type
+	for i := len(nodes) - 1; i >= 0; i-- {
+		if tag != "" && nodes[i].Tag == tag {
+			return nodes[i].Column, true
+		}
+	}
+
+	return nodes[0].Column, true
+}
+
+// describeYAMLError converts an error returned by yaml.v3 into one that includes
+// a snippet of the source the error relates to; if the error does not reference
+// any line numbers, it is returned as-is
+func describeYAMLError(file string, source []byte, err error) error {
+	var messages []string
+
+	var typeErr *yaml.TypeError
+
+	if errors.As(err, &typeErr) {
+		messages = typeErr.Errors
+	} else {
+		messages = []string{err.Error()}
+	}
+
+	lines := strings.Split(strings.ReplaceAll(string(source), "\r\n", "\n"), "\n")
+
+	var root yaml.Node
+
+	// this will fail for syntax errors, in which case we'll be guessing the column
+	_ = yaml.Unmarshal(source, &root)
+
+	problems := make([]yamlProblem, 0, len(messages))
+
+	for _, message := range messages {
+		matches := yamlErrorLineRe.FindStringSubmatch(message)
+
+		if matches == nil {
+			return err
+		}
+
+		line, _ := strconv.Atoi(matches[1])
+
+		if line < 1 || line > len(lines) {
+			return err
+		}
+
+		value, tag := "", ""
+
+		if m := yamlErrorValueRe.FindStringSubmatch(matches[2]); m != nil {
+			value = m[1]
+		}
+
+		if m := yamlErrorUnmarshalRe.FindStringSubmatch(matches[2]); m != nil {
+			tag = m[1]
+		}
+
+		column, ok := findNodeColumn(&root, line, value, tag)
+
+		if !ok {
+			column = firstNonSpaceColumn(lines[line-1])
+		}
+
+		problems = append(problems, yamlProblem{line: line, column: column, message: describeYAMLMessage(matches[2])})
+	}
+
+	return &yamlSourceError{file: file, lines: lines, problems: problems, err: err}
+}